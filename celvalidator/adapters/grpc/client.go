@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/gdbranco/celvalidator"
+	"github.com/gdbranco/celvalidator/adapters/grpc/rulepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultTimeout bounds a call to the rule service before falling back
+const defaultTimeout = 2 * time.Second
+
+// Client validates objects through a gRPC rule service, falling back to
+// in-process evaluation when the service is unreachable or fails, like
+// celvalidator.RuleServiceClient
+type Client struct {
+	RuleService rulepb.RuleServiceClient
+	// Timeout bounds calls without a deadline of their own, 0 meaning none
+	Timeout time.Duration
+	// Fallback and Rules evaluate requests locally when the service is
+	// unavailable; a nil Fallback reports celvalidator.ErrServiceUnavailable
+	// instead
+	Fallback *celvalidator.Validator
+	Rules    celvalidator.RuleSetMap
+}
+
+// NewClient creates a client of the rule service reached through conn, e.g. a
+// *grpc.ClientConn, falling back to fallback and rules
+func NewClient(conn grpc.ClientConnInterface, fallback *celvalidator.Validator, rules celvalidator.RuleSetMap) *Client {
+	return &Client{
+		RuleService: rulepb.NewRuleServiceClient(conn),
+		Timeout:     defaultTimeout,
+		Fallback:    fallback,
+		Rules:       rules,
+	}
+}
+
+// Validate validates the JSON encoding of obj against the rules of its struct
+func (c *Client) Validate(ctx context.Context, obj any, operation string) ([]celvalidator.ValidationResult, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling object: %w", err)
+	}
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return c.ValidateJSON(ctx, celvalidator.ServiceRequest{StructName: t.Name(), Operation: operation, Object: data})
+}
+
+// ValidateJSON sends req to the rule service. Calls failing with Unavailable,
+// DeadlineExceeded, Internal or Unknown fall back to in-process evaluation,
+// while rejected requests are returned as gRPC status errors.
+func (c *Client) ValidateJSON(ctx context.Context, req celvalidator.ServiceRequest) ([]celvalidator.ValidationResult, error) {
+	pbReq, err := toRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	resp, err := c.RuleService.Validate(ctx, pbReq)
+	if err != nil {
+		if !unavailable(err) {
+			return nil, err
+		}
+		if c.Fallback == nil {
+			return nil, fmt.Errorf("%w: %w", celvalidator.ErrServiceUnavailable, err)
+		}
+		return celvalidator.ValidateServiceRequest(c.Fallback, c.Rules, req)
+	}
+
+	metadata := celvalidator.ValidationMetadata{StructName: req.StructName, Operation: req.Operation, RuleIndex: -1, Annotations: req.Annotations}
+	results := make([]celvalidator.ValidationResult, 0, len(resp.GetResults()))
+	for _, r := range resp.GetResults() {
+		results = append(results, fromResult(r).Result(metadata))
+	}
+	return results, nil
+}
+
+// unavailable reports whether err is a failure of the service rather than a
+// rejection of the request
+func unavailable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"fmt"
+
+	"github.com/gdbranco/celvalidator"
+	"github.com/gdbranco/celvalidator/adapters/grpc/rulepb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// toRequest converts req to its protobuf form, its JSON object becoming a
+// google.protobuf.Struct
+func toRequest(req celvalidator.ServiceRequest) (*rulepb.ValidateRequest, error) {
+	var object structpb.Struct
+	if err := protojson.Unmarshal(req.Object, &object); err != nil {
+		return nil, fmt.Errorf("converting object: %w", err)
+	}
+	return &rulepb.ValidateRequest{
+		Struct:      req.StructName,
+		Operation:   req.Operation,
+		Object:      &object,
+		Annotations: req.Annotations,
+	}, nil
+}
+
+// fromRequest converts req back to a ServiceRequest
+func fromRequest(req *rulepb.ValidateRequest) (celvalidator.ServiceRequest, error) {
+	object := req.GetObject()
+	if object == nil {
+		object = &structpb.Struct{}
+	}
+	data, err := protojson.Marshal(object)
+	if err != nil {
+		return celvalidator.ServiceRequest{}, fmt.Errorf("converting object: %w", err)
+	}
+	return celvalidator.ServiceRequest{
+		StructName:  req.GetStruct(),
+		Operation:   req.GetOperation(),
+		Object:      data,
+		Annotations: req.GetAnnotations(),
+	}, nil
+}
+
+func toResult(r celvalidator.ServiceResult) *rulepb.Result {
+	parentIndexes := make([]int32, 0, len(r.ParentIndexes))
+	for _, i := range r.ParentIndexes {
+		parentIndexes = append(parentIndexes, int32(i))
+	}
+	return &rulepb.Result{
+		Id:            r.ID,
+		Rule:          r.Rule,
+		Passed:        r.Passed,
+		Message:       r.Message,
+		Error:         r.Error,
+		Severity:      string(r.Severity),
+		Weight:        r.Weight,
+		Outcome:       string(r.Outcome),
+		Cost:          r.Cost,
+		FieldPaths:    r.FieldPaths,
+		Suggestion:    r.Suggestion,
+		DocUrl:        r.DocURL,
+		Owner:         r.Owner,
+		Description:   r.Description,
+		Annotations:   r.Annotations,
+		Deprecated:    r.Deprecated,
+		ChainPath:     r.ChainPath,
+		RuleIndex:     int32(r.RuleIndex),
+		ParentRule:    r.ParentRule,
+		ParentRuleId:  r.ParentRuleID,
+		ParentIndexes: parentIndexes,
+	}
+}
+
+func fromResult(r *rulepb.Result) celvalidator.ServiceResult {
+	var parentIndexes []int
+	for _, i := range r.GetParentIndexes() {
+		parentIndexes = append(parentIndexes, int(i))
+	}
+	return celvalidator.ServiceResult{
+		ID:            r.GetId(),
+		Rule:          r.GetRule(),
+		Passed:        r.GetPassed(),
+		Message:       r.GetMessage(),
+		Error:         r.GetError(),
+		Severity:      celvalidator.Severity(r.GetSeverity()),
+		Weight:        r.GetWeight(),
+		Outcome:       celvalidator.Outcome(r.GetOutcome()),
+		Cost:          r.GetCost(),
+		FieldPaths:    r.GetFieldPaths(),
+		Suggestion:    r.GetSuggestion(),
+		DocURL:        r.GetDocUrl(),
+		Owner:         r.GetOwner(),
+		Description:   r.GetDescription(),
+		Annotations:   r.GetAnnotations(),
+		Deprecated:    r.GetDeprecated(),
+		ChainPath:     r.GetChainPath(),
+		RuleIndex:     int(r.GetRuleIndex()),
+		ParentRule:    r.GetParentRule(),
+		ParentRuleID:  r.GetParentRuleId(),
+		ParentIndexes: parentIndexes,
+	}
+}
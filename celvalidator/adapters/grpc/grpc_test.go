@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/gdbranco/celvalidator"
+	"github.com/gdbranco/celvalidator/adapters/grpc/rulepb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestGRPC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "gRPC Suite")
+}
+
+type Sample struct {
+	Age    int
+	Email  string
+	Active bool
+}
+
+// serve serves srv on a local port, returning a connection to it
+func serve(srv rulepb.RuleServiceServer) *grpc.ClientConn {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+	s := grpc.NewServer()
+	rulepb.RegisterRuleServiceServer(s, srv)
+	go s.Serve(lis)
+	DeferCleanup(s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	Expect(err).To(BeNil())
+	DeferCleanup(conn.Close)
+	return conn
+}
+
+// unavailableServer fails every call with Unavailable
+type unavailableServer struct {
+	rulepb.UnimplementedRuleServiceServer
+}
+
+func (unavailableServer) Validate(context.Context, *rulepb.ValidateRequest) (*rulepb.ValidateResponse, error) {
+	return nil, status.Error(codes.Unavailable, "overloaded")
+}
+
+var _ = Describe("Rule service", func() {
+	rules := celvalidator.RuleSetMap{"Sample": {"Create": {
+		{ID: "adult", Rule: "Age >= 18", Enabled: true, FailureMessage: "too young", Suggestion: "ask a guardian", DocURL: "https://docs.example.com/adult",
+			Owner: "identity", Description: "members must be adults",
+			Annotations: map[string]string{"runbook": "https://runbooks.example.com/adult"}, Deprecated: true, Weight: 2},
+		{ID: "active", Rule: "Active", Enabled: true, Then: []celvalidator.RuleEntry{
+			{Rule: "Email.endsWith('.com')", Enabled: true, Severity: celvalidator.SeverityWarning},
+		}},
+		{Rule: "Unknown == 1", Enabled: true},
+	}}}
+	v := celvalidator.NewValidator(celvalidator.WithPartialEval())
+
+	It("returns the results of a local validation", func() {
+		conn := serve(NewServer(v, rules))
+
+		obj := Sample{Age: 10, Active: true, Email: "a@b.org"}
+		remote, err := NewClient(conn, nil, nil).Validate(context.Background(), obj, "Create")
+		Expect(err).To(BeNil())
+
+		data, err := json.Marshal(obj)
+		Expect(err).To(BeNil())
+		local, err := v.ValidateJSON(data, celvalidator.GetRulesForStruct("Sample", "Create", rules),
+			celvalidator.ValidationMetadata{StructName: "Sample", Operation: "Create", RuleIndex: -1})
+		Expect(err).To(BeNil())
+
+		// errors travel as their message, issues and suggested values stay local
+		wire := func(results []celvalidator.ValidationResult) []celvalidator.ValidationResult {
+			out := slices.Clone(results)
+			for i := range out {
+				if out[i].Error != nil {
+					out[i].Error = errors.New(out[i].Error.Error())
+				}
+				out[i].Issues = nil
+			}
+			return out
+		}
+		Expect(remote).To(HaveLen(4))
+		Expect(remote).To(Equal(wire(local)))
+		Expect(remote[0].Message).To(Equal("too young"))
+		Expect(remote[2].Metadata.ParentRuleID).To(Equal("active"))
+	})
+
+	It("serves generated clients with summary headers as metadata", func() {
+		conn := serve(NewServer(v, rules))
+
+		object, err := structpb.NewStruct(map[string]any{"Age": 30, "Active": false})
+		Expect(err).To(BeNil())
+		var header metadata.MD
+		resp, err := rulepb.NewRuleServiceClient(conn).Validate(context.Background(),
+			&rulepb.ValidateRequest{Struct: "Sample", Operation: "Create", Object: object}, grpc.Header(&header))
+		Expect(err).To(BeNil())
+
+		Expect(header.Get(celvalidator.HeaderFailedCount)).To(Equal([]string{"2"}))
+		Expect(resp.GetResults()).To(HaveLen(3))
+		Expect(resp.GetResults()[0].GetId()).To(Equal("adult"))
+		Expect(resp.GetResults()[0].GetPassed()).To(BeTrue())
+		Expect(resp.GetResults()[0].GetAnnotations()).To(HaveKeyWithValue("runbook", "https://runbooks.example.com/adult"))
+		Expect(resp.GetResults()[1].GetPassed()).To(BeFalse())
+		Expect(resp.GetResults()[2].GetOutcome()).To(Equal(string(celvalidator.OutcomeError)))
+	})
+
+	It("answers unknown structs with NotFound", func() {
+		conn := serve(NewServer(v, rules))
+
+		_, err := NewClient(conn, v, rules).ValidateJSON(context.Background(), celvalidator.ServiceRequest{StructName: "Unknown", Object: []byte(`{}`)})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+		Expect(status.Convert(err).Message()).To(Equal(`no rules for struct "Unknown"`))
+		Expect(errors.Is(err, celvalidator.ErrServiceUnavailable)).To(BeFalse())
+	})
+
+	It("falls back to in-process evaluation when the service is unavailable", func() {
+		conn := serve(unavailableServer{})
+
+		results, err := NewClient(conn, v, rules).Validate(context.Background(), Sample{Age: 30, Email: "a@b.com"}, "Create")
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].Passed).To(BeTrue())
+
+		_, err = NewClient(conn, nil, nil).Validate(context.Background(), Sample{}, "Create")
+		Expect(errors.Is(err, celvalidator.ErrServiceUnavailable)).To(BeTrue())
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+	})
+
+	It("rejects objects that are not JSON objects", func() {
+		conn := serve(NewServer(v, rules))
+		_, err := NewClient(conn, nil, nil).ValidateJSON(context.Background(), celvalidator.ServiceRequest{StructName: "Sample", Object: []byte(`[1]`)})
+		Expect(err).To(MatchError(ContainSubstring("converting object")))
+	})
+})
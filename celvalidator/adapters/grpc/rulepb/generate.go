@@ -0,0 +1,5 @@
+// Package rulepb holds the messages and gRPC stubs generated from
+// rule_service.proto
+package rulepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative rule_service.proto
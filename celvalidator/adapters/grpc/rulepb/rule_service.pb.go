@@ -0,0 +1,454 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: rule_service.proto
+
+package rulepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ValidateRequest mirrors celvalidator.ServiceRequest.
+type ValidateRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Struct    string                 `protobuf:"bytes,1,opt,name=struct,proto3" json:"struct,omitempty"`
+	Operation string                 `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	// object is evaluated like celvalidator.ValidateJSON. Numbers travel as
+	// doubles, so integers past 2^53 lose precision.
+	Object        *structpb.Struct  `protobuf:"bytes,3,opt,name=object,proto3" json:"object,omitempty"`
+	Annotations   map[string]string `protobuf:"bytes,4,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateRequest) Reset() {
+	*x = ValidateRequest{}
+	mi := &file_rule_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateRequest) ProtoMessage() {}
+
+func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rule_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_rule_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ValidateRequest) GetStruct() string {
+	if x != nil {
+		return x.Struct
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetObject() *structpb.Struct {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *ValidateRequest) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+type ValidateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*Result              `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_rule_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rule_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_rule_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ValidateResponse) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Result mirrors celvalidator.ServiceResult.
+type Result struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Rule          string                 `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+	Passed        bool                   `protobuf:"varint,3,opt,name=passed,proto3" json:"passed,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Severity      string                 `protobuf:"bytes,6,opt,name=severity,proto3" json:"severity,omitempty"`
+	Weight        float64                `protobuf:"fixed64,7,opt,name=weight,proto3" json:"weight,omitempty"`
+	Outcome       string                 `protobuf:"bytes,8,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	Cost          uint64                 `protobuf:"varint,9,opt,name=cost,proto3" json:"cost,omitempty"`
+	FieldPaths    []string               `protobuf:"bytes,10,rep,name=field_paths,json=fieldPaths,proto3" json:"field_paths,omitempty"`
+	Suggestion    string                 `protobuf:"bytes,11,opt,name=suggestion,proto3" json:"suggestion,omitempty"`
+	DocUrl        string                 `protobuf:"bytes,12,opt,name=doc_url,json=docUrl,proto3" json:"doc_url,omitempty"`
+	Owner         string                 `protobuf:"bytes,13,opt,name=owner,proto3" json:"owner,omitempty"`
+	Description   string                 `protobuf:"bytes,14,opt,name=description,proto3" json:"description,omitempty"`
+	Annotations   map[string]string      `protobuf:"bytes,15,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Deprecated    bool                   `protobuf:"varint,16,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	ChainPath     string                 `protobuf:"bytes,17,opt,name=chain_path,json=chainPath,proto3" json:"chain_path,omitempty"`
+	RuleIndex     int32                  `protobuf:"varint,18,opt,name=rule_index,json=ruleIndex,proto3" json:"rule_index,omitempty"`
+	ParentRule    string                 `protobuf:"bytes,19,opt,name=parent_rule,json=parentRule,proto3" json:"parent_rule,omitempty"`
+	ParentRuleId  string                 `protobuf:"bytes,20,opt,name=parent_rule_id,json=parentRuleId,proto3" json:"parent_rule_id,omitempty"`
+	ParentIndexes []int32                `protobuf:"varint,21,rep,packed,name=parent_indexes,json=parentIndexes,proto3" json:"parent_indexes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_rule_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_rule_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_rule_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Result) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Result) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *Result) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *Result) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Result) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Result) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Result) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Result) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *Result) GetCost() uint64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *Result) GetFieldPaths() []string {
+	if x != nil {
+		return x.FieldPaths
+	}
+	return nil
+}
+
+func (x *Result) GetSuggestion() string {
+	if x != nil {
+		return x.Suggestion
+	}
+	return ""
+}
+
+func (x *Result) GetDocUrl() string {
+	if x != nil {
+		return x.DocUrl
+	}
+	return ""
+}
+
+func (x *Result) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Result) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Result) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *Result) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+func (x *Result) GetChainPath() string {
+	if x != nil {
+		return x.ChainPath
+	}
+	return ""
+}
+
+func (x *Result) GetRuleIndex() int32 {
+	if x != nil {
+		return x.RuleIndex
+	}
+	return 0
+}
+
+func (x *Result) GetParentRule() string {
+	if x != nil {
+		return x.ParentRule
+	}
+	return ""
+}
+
+func (x *Result) GetParentRuleId() string {
+	if x != nil {
+		return x.ParentRuleId
+	}
+	return ""
+}
+
+func (x *Result) GetParentIndexes() []int32 {
+	if x != nil {
+		return x.ParentIndexes
+	}
+	return nil
+}
+
+var File_rule_service_proto protoreflect.FileDescriptor
+
+const file_rule_service_proto_rawDesc = "" +
+	"\n" +
+	"\x12rule_service.proto\x12\x0fcelvalidator.v1\x1a\x1cgoogle/protobuf/struct.proto\"\x8d\x02\n" +
+	"\x0fValidateRequest\x12\x16\n" +
+	"\x06struct\x18\x01 \x01(\tR\x06struct\x12\x1c\n" +
+	"\toperation\x18\x02 \x01(\tR\toperation\x12/\n" +
+	"\x06object\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x06object\x12S\n" +
+	"\vannotations\x18\x04 \x03(\v21.celvalidator.v1.ValidateRequest.AnnotationsEntryR\vannotations\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"E\n" +
+	"\x10ValidateResponse\x121\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.celvalidator.v1.ResultR\aresults\"\xc0\x05\n" +
+	"\x06Result\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04rule\x18\x02 \x01(\tR\x04rule\x12\x16\n" +
+	"\x06passed\x18\x03 \x01(\bR\x06passed\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x1a\n" +
+	"\bseverity\x18\x06 \x01(\tR\bseverity\x12\x16\n" +
+	"\x06weight\x18\a \x01(\x01R\x06weight\x12\x18\n" +
+	"\aoutcome\x18\b \x01(\tR\aoutcome\x12\x12\n" +
+	"\x04cost\x18\t \x01(\x04R\x04cost\x12\x1f\n" +
+	"\vfield_paths\x18\n" +
+	" \x03(\tR\n" +
+	"fieldPaths\x12\x1e\n" +
+	"\n" +
+	"suggestion\x18\v \x01(\tR\n" +
+	"suggestion\x12\x17\n" +
+	"\adoc_url\x18\f \x01(\tR\x06docUrl\x12\x14\n" +
+	"\x05owner\x18\r \x01(\tR\x05owner\x12 \n" +
+	"\vdescription\x18\x0e \x01(\tR\vdescription\x12J\n" +
+	"\vannotations\x18\x0f \x03(\v2(.celvalidator.v1.Result.AnnotationsEntryR\vannotations\x12\x1e\n" +
+	"\n" +
+	"deprecated\x18\x10 \x01(\bR\n" +
+	"deprecated\x12\x1d\n" +
+	"\n" +
+	"chain_path\x18\x11 \x01(\tR\tchainPath\x12\x1d\n" +
+	"\n" +
+	"rule_index\x18\x12 \x01(\x05R\truleIndex\x12\x1f\n" +
+	"\vparent_rule\x18\x13 \x01(\tR\n" +
+	"parentRule\x12$\n" +
+	"\x0eparent_rule_id\x18\x14 \x01(\tR\fparentRuleId\x12%\n" +
+	"\x0eparent_indexes\x18\x15 \x03(\x05R\rparentIndexes\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012^\n" +
+	"\vRuleService\x12O\n" +
+	"\bValidate\x12 .celvalidator.v1.ValidateRequest\x1a!.celvalidator.v1.ValidateResponseB7Z5github.com/gdbranco/celvalidator/adapters/grpc/rulepbb\x06proto3"
+
+var (
+	file_rule_service_proto_rawDescOnce sync.Once
+	file_rule_service_proto_rawDescData []byte
+)
+
+func file_rule_service_proto_rawDescGZIP() []byte {
+	file_rule_service_proto_rawDescOnce.Do(func() {
+		file_rule_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rule_service_proto_rawDesc), len(file_rule_service_proto_rawDesc)))
+	})
+	return file_rule_service_proto_rawDescData
+}
+
+var file_rule_service_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_rule_service_proto_goTypes = []any{
+	(*ValidateRequest)(nil),  // 0: celvalidator.v1.ValidateRequest
+	(*ValidateResponse)(nil), // 1: celvalidator.v1.ValidateResponse
+	(*Result)(nil),           // 2: celvalidator.v1.Result
+	nil,                      // 3: celvalidator.v1.ValidateRequest.AnnotationsEntry
+	nil,                      // 4: celvalidator.v1.Result.AnnotationsEntry
+	(*structpb.Struct)(nil),  // 5: google.protobuf.Struct
+}
+var file_rule_service_proto_depIdxs = []int32{
+	5, // 0: celvalidator.v1.ValidateRequest.object:type_name -> google.protobuf.Struct
+	3, // 1: celvalidator.v1.ValidateRequest.annotations:type_name -> celvalidator.v1.ValidateRequest.AnnotationsEntry
+	2, // 2: celvalidator.v1.ValidateResponse.results:type_name -> celvalidator.v1.Result
+	4, // 3: celvalidator.v1.Result.annotations:type_name -> celvalidator.v1.Result.AnnotationsEntry
+	0, // 4: celvalidator.v1.RuleService.Validate:input_type -> celvalidator.v1.ValidateRequest
+	1, // 5: celvalidator.v1.RuleService.Validate:output_type -> celvalidator.v1.ValidateResponse
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_rule_service_proto_init() }
+func file_rule_service_proto_init() {
+	if File_rule_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rule_service_proto_rawDesc), len(file_rule_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rule_service_proto_goTypes,
+		DependencyIndexes: file_rule_service_proto_depIdxs,
+		MessageInfos:      file_rule_service_proto_msgTypes,
+	}.Build()
+	File_rule_service_proto = out.File
+	file_rule_service_proto_goTypes = nil
+	file_rule_service_proto_depIdxs = nil
+}
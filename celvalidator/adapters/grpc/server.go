@@ -0,0 +1,69 @@
+// Package grpc serves the rule service of celvalidator.NewRuleService over
+// gRPC, as the celvalidator.v1.RuleService of rulepb/rule_service.proto
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gdbranco/celvalidator"
+	"github.com/gdbranco/celvalidator/adapters/grpc/rulepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the RuleService, evaluating requests like
+// celvalidator.NewRuleService. Register it with Register or
+// rulepb.RegisterRuleServiceServer.
+type Server struct {
+	rulepb.UnimplementedRuleServiceServer
+	v     *celvalidator.Validator
+	rules celvalidator.RuleSetMap
+}
+
+// NewServer creates a RuleService evaluating rules with v
+func NewServer(v *celvalidator.Validator, rules celvalidator.RuleSetMap) *Server {
+	return &Server{v: v, rules: rules}
+}
+
+// Register registers a RuleService evaluating rules with v on s
+func Register(s grpc.ServiceRegistrar, v *celvalidator.Validator, rules celvalidator.RuleSetMap) {
+	rulepb.RegisterRuleServiceServer(s, NewServer(v, rules))
+}
+
+// Validate evaluates the object of req against the rules of its struct and
+// operation, sending summary headers as header metadata. Unknown structs are
+// answered with NotFound and objects failing to evaluate with InvalidArgument.
+func (s *Server) Validate(ctx context.Context, req *rulepb.ValidateRequest) (*rulepb.ValidateResponse, error) {
+	serviceReq, err := fromRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, ok := s.rules[serviceReq.StructName]; !ok {
+		return nil, status.Errorf(codes.NotFound, "no rules for struct %q", serviceReq.StructName)
+	}
+
+	results, err := celvalidator.ValidateServiceRequest(s.v, s.rules, serviceReq)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	header := http.Header{}
+	celvalidator.SetSummaryHeaders(header, results)
+	md := metadata.MD{}
+	for name, values := range header {
+		md.Append(strings.ToLower(name), values...)
+	}
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		return nil, err
+	}
+
+	resp := &rulepb.ValidateResponse{Results: make([]*rulepb.Result, 0, len(results))}
+	for _, r := range results {
+		resp.Results = append(resp.Results, toResult(celvalidator.NewServiceResult(r)))
+	}
+	return resp, nil
+}
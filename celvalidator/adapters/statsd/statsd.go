@@ -0,0 +1,106 @@
+// Package statsd sends validation metrics to a StatsD or Datadog agent,
+// recorded with celvalidator.WithMetrics
+package statsd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gdbranco/celvalidator"
+)
+
+// packetSize bounds the datagrams sent to the agent, below common MTUs
+const packetSize = 1432
+
+// Metrics sends validation metrics to a StatsD or Datadog agent with
+// DogStatsD tags:
+//   - <prefix>rule.evaluations, a counter per rule tagged with struct,
+//     operation, rule_id for rules with an ID, and outcome
+//   - <prefix>rule.cost, a histogram of the CEL cost of rules with the same tags
+//   - <prefix>validation.duration, a timing tagged with struct and operation
+//
+// Metrics are sent over UDP, delivery errors being dropped as they must not
+// fail validations.
+type Metrics struct {
+	w      io.Writer
+	prefix string
+	tags   []string
+}
+
+// Option configures a Metrics
+type Option func(*Metrics)
+
+// WithPrefix prefixes the metric names, celvalidator. by default
+func WithPrefix(prefix string) Option {
+	return func(m *Metrics) {
+		m.prefix = prefix
+	}
+}
+
+// WithTags adds constant tags to every metric, e.g. env:prod
+func WithTags(tags ...string) Option {
+	return func(m *Metrics) {
+		m.tags = append(m.tags, tags...)
+	}
+}
+
+// New sends metrics to the agent at addr, e.g. localhost:8125
+func New(addr string, opts ...Option) (*Metrics, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dialing statsd agent: %w", err)
+	}
+	return newMetrics(conn, opts...), nil
+}
+
+func newMetrics(w io.Writer, opts ...Option) *Metrics {
+	m := &Metrics{w: w, prefix: "celvalidator."}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// RecordValidation sends the metrics of a validation, several per datagram
+func (m *Metrics) RecordValidation(metadata celvalidator.ValidationMetadata, results []celvalidator.ValidationResult, elapsed time.Duration) {
+	var packet bytes.Buffer
+	send := func(line string) {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > packetSize {
+			_, _ = m.w.Write(packet.Bytes())
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+
+	validationTags := m.tagList("struct:"+metadata.StructName, "operation:"+metadata.Operation)
+	for _, r := range results {
+		tags := []string{"struct:" + r.Metadata.StructName, "operation:" + r.Metadata.Operation, "outcome:" + string(r.Outcome)}
+		if r.ID != "" {
+			tags = append(tags, "rule_id:"+r.ID)
+		}
+		ruleTags := m.tagList(tags...)
+		send(fmt.Sprintf("%srule.evaluations:1|c|#%s", m.prefix, ruleTags))
+		send(fmt.Sprintf("%srule.cost:%d|h|#%s", m.prefix, r.Cost, ruleTags))
+	}
+	send(fmt.Sprintf("%svalidation.duration:%g|ms|#%s", m.prefix, float64(elapsed)/float64(time.Millisecond), validationTags))
+	_, _ = m.w.Write(packet.Bytes())
+}
+
+// tagList joins the constant tags and tags, replacing the characters
+// DogStatsD reserves in tag values
+func (m *Metrics) tagList(tags ...string) string {
+	all := append(append(make([]string, 0, len(m.tags)+len(tags)), m.tags...), tags...)
+	for i, tag := range all {
+		all[i] = tagReplacer.Replace(tag)
+	}
+	return strings.Join(all, ",")
+}
+
+var tagReplacer = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_")
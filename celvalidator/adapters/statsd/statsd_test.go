@@ -0,0 +1,83 @@
+package statsd
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdbranco/celvalidator"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestStatsD(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "StatsD Suite")
+}
+
+type Sample struct {
+	Age   int
+	Email string
+}
+
+type packetRecorder struct {
+	packets []string
+}
+
+func (r *packetRecorder) Write(p []byte) (int, error) {
+	r.packets = append(r.packets, string(p))
+	return len(p), nil
+}
+
+var _ = Describe("Metrics", func() {
+	md := celvalidator.ValidationMetadata{StructName: "Sample", Operation: "Create"}
+	rules := []celvalidator.RuleEntry{
+		{ID: "adult", Rule: "Age >= 18", Enabled: true},
+		{Rule: "Email != ''", Enabled: true},
+	}
+
+	It("sends rule counters and costs with tags and the validation duration", func() {
+		recorder := &packetRecorder{}
+		m := newMetrics(recorder, WithPrefix("app."), WithTags("env:prod"))
+		_, err := celvalidator.NewValidator(celvalidator.WithPartialEval(), celvalidator.WithMetrics(m)).Validate(Sample{Age: 20}, rules, md)
+		Expect(err).To(BeNil())
+
+		Expect(recorder.packets).To(HaveLen(1))
+		lines := strings.Split(recorder.packets[0], "\n")
+		Expect(lines).To(HaveLen(5))
+		Expect(lines[0]).To(Equal("app.rule.evaluations:1|c|#env:prod,struct:Sample,operation:Create,outcome:passed,rule_id:adult"))
+		Expect(lines[1]).To(MatchRegexp(`^app\.rule\.cost:\d+\|h\|#env:prod,struct:Sample,operation:Create,outcome:passed,rule_id:adult$`))
+		Expect(lines[2]).To(Equal("app.rule.evaluations:1|c|#env:prod,struct:Sample,operation:Create,outcome:failed"))
+		Expect(lines[4]).To(MatchRegexp(`^app\.validation\.duration:[\d.e-]+\|ms\|#env:prod,struct:Sample,operation:Create$`))
+	})
+
+	It("splits datagrams and escapes reserved characters", func() {
+		recorder := &packetRecorder{}
+		m := newMetrics(recorder, WithTags("team:a,b|c"))
+		results := make([]celvalidator.ValidationResult, 40)
+		m.RecordValidation(md, results, time.Millisecond)
+		Expect(len(recorder.packets)).To(BeNumerically(">", 1))
+		for _, p := range recorder.packets {
+			Expect(len(p)).To(BeNumerically("<=", packetSize))
+		}
+		Expect(recorder.packets[0]).To(HavePrefix("celvalidator.rule.evaluations:1|c|#team:a_b_c,"))
+	})
+
+	It("sends to an agent over UDP", func() {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		DeferCleanup(conn.Close)
+
+		m, err := New(conn.LocalAddr().String())
+		Expect(err).To(BeNil())
+		m.RecordValidation(md, nil, time.Millisecond)
+
+		buf := make([]byte, packetSize)
+		Expect(conn.SetReadDeadline(time.Now().Add(time.Second))).To(Succeed())
+		n, _, err := conn.ReadFrom(buf)
+		Expect(err).To(BeNil())
+		Expect(bytes.HasPrefix(buf[:n], []byte("celvalidator.validation.duration:1|ms|"))).To(BeTrue())
+	})
+})
@@ -0,0 +1,398 @@
+package celvalidator
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrQueueFull is returned by TrySubmit when the queue has no free slot
+	ErrQueueFull = errors.New("validation queue full")
+	// ErrAsyncValidatorClosed is returned when submitting to a closed AsyncValidator
+	ErrAsyncValidatorClosed = errors.New("async validator closed")
+)
+
+// ValidationJob is a single validation request for the AsyncValidator
+type ValidationJob struct {
+	// Tenant is the tenant the job is scheduled for, jobs of all tenants
+	// being served in turn
+	Tenant   string
+	Object   any
+	Rules    []RuleEntry
+	Metadata ValidationMetadata
+}
+
+// AsyncResult is the outcome of a ValidationJob
+type AsyncResult struct {
+	Results []ValidationResult
+	Err     error
+}
+
+// AsyncStats is a snapshot of the AsyncValidator queue
+type AsyncStats struct {
+	QueueDepth    int
+	QueueCapacity int
+	InFlight      int
+	Completed     uint64
+	Rejected      uint64
+	// Tenants holds the metrics of every tenant with queued or in-flight jobs,
+	// and of up to maxTenantCounters tenants in total
+	Tenants map[string]TenantStats
+}
+
+// TenantStats is a snapshot of the queue metrics of a tenant, Completed and
+// Rejected counting since the validator was created
+type TenantStats struct {
+	QueueDepth int
+	InFlight   int
+	Completed  uint64
+	Rejected   uint64
+}
+
+type asyncJob struct {
+	job    ValidationJob
+	result chan AsyncResult
+}
+
+// tenantQueue holds the pending jobs of a tenant, forgotten once it has
+// neither queued nor in-flight jobs
+type tenantQueue struct {
+	jobs     []asyncJob
+	inFlight int
+}
+
+// maxTenantCounters bounds the tenants whose counters are kept, idle tenants
+// being evicted to make room
+const maxTenantCounters = 1024
+
+// tenantCounters holds the cumulative counters of a tenant, kept apart from
+// its queue so they outlive it
+type tenantCounters struct {
+	completed uint64
+	rejected  uint64
+}
+
+// AsyncValidator runs validations on a bounded queue served by a worker pool.
+// Workers take jobs from tenants in turn, so a tenant's large batch delays
+// other tenants by at most one job per tenant.
+type AsyncValidator struct {
+	validator         *Validator
+	workers           int
+	queueSize         int
+	tenantConcurrency int
+	tenantQueueSize   int
+
+	mu      sync.Mutex
+	ready   *sync.Cond
+	closed  bool
+	queued  int
+	idle    int
+	tenants map[string]*tenantQueue
+	// order lists tenants in turn order, next modulo its length being the
+	// tenant served next
+	order []string
+	next  int
+	// counters holds the counters of active and recently active tenants
+	counters map[string]*tenantCounters
+	// space is closed and replaced whenever there may be room for a job
+	space chan struct{}
+	wg    sync.WaitGroup
+
+	inFlight  int
+	completed uint64
+	rejected  uint64
+}
+
+type AsyncOption func(*AsyncValidator)
+
+// WithWorkers sets the number of workers serving the queue
+func WithWorkers(n int) AsyncOption {
+	return func(a *AsyncValidator) {
+		if n > 0 {
+			a.workers = n
+		}
+	}
+}
+
+// WithQueueSize sets the number of jobs that can wait for a worker
+func WithQueueSize(n int) AsyncOption {
+	return func(a *AsyncValidator) {
+		if n >= 0 {
+			a.queueSize = n
+		}
+	}
+}
+
+// WithTenantConcurrency caps the workers serving a single tenant at once
+func WithTenantConcurrency(n int) AsyncOption {
+	return func(a *AsyncValidator) {
+		if n > 0 {
+			a.tenantConcurrency = n
+		}
+	}
+}
+
+// WithTenantQueueSize caps the jobs of a single tenant waiting for a worker,
+// by default half the queue size so no tenant fills the queue alone
+func WithTenantQueueSize(n int) AsyncOption {
+	return func(a *AsyncValidator) {
+		if n > 0 {
+			a.tenantQueueSize = n
+		}
+	}
+}
+
+// NewAsyncValidator creates an AsyncValidator and starts its workers
+func NewAsyncValidator(v *Validator, opts ...AsyncOption) *AsyncValidator {
+	a := &AsyncValidator{
+		validator: v,
+		workers:   1,
+		queueSize: 64,
+		tenants:   map[string]*tenantQueue{},
+		counters:  map[string]*tenantCounters{},
+		space:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.tenantQueueSize == 0 && a.queueSize > 1 {
+		a.tenantQueueSize = a.queueSize / 2
+	}
+	a.ready = sync.NewCond(&a.mu)
+
+	for range a.workers {
+		a.wg.Add(1)
+		go a.work()
+	}
+	return a
+}
+
+func (a *AsyncValidator) work() {
+	defer a.wg.Done()
+	for {
+		j, tenant, ok := a.take()
+		if !ok {
+			return
+		}
+		results, err := a.validator.Validate(j.job.Object, j.job.Rules, j.job.Metadata)
+
+		a.mu.Lock()
+		tenant.inFlight--
+		a.counter(j.job.Tenant).completed++
+		a.inFlight--
+		a.completed++
+		a.prune(j.job.Tenant)
+		// A tenant at its concurrency cap may have become eligible
+		a.ready.Broadcast()
+		a.mu.Unlock()
+
+		j.result <- AsyncResult{Results: results, Err: err}
+		close(j.result)
+	}
+}
+
+// take waits for the next job in tenant turn order, returning false once the
+// validator is closed and drained
+func (a *AsyncValidator) take() (asyncJob, *tenantQueue, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		if j, tenant, ok := a.dequeue(); ok {
+			return j, tenant, true
+		}
+		if a.closed && a.queued == 0 {
+			return asyncJob{}, nil, false
+		}
+		a.idle++
+		// An idle worker makes room in an unbuffered queue
+		a.signalSpace()
+		a.ready.Wait()
+		a.idle--
+	}
+}
+
+// dequeue pops the job of the next tenant with pending jobs under its
+// concurrency cap, a.mu must be held
+func (a *AsyncValidator) dequeue() (asyncJob, *tenantQueue, bool) {
+	for i := range a.order {
+		idx := (a.next + i) % len(a.order)
+		tenant := a.tenants[a.order[idx]]
+		if len(tenant.jobs) == 0 || (a.tenantConcurrency > 0 && tenant.inFlight >= a.tenantConcurrency) {
+			continue
+		}
+
+		j := tenant.jobs[0]
+		tenant.jobs[0] = asyncJob{}
+		tenant.jobs = tenant.jobs[1:]
+		tenant.inFlight++
+		a.queued--
+		a.inFlight++
+		a.next = idx + 1
+		a.signalSpace()
+		return j, tenant, true
+	}
+	return asyncJob{}, nil, false
+}
+
+// signalSpace wakes up blocked submitters, a.mu must be held
+func (a *AsyncValidator) signalSpace() {
+	close(a.space)
+	a.space = make(chan struct{})
+}
+
+// enqueue queues j if there is room, a.mu must be held
+func (a *AsyncValidator) enqueue(j asyncJob) bool {
+	tenant := a.tenant(j.job.Tenant)
+	// An unbuffered queue hands jobs over to idle workers
+	if a.queued >= a.queueSize && (a.queueSize > 0 || a.queued >= a.idle) {
+		return false
+	}
+	if a.tenantQueueSize > 0 && len(tenant.jobs) >= a.tenantQueueSize {
+		return false
+	}
+	tenant.jobs = append(tenant.jobs, j)
+	a.queued++
+	a.ready.Signal()
+	return true
+}
+
+// tenant returns the queue of a tenant, adding it last in turn order, a.mu must be held
+func (a *AsyncValidator) tenant(name string) *tenantQueue {
+	tenant, ok := a.tenants[name]
+	if !ok {
+		tenant = &tenantQueue{}
+		a.tenants[name] = tenant
+		a.order = append(a.order, name)
+		a.counter(name)
+	}
+	return tenant
+}
+
+// counter returns the counters of a tenant, evicting an idle tenant's once
+// maxTenantCounters are kept, a.mu must be held
+func (a *AsyncValidator) counter(name string) *tenantCounters {
+	if c, ok := a.counters[name]; ok {
+		return c
+	}
+	if len(a.counters) >= maxTenantCounters {
+		for idle := range a.counters {
+			if _, active := a.tenants[idle]; !active {
+				delete(a.counters, idle)
+				break
+			}
+		}
+	}
+	c := &tenantCounters{}
+	a.counters[name] = c
+	return c
+}
+
+// prune forgets a tenant with neither queued nor in-flight jobs, so tenants
+// seen once do not accumulate, a.mu must be held
+func (a *AsyncValidator) prune(name string) {
+	tenant, ok := a.tenants[name]
+	if !ok || len(tenant.jobs) > 0 || tenant.inFlight > 0 {
+		return
+	}
+	delete(a.tenants, name)
+	idx := slices.Index(a.order, name)
+	a.order = slices.Delete(a.order, idx, idx+1)
+	if idx < a.next {
+		a.next--
+	}
+}
+
+// reject counts a rejected job, a.mu must be held
+func (a *AsyncValidator) reject(job ValidationJob) {
+	a.rejected++
+	a.counter(job.Tenant).rejected++
+	a.prune(job.Tenant)
+}
+
+// Submit queues a job, blocking until there is room or ctx is done
+func (a *AsyncValidator) Submit(ctx context.Context, job ValidationJob) (<-chan AsyncResult, error) {
+	j := asyncJob{job: job, result: make(chan AsyncResult, 1)}
+	for {
+		a.mu.Lock()
+		if a.closed {
+			a.mu.Unlock()
+			return nil, ErrAsyncValidatorClosed
+		}
+		if a.enqueue(j) {
+			a.mu.Unlock()
+			return j.result, nil
+		}
+		space := a.space
+		a.mu.Unlock()
+
+		select {
+		case <-space:
+		case <-ctx.Done():
+			a.mu.Lock()
+			a.reject(job)
+			a.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// TrySubmit queues a job without blocking, returning ErrQueueFull when there is no room
+func (a *AsyncValidator) TrySubmit(job ValidationJob) (<-chan AsyncResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil, ErrAsyncValidatorClosed
+	}
+
+	j := asyncJob{job: job, result: make(chan AsyncResult, 1)}
+	if !a.enqueue(j) {
+		a.reject(job)
+		return nil, ErrQueueFull
+	}
+	return j.result, nil
+}
+
+// QueueDepth returns the number of jobs waiting for a worker
+func (a *AsyncValidator) QueueDepth() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.queued
+}
+
+// Stats returns a snapshot of the queue metrics
+func (a *AsyncValidator) Stats() AsyncStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := AsyncStats{
+		QueueDepth:    a.queued,
+		QueueCapacity: a.queueSize,
+		InFlight:      a.inFlight,
+		Completed:     a.completed,
+		Rejected:      a.rejected,
+		Tenants:       make(map[string]TenantStats, len(a.counters)),
+	}
+	for name, c := range a.counters {
+		tenant := a.tenants[name]
+		if tenant == nil {
+			tenant = &tenantQueue{}
+		}
+		stats.Tenants[name] = TenantStats{
+			QueueDepth: len(tenant.jobs),
+			InFlight:   tenant.inFlight,
+			Completed:  c.completed,
+			Rejected:   c.rejected,
+		}
+	}
+	return stats
+}
+
+// Close stops accepting jobs and waits for queued jobs to complete
+func (a *AsyncValidator) Close() {
+	a.mu.Lock()
+	a.closed = true
+	a.ready.Broadcast()
+	a.mu.Unlock()
+	a.wg.Wait()
+}
@@ -0,0 +1,200 @@
+package celvalidator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AsyncValidator", func() {
+	var obj Sample
+	var ruleMap RuleSetMap
+
+	BeforeEach(func() {
+		obj = Sample{Age: 21, Email: "test@example.com"}
+		ruleMap = RuleSetMap{
+			"Sample": {"Create": {{Rule: "Age > 18", Enabled: true}}},
+		}
+	})
+
+	It("delivers results through the returned channel", func() {
+		a := NewAsyncValidator(NewValidator(), WithWorkers(2), WithQueueSize(4))
+		defer a.Close()
+
+		job := ValidationJob{
+			Object:   obj,
+			Rules:    GetRulesFor(obj, "Create", ruleMap),
+			Metadata: NewValidationMetadata(obj, "Create", ruleMap),
+		}
+		ch, err := a.Submit(context.Background(), job)
+		Expect(err).To(BeNil())
+
+		res := <-ch
+		Expect(res.Err).To(BeNil())
+		Expect(res.Results).To(HaveLen(1))
+		Expect(res.Results[0].Passed).To(BeTrue())
+		Eventually(func() uint64 { return a.Stats().Completed }).Should(Equal(uint64(1)))
+	})
+
+	It("rejects jobs after close", func() {
+		a := NewAsyncValidator(NewValidator())
+		a.Close()
+
+		_, err := a.TrySubmit(ValidationJob{Object: obj})
+		Expect(err).To(MatchError(ErrAsyncValidatorClosed))
+	})
+
+	Describe("tenants", func() {
+		var (
+			mu    sync.Mutex
+			order []string
+			gate  chan struct{}
+		)
+
+		// blockingValidator records the Email of each validated object, waiting for the gate
+		blockingValidator := func() *Validator {
+			return NewValidator(WithResolver("record", 1, func(rc ResolverContext, args []any) (any, error) {
+				<-gate
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, args[0].(string))
+				return true, nil
+			}))
+		}
+		job := func(tenant, name string) ValidationJob {
+			return ValidationJob{
+				Tenant:   tenant,
+				Object:   Sample{Email: name},
+				Rules:    []RuleEntry{{Rule: "record(Email)", Enabled: true}},
+				Metadata: ValidationMetadata{StructName: "Sample"},
+			}
+		}
+
+		var openGate func()
+		// newAsync starts a validator, opening the gate before closing it
+		newAsync := func(opts ...AsyncOption) *AsyncValidator {
+			a := NewAsyncValidator(blockingValidator(), opts...)
+			DeferCleanup(a.Close)
+			DeferCleanup(openGate)
+			return a
+		}
+
+		BeforeEach(func() {
+			order = nil
+			gate = make(chan struct{})
+			openGate = sync.OnceFunc(func() { close(gate) })
+		})
+
+		It("serves tenants in turn", func() {
+			a := newAsync()
+
+			var results []<-chan AsyncResult
+			submit := func(j ValidationJob) {
+				ch, err := a.TrySubmit(j)
+				Expect(err).To(BeNil())
+				results = append(results, ch)
+			}
+			submit(job("batch", "b1"))
+			Eventually(func() int { return a.Stats().InFlight }).Should(Equal(1))
+			for _, name := range []string{"b2", "b3", "b4"} {
+				submit(job("batch", name))
+			}
+			submit(job("interactive", "i1"))
+
+			stats := a.Stats()
+			Expect(stats.Tenants["batch"].QueueDepth).To(Equal(3))
+			Expect(stats.Tenants["interactive"].QueueDepth).To(Equal(1))
+
+			openGate()
+			for _, ch := range results {
+				Expect((<-ch).Err).To(BeNil())
+			}
+			Expect(order).To(Equal([]string{"b1", "i1", "b2", "b3", "b4"}))
+			Eventually(func() uint64 { return a.Stats().Completed }).Should(Equal(uint64(5)))
+			stats = a.Stats()
+			Expect(stats.Tenants["batch"]).To(Equal(TenantStats{Completed: 4}))
+			Expect(stats.Tenants["interactive"]).To(Equal(TenantStats{Completed: 1}))
+			// drained tenants leave the turn order, keeping their counters
+			a.mu.Lock()
+			Expect(a.tenants).To(BeEmpty())
+			Expect(a.order).To(BeEmpty())
+			a.mu.Unlock()
+		})
+
+		It("caps the queued jobs of a tenant by default", func() {
+			a := newAsync(WithQueueSize(4))
+			_, err := a.TrySubmit(job("batch", "b1"))
+			Expect(err).To(BeNil())
+			Eventually(func() int { return a.Stats().InFlight }).Should(Equal(1))
+			for _, name := range []string{"b2", "b3"} {
+				_, err := a.TrySubmit(job("batch", name))
+				Expect(err).To(BeNil())
+			}
+
+			_, err = a.TrySubmit(job("batch", "b4"))
+			Expect(err).To(MatchError(ErrQueueFull))
+			_, err = a.TrySubmit(job("interactive", "i1"))
+			Expect(err).To(BeNil())
+		})
+
+		It("caps the workers and queued jobs of a tenant", func() {
+			a := newAsync(WithWorkers(3), WithTenantConcurrency(2), WithTenantQueueSize(1))
+
+			for i, name := range []string{"b1", "b2", "b3"} {
+				_, err := a.TrySubmit(job("batch", name))
+				Expect(err).To(BeNil())
+				Eventually(func() int { return a.Stats().Tenants["batch"].InFlight }).Should(Equal(min(i+1, 2)))
+			}
+			_, err := a.TrySubmit(job("batch", "b4"))
+			Expect(err).To(MatchError(ErrQueueFull))
+
+			ch, err := a.TrySubmit(job("interactive", "i1"))
+			Expect(err).To(BeNil())
+			Eventually(func() int { return a.Stats().Tenants["interactive"].InFlight }).Should(Equal(1))
+
+			stats := a.Stats()
+			Expect(stats.Tenants["batch"].InFlight).To(Equal(2))
+			Expect(stats.Tenants["batch"].QueueDepth).To(Equal(1))
+			Expect(stats.Tenants["batch"].Rejected).To(Equal(uint64(1)))
+
+			openGate()
+			Expect((<-ch).Err).To(BeNil())
+		})
+
+		It("keeps the counters of idle tenants", func() {
+			a := newAsync(WithQueueSize(1))
+			_, err := a.TrySubmit(job("batch", "b1"))
+			Expect(err).To(BeNil())
+			Eventually(func() int { return a.Stats().InFlight }).Should(Equal(1))
+			_, err = a.TrySubmit(job("batch", "b2"))
+			Expect(err).To(BeNil())
+
+			_, err = a.TrySubmit(job("idle", "i1"))
+			Expect(err).To(MatchError(ErrQueueFull))
+			Expect(a.Stats().Tenants["idle"]).To(Equal(TenantStats{Rejected: 1}))
+			openGate()
+		})
+
+		It("bounds the counters of idle tenants", func() {
+			a := newAsync(WithQueueSize(1))
+			_, err := a.TrySubmit(job("batch", "b1"))
+			Expect(err).To(BeNil())
+			Eventually(func() int { return a.Stats().InFlight }).Should(Equal(1))
+			_, err = a.TrySubmit(job("batch", "b2"))
+			Expect(err).To(BeNil())
+
+			for i := range maxTenantCounters + 10 {
+				_, err := a.TrySubmit(job(fmt.Sprintf("tenant-%d", i), "x"))
+				Expect(err).To(MatchError(ErrQueueFull))
+			}
+			stats := a.Stats()
+			Expect(stats.Tenants).To(HaveLen(maxTenantCounters))
+			Expect(stats.Tenants["batch"]).To(Equal(TenantStats{QueueDepth: 1, InFlight: 1}))
+			Expect(stats.Rejected).To(Equal(uint64(maxTenantCounters + 10)))
+			openGate()
+		})
+	})
+})
@@ -0,0 +1,193 @@
+package celvalidator
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrAuditChainBroken is returned when an audit log fails hash chain verification
+var ErrAuditChainBroken = errors.New("audit chain broken")
+
+// AuditSink receives a record for every completed validation
+type AuditSink interface {
+	Record(record AuditRecord) error
+}
+
+// AuditResult is the persisted outcome of a single rule
+type AuditResult struct {
+	Rule    string `json:"rule"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// AuditRecord is a single entry of the audit log
+type AuditRecord struct {
+	Sequence   uint64        `json:"seq"`
+	Time       time.Time     `json:"time"`
+	StructName string        `json:"struct"`
+	Operation  string        `json:"operation"`
+	ObjectID   string        `json:"object_id,omitempty"`
+	Results    []AuditResult `json:"results"`
+	// Object is the validated object as captured by the snapshot policy
+	Object   json.RawMessage `json:"object,omitempty"`
+	PrevHash string          `json:"prev_hash"`
+	Hash     string          `json:"hash"`
+}
+
+// WithAuditSink records the outcome of every validation to the given sink
+func WithAuditSink(sink AuditSink) ValidatorOption {
+	return func(v *Validator) {
+		v.auditSink = sink
+	}
+}
+
+// newAuditRecord builds the audit record of a validation
+func newAuditRecord(metadata ValidationMetadata, results []ValidationResult) AuditRecord {
+	record := AuditRecord{
+		Time:       time.Now().UTC(),
+		StructName: metadata.StructName,
+		Operation:  metadata.Operation,
+		ObjectID:   metadata.ObjectID,
+		Results:    make([]AuditResult, 0, len(results)),
+	}
+	for _, r := range results {
+		record.Results = append(record.Results, AuditResult{
+			Rule:    r.Rule,
+			Passed:  r.Passed,
+			Message: r.Message,
+			Error:   errorString(r.Error),
+		})
+	}
+	return record
+}
+
+// HashChainSink writes audit records as JSON lines where each record carries
+// the hash of the previous one, making later tampering detectable
+type HashChainSink struct {
+	mu       sync.Mutex
+	w        io.Writer
+	seq      uint64
+	prevHash string
+}
+
+// NewHashChainSink starts a new hash chain on w
+func NewHashChainSink(w io.Writer) *HashChainSink {
+	return &HashChainSink{w: w}
+}
+
+// OpenHashChainLog verifies an existing log file and opens it for appending,
+// continuing its chain. The file is created if it does not exist.
+func OpenHashChainLog(path string) (*HashChainSink, *os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening audit log: %w", err)
+	}
+
+	last, _, err := verifyAuditLog(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	sink := NewHashChainSink(f)
+	if last != nil {
+		sink.seq = last.Sequence
+		sink.prevHash = last.Hash
+	}
+	return sink, f, nil
+}
+
+// Record appends a record to the chain
+func (s *HashChainSink) Record(record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.Sequence = s.seq + 1
+	record.PrevHash = s.prevHash
+	hash, err := hashAuditRecord(record)
+	if err != nil {
+		return err
+	}
+	record.Hash = hash
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshalling audit record: %w", err)
+	}
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing audit record: %w", err)
+	}
+
+	s.seq = record.Sequence
+	s.prevHash = record.Hash
+	return nil
+}
+
+// VerifyAuditLog checks the hash chain of an audit log and returns the number
+// of verified records
+func VerifyAuditLog(r io.Reader) (int, error) {
+	_, count, err := verifyAuditLog(r)
+	return count, err
+}
+
+func verifyAuditLog(r io.Reader) (*AuditRecord, int, error) {
+	var last *AuditRecord
+	count := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, count, fmt.Errorf("%w: record %d: %v", ErrAuditChainBroken, count+1, err)
+		}
+
+		expectedPrev, expectedSeq := "", uint64(1)
+		if last != nil {
+			expectedPrev, expectedSeq = last.Hash, last.Sequence+1
+		}
+		if record.Sequence != expectedSeq || record.PrevHash != expectedPrev {
+			return nil, count, fmt.Errorf("%w: record %d does not follow previous record", ErrAuditChainBroken, count+1)
+		}
+
+		hash, err := hashAuditRecord(record)
+		if err != nil {
+			return nil, count, err
+		}
+		if hash != record.Hash {
+			return nil, count, fmt.Errorf("%w: record %d hash mismatch", ErrAuditChainBroken, count+1)
+		}
+
+		last = &record
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, count, fmt.Errorf("reading audit log: %w", err)
+	}
+
+	return last, count, nil
+}
+
+// hashAuditRecord hashes the record content, excluding its own hash
+func hashAuditRecord(record AuditRecord) (string, error) {
+	record.Hash = ""
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("marshalling audit record: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
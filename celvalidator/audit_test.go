@@ -0,0 +1,63 @@
+package celvalidator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hash chain audit sink", func() {
+	var buf *bytes.Buffer
+	var v *Validator
+	var obj Sample
+	var ruleMap RuleSetMap
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		v = NewValidator(WithAuditSink(NewHashChainSink(buf)))
+		obj = Sample{Age: 21, Email: "test@example.com"}
+		ruleMap = RuleSetMap{
+			"Sample": {"Create": {{Rule: "Age > 18", Enabled: true}, {Rule: "Email == ''", Enabled: true}}},
+		}
+	})
+
+	It("writes a verifiable chain", func() {
+		for range 3 {
+			_, err := v.Validate(obj, GetRulesFor(obj, "Create", ruleMap), NewValidationMetadata(obj, "Create", ruleMap))
+			Expect(err).To(BeNil())
+		}
+
+		count, err := VerifyAuditLog(bytes.NewReader(buf.Bytes()))
+		Expect(err).To(BeNil())
+		Expect(count).To(Equal(3))
+	})
+
+	It("detects tampering", func() {
+		_, err := v.Validate(obj, GetRulesFor(obj, "Create", ruleMap), NewValidationMetadata(obj, "Create", ruleMap))
+		Expect(err).To(BeNil())
+
+		tampered := strings.Replace(buf.String(), `"passed":false`, `"passed":true`, 1)
+		_, err = VerifyAuditLog(strings.NewReader(tampered))
+		Expect(err).To(MatchError(ErrAuditChainBroken))
+	})
+	DescribeTable("captures the object with the snapshot policy",
+		func(policy SnapshotPolicy, expected string) {
+			v = NewValidator(WithAuditSink(NewHashChainSink(buf)), WithAuditSnapshot(policy))
+			_, err := v.Validate(obj, GetRulesFor(obj, "Create", ruleMap), NewValidationMetadata(obj, "Create", ruleMap))
+			Expect(err).To(BeNil())
+
+			var record AuditRecord
+			Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+			Expect(string(record.Object)).To(MatchRegexp(expected))
+
+			_, err = VerifyAuditLog(bytes.NewReader(buf.Bytes()))
+			Expect(err).To(BeNil())
+		},
+		Entry("full", FullSnapshot(), `^\{"Active":false,"Age":21,"Details":null,"Email":"test@example.com"\}$`),
+		Entry("hashed", HashedSnapshot(), `^\{"sha256":"[0-9a-f]{64}"\}$`),
+		Entry("selected fields", FieldsSnapshot("Age"), `^\{"Age":21\}$`),
+	)
+})
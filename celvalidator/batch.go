@@ -0,0 +1,207 @@
+package celvalidator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ObjectResult is the outcome of validating one object of a batch
+type ObjectResult struct {
+	// Index is the position of the object in the batch
+	Index    int
+	Object   any
+	Metadata ValidationMetadata
+	Results  []ValidationResult
+	Err      error
+}
+
+// WithBatchConcurrency validates the objects of ValidateAll across n workers
+func WithBatchConcurrency(n int) ValidatorOption {
+	return func(v *Validator) {
+		v.batchConcurrency = n
+	}
+}
+
+// BatchProgress is the progress of a batch validation
+type BatchProgress struct {
+	Total     int
+	Completed int
+	// Failures counts the completed objects with an error or a failed rule
+	Failures int
+	Elapsed  time.Duration
+}
+
+// Remaining estimates the time left from the pace so far, zero until an
+// object completes
+func (p BatchProgress) Remaining() time.Duration {
+	if p.Completed == 0 {
+		return 0
+	}
+	return p.Elapsed / time.Duration(p.Completed) * time.Duration(p.Total-p.Completed)
+}
+
+// Progress receives the progress of batch validations, e.g. to render a
+// progress bar. Reports of a batch are delivered one at a time.
+type Progress interface {
+	Report(p BatchProgress)
+}
+
+// ProgressFunc adapts a function to a Progress
+type ProgressFunc func(p BatchProgress)
+
+func (f ProgressFunc) Report(p BatchProgress) {
+	f(p)
+}
+
+type batchProgressKey struct{}
+
+// batchProgress is the progress reporter attached to a context
+type batchProgress struct {
+	progress Progress
+	interval time.Duration
+}
+
+// WithBatchProgress reports the progress of ValidateAllContext run with ctx to
+// p every interval, and once more when the batch is done, only then with a
+// zero interval. Each batch reports to the reporter of its own context.
+func WithBatchProgress(ctx context.Context, p Progress, interval time.Duration) context.Context {
+	return context.WithValue(ctx, batchProgressKey{}, batchProgress{progress: p, interval: interval})
+}
+
+// BatchProgressFrom returns the reporter and interval attached with
+// WithBatchProgress, nil without one
+func BatchProgressFrom(ctx context.Context) (Progress, time.Duration) {
+	bp, _ := ctx.Value(batchProgressKey{}).(batchProgress)
+	return bp.progress, bp.interval
+}
+
+// batchTracker counts the completed objects of a batch for progress reports
+type batchTracker struct {
+	total     int
+	start     time.Time
+	completed atomic.Int64
+	failures  atomic.Int64
+}
+
+func (t *batchTracker) done(r ObjectResult) {
+	if r.Err != nil || !allPassed(r.Results) {
+		t.failures.Add(1)
+	}
+	t.completed.Add(1)
+}
+
+func (t *batchTracker) snapshot() BatchProgress {
+	return BatchProgress{
+		Total:     t.total,
+		Completed: int(t.completed.Load()),
+		Failures:  int(t.failures.Load()),
+		Elapsed:   time.Since(t.start),
+	}
+}
+
+// report reports the progress of the batch every interval until stop is closed
+func (t *batchTracker) report(p Progress, interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		<-stop
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.Report(t.snapshot())
+		}
+	}
+}
+
+func allPassed(results []ValidationResult) bool {
+	for _, r := range results {
+		if !r.Passed || r.Error != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateAll validates every object against its rules for an operation, in
+// the order of objs. Objects of the same type share compiled programs through
+// the program cache. Errors of an object are reported on its result.
+func (v *Validator) ValidateAll(objs []any, rulesMap RuleSetMap, operation string) ([]ObjectResult, error) {
+	return v.ValidateAllContext(context.Background(), objs, rulesMap, operation)
+}
+
+// ValidateAllContext is ValidateAll with a context, returning the context error
+// along with the results of the objects validated when it is done
+func (v *Validator) ValidateAllContext(ctx context.Context, objs []any, rulesMap RuleSetMap, operation string) ([]ObjectResult, error) {
+	results := make([]ObjectResult, len(objs))
+	validated := make([]bool, len(objs))
+	tracker := &batchTracker{total: len(objs), start: time.Now()}
+	if progress, interval := BatchProgressFrom(ctx); progress != nil {
+		stop := make(chan struct{})
+		reported := make(chan struct{})
+		go func() {
+			defer close(reported)
+			tracker.report(progress, interval, stop)
+		}()
+		defer func() {
+			close(stop)
+			<-reported
+			progress.Report(tracker.snapshot())
+		}()
+	}
+	validate := func(i int) {
+		defer func() { tracker.done(results[i]) }()
+		obj := objs[i]
+		results[i] = ObjectResult{Index: i, Object: obj}
+		validated[i] = true
+		if obj == nil {
+			results[i].Err = fmt.Errorf("object %d is nil", i)
+			return
+		}
+		metadata := v.identify(obj, NewValidationMetadata(obj, operation, rulesMap))
+		results[i].Metadata = metadata
+		results[i].Results, results[i].Err = v.ValidateContext(ctx, obj, GetRulesFor(obj, metadata.Operation, rulesMap), metadata)
+	}
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(max(v.batchConcurrency, 1), len(objs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				validate(i)
+			}
+		}()
+	}
+feed:
+	for i := range objs {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case work <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		var done []ObjectResult
+		for i, r := range results {
+			if validated[i] {
+				done = append(done, r)
+			}
+		}
+		return done, err
+	}
+	return results, nil
+}
@@ -0,0 +1,118 @@
+package celvalidator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch validation", func() {
+	rules := RuleSetMap{
+		"Sample": {
+			"Default": {{Rule: "Age >= 18", Enabled: true}},
+			"Create":  {{Rule: "Email != ''", Enabled: true}},
+		},
+		"Address": {
+			"Default": {{Rule: "City != ''", Enabled: true}},
+		},
+	}
+
+	objs := []any{
+		Sample{Age: 20, Email: "a@example.com"},
+		&Sample{Age: 10},
+		Address{City: "Toronto"},
+		nil,
+		Sample{Age: 30},
+	}
+
+	It("validates objects in order with their own rules", func() {
+		for _, v := range []*Validator{NewValidator(), NewValidator(WithBatchConcurrency(3))} {
+			results, err := v.ValidateAll(objs, rules, "Create")
+			Expect(err).To(BeNil())
+			Expect(results).To(HaveLen(5))
+
+			Expect(results[0].Results).To(HaveLen(2))
+			Expect(results[0].Results[0].Passed).To(BeTrue())
+			Expect(results[1].Results[0].Passed).To(BeFalse())
+			Expect(results[1].Results[1].Passed).To(BeFalse())
+			Expect(results[2].Metadata.StructName).To(Equal("Address"))
+			Expect(results[2].Results).To(HaveLen(1))
+			Expect(results[3].Err).To(MatchError(ContainSubstring("nil")))
+			Expect(results[4].Index).To(Equal(4))
+			Expect(results[4].Results[1].Passed).To(BeFalse())
+		}
+	})
+
+	It("reuses compiled programs across objects", func() {
+		v := NewValidator()
+		_, err := v.ValidateAll([]any{Sample{Age: 1}, Sample{Age: 2}, Sample{Age: 3}}, rules, "Create")
+		Expect(err).To(BeNil())
+		Expect(v.Stats().Misses).To(Equal(uint64(2)))
+		Expect(v.Stats().Hits).To(Equal(uint64(4)))
+	})
+
+	It("stops on a done context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := NewValidator().ValidateAllContext(ctx, objs, rules, "Create")
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(results).To(BeEmpty())
+	})
+
+	It("reports the progress of the batch", func() {
+		var mu sync.Mutex
+		var reports []BatchProgress
+		progress := ProgressFunc(func(p BatchProgress) {
+			mu.Lock()
+			defer mu.Unlock()
+			reports = append(reports, p)
+		})
+
+		v := NewValidator(WithBatchConcurrency(2))
+		_, err := v.ValidateAllContext(WithBatchProgress(context.Background(), progress, time.Millisecond), objs, rules, "Create")
+		Expect(err).To(BeNil())
+		last := reports[len(reports)-1]
+		Expect(last.Total).To(Equal(5))
+		Expect(last.Completed).To(Equal(5))
+		Expect(last.Failures).To(Equal(3))
+		Expect(last.Remaining()).To(BeZero())
+		for i := 1; i < len(reports); i++ {
+			Expect(reports[i].Completed).To(BeNumerically(">=", reports[i-1].Completed))
+		}
+
+		reports = nil
+		_, err = v.ValidateAllContext(WithBatchProgress(context.Background(), progress, 0), objs[:2], rules, "Create")
+		Expect(err).To(BeNil())
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Completed).To(Equal(2))
+	})
+
+	It("reports concurrent batches of a validator to their own reporter", func() {
+		v := NewValidator(WithBatchConcurrency(2))
+		var wg sync.WaitGroup
+		totals := make([]int, 2)
+		for i, batch := range [][]any{objs, objs[:2]} {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				ctx := WithBatchProgress(context.Background(), ProgressFunc(func(p BatchProgress) {
+					Expect(p.Total).To(Equal(len(batch)))
+					totals[i] = p.Completed
+				}), time.Millisecond)
+				_, err := v.ValidateAllContext(ctx, batch, rules, "Create")
+				Expect(err).To(BeNil())
+			}()
+		}
+		wg.Wait()
+		Expect(totals).To(Equal([]int{5, 2}))
+	})
+
+	It("estimates the remaining time from the pace so far", func() {
+		Expect(BatchProgress{Total: 10, Completed: 2, Elapsed: time.Second}.Remaining()).To(Equal(4 * time.Second))
+		Expect(BatchProgress{Total: 10}.Remaining()).To(BeZero())
+	})
+})
@@ -0,0 +1,135 @@
+package celvalidator
+
+import (
+	"expvar"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// canaryDivergences is the number of recent divergences kept by a canary
+const canaryDivergences = 100
+
+// Canary validates with the current rule set while evaluating a candidate rule
+// set in shadow for a fraction of calls until it expires, counting the objects
+// whose verdict differs so rule changes can be promoted with confidence
+type Canary struct {
+	v         *Validator
+	shadow    *Validator
+	current   *RuleProvider
+	candidate *RuleSetSnapshot
+	fraction  float64
+	expires   time.Time
+	sample    func() float64
+
+	mu          sync.Mutex
+	stats       CanaryStats
+	divergences []CanaryDivergence
+}
+
+// CanaryStats counts the shadow evaluations of a canary
+type CanaryStats struct {
+	Evaluated    uint64 `json:"evaluated"`
+	Diverged     uint64 `json:"diverged"`
+	ShadowErrors uint64 `json:"shadow_errors"`
+	Active       bool   `json:"active"`
+}
+
+// CanaryDivergence is an object whose verdict differs between rule sets
+type CanaryDivergence struct {
+	Time       time.Time  `json:"time"`
+	StructName string     `json:"struct"`
+	Operation  string     `json:"operation"`
+	Current    bool       `json:"current_valid"`
+	Candidate  bool       `json:"candidate_valid"`
+	Diff       ReportDiff `json:"-"`
+}
+
+// NewCanary evaluates candidate in shadow of current for fraction (0 to 1) of
+// the validations made during duration. Shadow validations are neither audited
+// nor fed to the rule optimizer, failure router and metrics.
+func NewCanary(v *Validator, current *RuleProvider, candidate *RuleFile, fraction float64, duration time.Duration) *Canary {
+	shadow := *v
+	shadow.auditSink = nil
+	shadow.optimizer = nil
+	shadow.failureRouter = nil
+	shadow.metrics = nil
+
+	candidateProvider := &RuleProvider{}
+	return &Canary{
+		v:         v,
+		shadow:    &shadow,
+		current:   current,
+		candidate: candidateProvider.Swap(candidate),
+		fraction:  fraction,
+		expires:   time.Now().Add(duration),
+		sample:    rand.Float64,
+	}
+}
+
+// Validate validates obj for an operation with the current rule set, which
+// alone determines the returned results
+func (c *Canary) Validate(obj any, operation string) ([]ValidationResult, error) {
+	results, err := c.v.ValidateSnapshot(obj, operation, c.current.Snapshot())
+	if err != nil || !c.Active() || c.sample() >= c.fraction {
+		return results, err
+	}
+
+	shadowResults, shadowErr := c.shadow.ValidateSnapshot(obj, operation, c.candidate)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Evaluated++
+	if shadowErr != nil {
+		c.stats.ShadowErrors++
+		return results, err
+	}
+
+	currentValid := ValidationReport(results).Decide(AllMustPass()).Valid
+	candidateValid := ValidationReport(shadowResults).Decide(AllMustPass()).Valid
+	if currentValid != candidateValid {
+		c.stats.Diverged++
+		metadata := c.candidate.Metadata(obj, operation)
+		divergence := CanaryDivergence{
+			Time:       time.Now().UTC(),
+			StructName: metadata.StructName,
+			Operation:  metadata.Operation,
+			Current:    currentValid,
+			Candidate:  candidateValid,
+			Diff:       DiffReports(results, shadowResults),
+		}
+		if len(c.divergences) == canaryDivergences {
+			c.divergences = c.divergences[1:]
+		}
+		c.divergences = append(c.divergences, divergence)
+	}
+	return results, err
+}
+
+// Active reports whether the canary still evaluates the candidate
+func (c *Canary) Active() bool {
+	return time.Now().Before(c.expires)
+}
+
+// Stats returns the shadow evaluation counters
+func (c *Canary) Stats() CanaryStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Active = c.Active()
+	return stats
+}
+
+// Divergences returns the most recent divergences, oldest first
+func (c *Canary) Divergences() []CanaryDivergence {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]CanaryDivergence(nil), c.divergences...)
+}
+
+// PublishExpvar publishes the canary stats as the expvar name, which must be unique
+func (c *Canary) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return c.Stats()
+	}))
+}
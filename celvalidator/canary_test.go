@@ -0,0 +1,50 @@
+package celvalidator
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Canary", func() {
+	current := RuleSetMap{"Sample": {"Create": {{Rule: "Age > 18", Enabled: true}}}}
+	candidate := &RuleFile{Rules: RuleSetMap{"Sample": {"Create": {{Rule: "Age > 21", Enabled: true}}}}}
+
+	It("counts objects whose verdict differs under the candidate", func() {
+		canary := NewCanary(NewValidator(), NewRuleProvider(current), candidate, 1, time.Hour)
+
+		for _, age := range []int{10, 20, 30} {
+			results, err := canary.Validate(Sample{Age: age}, "Create")
+			Expect(err).To(BeNil())
+			Expect(results[0].Rule).To(Equal("Age > 18"))
+		}
+
+		Expect(canary.Stats()).To(Equal(CanaryStats{Evaluated: 3, Diverged: 1, Active: true}))
+		divergences := canary.Divergences()
+		Expect(divergences).To(HaveLen(1))
+		Expect(divergences[0].Current).To(BeTrue())
+		Expect(divergences[0].Candidate).To(BeFalse())
+		Expect(divergences[0].Diff.Added).To(ConsistOf(HaveField("Rule", "Age > 21")))
+	})
+
+	It("only samples a fraction of validations while active", func() {
+		canary := NewCanary(NewValidator(), NewRuleProvider(current), candidate, 0.5, time.Hour)
+		samples := []float64{0.2, 0.7}
+		canary.sample = func() float64 {
+			s := samples[0]
+			samples = samples[1:]
+			return s
+		}
+		for range 2 {
+			_, err := canary.Validate(Sample{Age: 20}, "Create")
+			Expect(err).To(BeNil())
+		}
+		Expect(canary.Stats().Evaluated).To(Equal(uint64(1)))
+
+		expired := NewCanary(NewValidator(), NewRuleProvider(current), candidate, 1, 0)
+		_, err := expired.Validate(Sample{Age: 20}, "Create")
+		Expect(err).To(BeNil())
+		Expect(expired.Stats()).To(Equal(CanaryStats{}))
+	})
+})
@@ -0,0 +1,70 @@
+package celvalidator
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+type cachedRuleFile struct {
+	modTime   time.Time
+	file      *RuleFile
+	validator *Validator
+}
+
+var (
+	checkCacheMu sync.Mutex
+	checkCache   = map[string]cachedRuleFile{}
+)
+
+// Check loads the rule file at path, selects the rules for obj and operation
+// and validates obj with partial evaluation in one call, with a validator
+// configured by the validator: block of the file. Rule files and their
+// validators are cached and reloaded when their modification time changes.
+func Check(obj any, operation string, path string) (ValidationReport, error) {
+	file, v, err := loadCachedRuleFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := NewValidationMetadata(obj, operation, file.Rules, WithDefaultOperations(file.DefaultOperations), WithVersions(file.Versions))
+	rules := GetRulesFor(obj, metadata.Operation, file.Rules)
+	if m, ok := file.Versions[metadata.StructName]; ok {
+		rules = GetRulesForStruct(m.Logical, metadata.Operation, file.Rules)
+	}
+
+	return v.Validate(obj, rules, metadata)
+}
+
+// loadCachedRuleFile returns the rule file at path and its validator, loading
+// them unless cached for the current modification time
+func loadCachedRuleFile(path string) (*RuleFile, *Validator, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading rule file: %w", err)
+	}
+
+	checkCacheMu.Lock()
+	defer checkCacheMu.Unlock()
+
+	if cached, ok := checkCache[path]; ok && cached.modTime.Equal(info.ModTime()) {
+		debug.recordCacheLookup(true)
+		return cached.file, cached.validator, nil
+	}
+	debug.recordCacheLookup(false)
+
+	file, err := LoadRuleFileFromYAML(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opts []ValidatorOption
+	if file.Validator != nil {
+		if opts, err = file.Validator.Options(); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	v := NewValidator(append(opts, WithPartialEval(), WithVersionMappings(file.Versions))...)
+	checkCache[path] = cachedRuleFile{modTime: info.ModTime(), file: file, validator: v}
+	return file, v, nil
+}
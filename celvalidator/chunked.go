@@ -0,0 +1,64 @@
+package celvalidator
+
+import "context"
+
+// ChunkedValidation evaluates a rule set a few top-level rules at a time, so
+// very large rule sets can be spread across scheduling slices without blocking
+// for the whole set. It is not safe for concurrent use.
+type ChunkedValidation struct {
+	e     *evaluation
+	rules []RuleEntry
+	next  int
+	done  bool
+	err   error
+}
+
+// StartChunked prepares a chunked validation of obj, no rule is evaluated
+// before the first call to Next
+func (v *Validator) StartChunked(
+	ctx context.Context,
+	obj any,
+	rules []RuleEntry,
+	metadata ValidationMetadata,
+) (*ChunkedValidation, error) {
+	metadata = v.identify(obj, metadata)
+	fields := v.objectFields(obj)
+	v.addRequestVars(ctx, fields)
+	env, vars, err := v.buildFieldsEnv(obj, fields)
+	if err != nil {
+		return nil, err
+	}
+	return &ChunkedValidation{e: v.newEvaluation(ctx, env, vars, metadata), rules: rules}, nil
+}
+
+// Next evaluates up to n further top-level rules in rule order, with their
+// Then children, and returns their results. done reports whether the validation
+// is complete, after which Next returns no results and the final error. Results
+// are audited once, when the validation completes.
+func (c *ChunkedValidation) Next(n int) (results []ValidationResult, done bool, err error) {
+	if c.done {
+		return nil, true, c.err
+	}
+
+	start := len(c.e.results)
+	end := min(c.next+n, len(c.rules))
+	for ; c.next < end && err == nil; c.next++ {
+		err = c.e.evalEntry(c.next, c.rules[c.next], c.e.metadata)
+	}
+
+	if err != nil || c.next == len(c.rules) {
+		c.done = true
+		c.err = c.e.finish(err)
+	}
+	return c.e.results[start:], c.done, c.err
+}
+
+// Cursor returns the index of the next top-level rule to evaluate
+func (c *ChunkedValidation) Cursor() int {
+	return c.next
+}
+
+// Results returns the results of all the chunks evaluated so far
+func (c *ChunkedValidation) Results() ValidationReport {
+	return c.e.results
+}
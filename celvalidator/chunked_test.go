@@ -0,0 +1,61 @@
+package celvalidator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chunked validation", func() {
+	obj := Sample{Age: 30, Email: "a@b.com"}
+	rules := []RuleEntry{
+		{Rule: "Age > 18", Enabled: true, Then: []RuleEntry{{Rule: "Age < 100", Enabled: true}}},
+		{Rule: "Email != ''", Enabled: true},
+		{Rule: "Age > 18", Enabled: true},
+		{Rule: "Age > 40", Enabled: true},
+	}
+	metadata := ValidationMetadata{StructName: "Sample"}
+
+	It("evaluates rules in chunks matching a single validation", func() {
+		v := NewValidator()
+		chunked, err := v.StartChunked(context.Background(), obj, rules, metadata)
+		Expect(err).To(BeNil())
+
+		results, done, err := chunked.Next(1)
+		Expect(err).To(BeNil())
+		Expect(done).To(BeFalse())
+		Expect(results).To(HaveLen(2))
+		Expect(chunked.Cursor()).To(Equal(1))
+
+		results, done, err = chunked.Next(2)
+		Expect(err).To(BeNil())
+		Expect(done).To(BeFalse())
+		Expect(results).To(ConsistOf(HaveField("Rule", "Email != ''")))
+
+		results, done, err = chunked.Next(10)
+		Expect(err).To(BeNil())
+		Expect(done).To(BeTrue())
+		Expect(results).To(ConsistOf(HaveField("Rule", "Age > 40")))
+
+		whole, err := v.Validate(obj, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(chunked.Results()).To(Equal(ValidationReport(whole)))
+
+		results, done, _ = chunked.Next(1)
+		Expect(results).To(BeEmpty())
+		Expect(done).To(BeTrue())
+	})
+
+	It("completes early on errors outside partial evaluation", func() {
+		chunked, err := NewValidator().StartChunked(context.Background(), obj, []RuleEntry{
+			{Rule: "Unknown == 1", Enabled: true},
+			{Rule: "Age > 18", Enabled: true},
+		}, metadata)
+		Expect(err).To(BeNil())
+
+		_, done, err := chunked.Next(1)
+		Expect(done).To(BeTrue())
+		Expect(err).To(HaveOccurred())
+	})
+})
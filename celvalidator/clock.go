@@ -0,0 +1,41 @@
+package celvalidator
+
+import (
+	"reflect"
+	"time"
+)
+
+// nowVariable names the variable holding the time of the validation with WithClock
+const nowVariable = "now"
+
+// timeType is exposed as a CEL timestamp rather than flattened into its fields
+var timeType = reflect.TypeOf(time.Time{})
+
+// Clock tells the time of validations
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock reads the system time
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock always tells t, for deterministic tests of time-based rules
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// WithClock declares now, a timestamp read from clock once per validation, so
+// rules can compare it to time.Time fields, e.g. Expiry > now or
+// DOB + duration('157680h') <= now. Fields named now shadow it.
+func WithClock(clock Clock) ValidatorOption {
+	return func(v *Validator) {
+		v.clock = clock
+	}
+}
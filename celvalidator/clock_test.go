@@ -0,0 +1,54 @@
+package celvalidator
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Clock", func() {
+	type Subscription struct {
+		Expiry  time.Time
+		Renewed *time.Time
+	}
+	md := ValidationMetadata{StructName: "Subscription", Operation: "Create"}
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	It("declares now from the clock and time fields as timestamps", func() {
+		v := NewValidator(WithClock(FixedClock(now)))
+		rules := []RuleEntry{
+			{Rule: "Expiry > now", Enabled: true},
+			{Rule: "!has(Renewed) || Renewed <= now", Enabled: true},
+		}
+		renewed := now.Add(-time.Hour)
+		results, err := v.Validate(Subscription{Expiry: now.Add(24 * time.Hour), Renewed: &renewed}, rules, md)
+		Expect(err).To(BeNil())
+		for _, res := range results {
+			Expect(res.Error).To(BeNil())
+			Expect(res.Passed).To(BeTrue(), res.Rule)
+		}
+
+		results, err = v.Validate(Subscription{Expiry: now.Add(-time.Second)}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeFalse())
+		Expect(results[1].Passed).To(BeTrue())
+	})
+
+	It("reads the clock once per validation", func() {
+		calls := 0
+		v := NewValidator(WithClock(ClockFunc(func() time.Time {
+			calls++
+			return now
+		})))
+		_, err := v.Validate(Subscription{}, []RuleEntry{{Rule: "now == now", Enabled: true}, {Rule: "now > Expiry", Enabled: true}}, md)
+		Expect(err).To(BeNil())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("does not declare now without a clock", func() {
+		results, err := NewValidator(WithPartialEval()).Validate(Subscription{}, []RuleEntry{{Rule: "Expiry < now", Enabled: true}}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Error).NotTo(BeNil())
+	})
+})
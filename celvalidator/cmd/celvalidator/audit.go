@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gdbranco/celvalidator"
+)
+
+// runVerifyAudit verifies the hash chain of an audit log file
+func runVerifyAudit(args []string) error {
+	if len(args) != 1 {
+		return errors.New("verify-audit expects exactly one file")
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	count, err := celvalidator.VerifyAuditLog(f)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: %d records verified\n", args[0], count)
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gdbranco/celvalidator"
+)
+
+// runCheckConfig validates a YAML or JSON config file, described by a schema
+// or a sample document rather than a Go type, and returns the exit code
+func runCheckConfig(args []string, stdout io.Writer) (int, error) {
+	fs := flag.NewFlagSet("check-config", flag.ContinueOnError)
+	rulesPath := fs.String("rules", "", "rule file (YAML)")
+	typeName := fs.String("type", "", "type name the config represents in the rule file")
+	schemaPath := fs.String("schema", "", "schema file mapping fields to CEL types")
+	samplePath := fs.String("sample", "", "sample config to infer the schema from")
+	operation := fs.String("op", "", "operation, defaults to the type default_operation")
+	failOn := fs.String("fail-on", "error", "lowest failing severity: error|warning|any")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+
+	threshold, ok := failOnThresholds[*failOn]
+	if !ok {
+		return exitUsage, fmt.Errorf("invalid --fail-on %q", *failOn)
+	}
+	if *rulesPath == "" || *typeName == "" || fs.NArg() != 1 {
+		return exitUsage, errors.New("check-config expects --rules, --type and one config file")
+	}
+	if *schemaPath != "" && *samplePath != "" {
+		return exitUsage, errors.New("check-config accepts only one of --schema and --sample")
+	}
+
+	var schema celvalidator.DocumentSchema
+	var err error
+	switch {
+	case *schemaPath != "":
+		schema, err = celvalidator.LoadDocumentSchema(*schemaPath)
+	case *samplePath != "":
+		var sample []byte
+		if sample, err = os.ReadFile(*samplePath); err == nil {
+			schema, err = celvalidator.SchemaFromSample(sample)
+		}
+	}
+	if err != nil {
+		return exitError, err
+	}
+
+	file, err := celvalidator.LoadRuleFileFromYAML(*rulesPath)
+	if err != nil {
+		return exitError, err
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return exitError, err
+	}
+
+	op := resolveOperation(file, *typeName, *operation)
+	metadata := celvalidator.ValidationMetadata{StructName: *typeName, Operation: op, RuleIndex: -1}
+	rules := celvalidator.GetRulesForStruct(*typeName, op, file.Rules)
+	v, err := newValidator(file)
+	if err != nil {
+		return exitError, err
+	}
+	results, err := v.ValidateDocument(data, schema, rules, metadata)
+	if err != nil {
+		return exitError, err
+	}
+
+	return reportResults(stdout, results, threshold), nil
+}
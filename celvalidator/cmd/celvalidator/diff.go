@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/gdbranco/celvalidator"
+)
+
+// runDiff prints the semantic changes between two rule files and returns the exit code
+func runDiff(args []string, stdout io.Writer) (int, error) {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() != 2 {
+		return exitUsage, errors.New("diff expects two rule files")
+	}
+
+	var files [2]*celvalidator.RuleFile
+	for i, path := range fs.Args() {
+		file, err := celvalidator.LoadRuleFileFromYAML(path)
+		if err != nil {
+			return exitError, err
+		}
+		files[i] = file
+	}
+
+	changes := celvalidator.DiffRuleSets(files[0].Rules, files[1].Rules)
+	if len(changes) == 0 {
+		fmt.Fprintln(stdout, "no rule changes")
+		return exitOK, nil
+	}
+	for _, change := range changes {
+		fmt.Fprintln(stdout, change)
+	}
+	return exitOK, nil
+}
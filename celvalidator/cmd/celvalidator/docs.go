@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+
+	"github.com/gdbranco/celvalidator"
+)
+
+// runDocs writes the rules of a rule file as Markdown and returns the exit code
+func runDocs(args []string, stdout io.Writer) (int, error) {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	rulesPath := fs.String("rules", "", "rule file (YAML)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if *rulesPath == "" || fs.NArg() != 0 {
+		return exitUsage, errors.New("docs expects --rules")
+	}
+
+	file, err := celvalidator.LoadRuleFileFromYAML(*rulesPath)
+	if err != nil {
+		return exitError, err
+	}
+	if err := celvalidator.WriteRuleDocs(stdout, file.Rules); err != nil {
+		return exitError, err
+	}
+	return exitOK, nil
+}
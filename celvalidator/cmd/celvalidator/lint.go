@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gdbranco/celvalidator"
+)
+
+// runLint reports the rules of a rule file exceeding the complexity thresholds
+// and its suspicious whitespace, warns about deprecated rules, and returns the
+// exit code. Ambiguous booleans and numbers fail the load.
+func runLint(args []string, stdout io.Writer) (int, error) {
+	defaults := celvalidator.DefaultLintThresholds()
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	rulesPath := fs.String("rules", "", "rule file (YAML)")
+	maxLength := fs.Int("max-length", defaults.MaxLength, "maximum expression length, 0 to disable")
+	maxOperators := fs.Int("max-operators", defaults.MaxOperators, "maximum operators per expression, 0 to disable")
+	maxDepth := fs.Int("max-depth", defaults.MaxDepth, "maximum nesting depth, 0 to disable")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if *rulesPath == "" || fs.NArg() != 0 {
+		return exitUsage, errors.New("lint expects --rules")
+	}
+
+	data, err := os.ReadFile(*rulesPath)
+	if err != nil {
+		return exitError, err
+	}
+	file, err := celvalidator.ParseRuleFileYAML(data, celvalidator.WithSourceFile(*rulesPath), celvalidator.WithProvenance(""), celvalidator.WithStrictScalars())
+	if err != nil {
+		return exitError, err
+	}
+
+	for _, d := range file.Deprecations {
+		fmt.Fprintf(stdout, "%s:%d: warning: %s\n", d.Source.File, d.Source.Line, d)
+	}
+	findings := celvalidator.CheckRuleSet(file.Rules, celvalidator.LintThresholds{
+		MaxLength:    *maxLength,
+		MaxOperators: *maxOperators,
+		MaxDepth:     *maxDepth,
+	})
+	for _, f := range findings {
+		fmt.Fprintf(stdout, "%s:%d: %s\n", f.Source.File, f.Source.Line, f)
+	}
+	whitespace := celvalidator.CheckWhitespace(data)
+	for _, f := range whitespace {
+		fmt.Fprintf(stdout, "%s:%d: %s\n", *rulesPath, f.Line, f)
+	}
+	if len(findings) > 0 || len(whitespace) > 0 {
+		return exitRuleErrors, nil
+	}
+	return exitOK, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: celvalidator <command> [arguments]
+
+commands:
+  validate --rules <file> --struct <name> [--op <operation>] [--fail-on error|warning|any] <json>
+                        validate a JSON document against a rule file
+  check-config --rules <file> --type <name> [--schema <file>|--sample <file>] [--op <operation>] [--fail-on error|warning|any] <config>
+                        validate a YAML or JSON config file against a rule file
+  lint --rules <file> [--max-length n] [--max-operators n] [--max-depth n]
+                        report rules too complex to read, waived per rule by lint_waivers
+  watch --rules <file> --fixtures <dir> [--interval d] [--once] [--no-color]
+                        re-lint and re-run fixture tests on every rule or fixture change
+  docs --rules <file>   write the rules of a rule file and their examples as Markdown
+  diff <old> <new>      report the rules added, removed, enabled, disabled or changed between two rule files
+  scaffold --type <package dir>.<type>
+                        write a starter rule file for a struct type
+  verify-audit <file>   verify the hash chain of an audit log
+
+exit codes:
+  0 passed, 1 error, 2 usage, 3 error failures, 4 warning failures, 5 info failures
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitUsage)
+	}
+
+	code := exitOK
+	var err error
+	switch os.Args[1] {
+	case "validate":
+		code, err = runValidate(os.Args[2:], os.Stdout)
+	case "check-config":
+		code, err = runCheckConfig(os.Args[2:], os.Stdout)
+	case "lint":
+		code, err = runLint(os.Args[2:], os.Stdout)
+	case "watch":
+		code, err = runWatch(os.Args[2:], os.Stdout)
+	case "docs":
+		code, err = runDocs(os.Args[2:], os.Stdout)
+	case "diff":
+		code, err = runDiff(os.Args[2:], os.Stdout)
+	case "scaffold":
+		code, err = runScaffold(os.Args[2:], os.Stdout)
+	case "verify-audit":
+		err = runVerifyAudit(os.Args[2:])
+		if err != nil {
+			code = exitError
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitUsage)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		if code == exitUsage {
+			fmt.Fprint(os.Stderr, usage)
+		}
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdbranco/celvalidator"
+)
+
+// runScaffold writes a starter rule file for a struct type of a Go package and
+// returns the exit code
+func runScaffold(args []string, stdout io.Writer) (int, error) {
+	fs := flag.NewFlagSet("scaffold", flag.ContinueOnError)
+	typePath := fs.String("type", "", "package directory and type, e.g. ./pkg/model.User")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+
+	dot := strings.LastIndex(*typePath, ".")
+	if *typePath == "" || dot <= strings.LastIndex(*typePath, "/") || fs.NArg() != 0 {
+		return exitUsage, errors.New("scaffold expects --type <package dir>.<type>")
+	}
+	dir, typeName := (*typePath)[:dot], (*typePath)[dot+1:]
+
+	structs, err := parseStructs(dir)
+	if err != nil {
+		return exitError, err
+	}
+	st, ok := structs[typeName]
+	if !ok {
+		return exitError, fmt.Errorf("struct %s not found in %s", typeName, dir)
+	}
+
+	fields := scaffoldFields("", st, structs, map[string]bool{typeName: true})
+	if err := celvalidator.WriteScaffold(stdout, typeName, fields); err != nil {
+		return exitError, err
+	}
+	return exitOK, nil
+}
+
+// parseStructs returns the struct types declared in the non-test files of dir
+func parseStructs(dir string) (map[string]*ast.StructType, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	structs := map[string]*ast.StructType{}
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					structs[spec.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return structs, nil
+}
+
+// scaffoldFields mirrors celvalidator.ScaffoldFields on parsed source, nested
+// structs of the same package being flattened
+func scaffoldFields(prefix string, st *ast.StructType, structs map[string]*ast.StructType, visiting map[string]bool) []celvalidator.ScaffoldField {
+	var fields []celvalidator.ScaffoldField
+	for _, field := range st.Fields.List {
+		typ := field.Type
+		for {
+			star, ok := typ.(*ast.StarExpr)
+			if !ok {
+				break
+			}
+			typ = star.X
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			// Embedded fields are named after their type
+			ident, ok := typ.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			names = []*ast.Ident{ident}
+		}
+
+		for _, name := range names {
+			if !name.IsExported() {
+				continue
+			}
+			path := prefix + name.Name
+			if ident, ok := typ.(*ast.Ident); ok && structs[ident.Name] != nil && !visiting[ident.Name] {
+				visiting[ident.Name] = true
+				fields = append(fields, scaffoldFields(path+".", structs[ident.Name], structs, visiting)...)
+				delete(visiting, ident.Name)
+				continue
+			}
+			fields = append(fields, celvalidator.ScaffoldField{Name: path, Kind: sourceKind(typ)})
+		}
+	}
+	return fields
+}
+
+func sourceKind(typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.ArrayType:
+		return "list"
+	case *ast.MapType:
+		return "map"
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return "string"
+		case "int", "int8", "int16", "int32", "int64", "rune":
+			return "int"
+		case "uint", "uint8", "uint16", "uint32", "uint64", "byte", "uintptr":
+			return "uint"
+		case "float32", "float64":
+			return "double"
+		case "bool":
+			return "bool"
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gdbranco/celvalidator"
+)
+
+// Exit codes of the validate command, one per outcome class
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitUsage        = 2
+	exitRuleErrors   = 3
+	exitRuleWarnings = 4
+	exitRuleInfos    = 5
+)
+
+// failOnThresholds maps --fail-on values to the lowest failing severity
+var failOnThresholds = map[string]celvalidator.Severity{
+	"error":   celvalidator.SeverityError,
+	"warning": celvalidator.SeverityWarning,
+	"any":     celvalidator.SeverityInfo,
+}
+
+// runValidate validates a JSON document against a rule file and returns the exit code
+func runValidate(args []string, stdout io.Writer) (int, error) {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	rulesPath := fs.String("rules", "", "rule file (YAML)")
+	structName := fs.String("struct", "", "struct name the document represents")
+	operation := fs.String("op", "", "operation, defaults to the struct default_operation")
+	failOn := fs.String("fail-on", "error", "lowest failing severity: error|warning|any")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+
+	threshold, ok := failOnThresholds[*failOn]
+	if !ok {
+		return exitUsage, fmt.Errorf("invalid --fail-on %q", *failOn)
+	}
+	if *rulesPath == "" || *structName == "" || fs.NArg() != 1 {
+		return exitUsage, errors.New("validate expects --rules, --struct and one JSON file")
+	}
+
+	file, err := celvalidator.LoadRuleFileFromYAML(*rulesPath)
+	if err != nil {
+		return exitError, err
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return exitError, err
+	}
+
+	op := resolveOperation(file, *structName, *operation)
+	metadata := celvalidator.ValidationMetadata{StructName: *structName, Operation: op, RuleIndex: -1}
+	rules := celvalidator.GetRulesForStruct(*structName, op, file.Rules)
+	v, err := newValidator(file)
+	if err != nil {
+		return exitError, err
+	}
+	results, err := v.ValidateJSON(data, rules, metadata)
+	if err != nil {
+		return exitError, err
+	}
+
+	return reportResults(stdout, results, threshold), nil
+}
+
+// newValidator creates a validator configured by the rule file validator:
+// block, always evaluating every rule so the whole report is printed
+func newValidator(file *celvalidator.RuleFile) (*celvalidator.Validator, error) {
+	var opts []celvalidator.ValidatorOption
+	if file.Validator != nil {
+		var err error
+		if opts, err = file.Validator.Options(); err != nil {
+			return nil, err
+		}
+	}
+	return celvalidator.NewValidator(append(opts, celvalidator.WithPartialEval())...), nil
+}
+
+// resolveOperation returns op, else the struct default_operation, else "Default"
+func resolveOperation(file *celvalidator.RuleFile, structName, op string) string {
+	if op == "" {
+		op = file.DefaultOperations[structName]
+	}
+	if op == "" {
+		op = "Default"
+	}
+	return op
+}
+
+// reportResults prints results and returns the exit code of the most severe
+// failure at or above threshold. Rules that could not be evaluated count as errors.
+func reportResults(w io.Writer, results []celvalidator.ValidationResult, threshold celvalidator.Severity) int {
+	worst := 0
+	for _, r := range results {
+		status := "PASS"
+		if !r.Passed {
+			status = "FAIL"
+		}
+
+		severity := r.Severity
+		if r.Error != nil {
+			severity = celvalidator.SeverityError
+		}
+
+		fmt.Fprintf(w, "[%s] %-7s %s", status, severity, r.Rule)
+		if r.Message != "" {
+			fmt.Fprintf(w, ": %s", r.Message)
+		}
+		if r.Error != nil {
+			fmt.Fprintf(w, " (%v)", r.Error)
+		}
+		fmt.Fprintln(w)
+		if r.Suggestion != "" {
+			fmt.Fprintf(w, "    suggestion: %s\n", r.Suggestion)
+		}
+		if r.SuggestedValue != nil {
+			fmt.Fprintf(w, "    suggested value: %v\n", r.SuggestedValue)
+		}
+		if r.DocURL != "" {
+			fmt.Fprintf(w, "    see: %s\n", r.DocURL)
+		}
+		for _, issue := range r.Issues {
+			if issue.Severity == celvalidator.IssueWarning {
+				fmt.Fprintf(w, "    %s\n", issue)
+			}
+		}
+
+		if !r.Passed && severity.Rank() >= threshold.Rank() && severity.Rank() > worst {
+			worst = severity.Rank()
+		}
+	}
+
+	switch worst {
+	case celvalidator.SeverityError.Rank():
+		return exitRuleErrors
+	case celvalidator.SeverityWarning.Rank():
+		return exitRuleWarnings
+	case celvalidator.SeverityInfo.Rank():
+		return exitRuleInfos
+	}
+	return exitOK
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gdbranco/celvalidator"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCLI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CLI Suite")
+}
+
+var _ = Describe("reportResults", func() {
+	results := []celvalidator.ValidationResult{
+		{Rule: "a", Passed: true, Severity: celvalidator.SeverityError},
+		{Rule: "b", Passed: false, Severity: celvalidator.SeverityWarning},
+		{Rule: "c", Passed: false, Severity: celvalidator.SeverityInfo},
+	}
+
+	DescribeTable("exit code by --fail-on threshold",
+		func(threshold celvalidator.Severity, expected int) {
+			Expect(reportResults(io.Discard, results, threshold)).To(Equal(expected))
+		},
+		Entry("error", celvalidator.SeverityError, exitOK),
+		Entry("warning", celvalidator.SeverityWarning, exitRuleWarnings),
+		Entry("any", celvalidator.SeverityInfo, exitRuleWarnings),
+	)
+
+	It("treats evaluation errors as error severity", func() {
+		failed := []celvalidator.ValidationResult{{Rule: "x", Error: io.EOF, Severity: celvalidator.SeverityInfo}}
+		Expect(reportResults(io.Discard, failed, celvalidator.SeverityError)).To(Equal(exitRuleErrors))
+	})
+})
+
+var _ = Describe("check-config", func() {
+	var dir string
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	It("validates a config file against rules for a dynamic type", func() {
+		rules := write("rules.yaml", "AppConfig:\n  Default:\n    - rule: \"Replicas >= 2\"\n      enabled: true\n")
+		good := write("good.yaml", "Replicas: 3\n")
+		bad := write("bad.yaml", "Replicas: 1\n")
+
+		code, err := runCheckConfig([]string{"--rules", rules, "--type", "AppConfig", good}, io.Discard)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitOK))
+
+		code, err = runCheckConfig([]string{"--rules", rules, "--type", "AppConfig", bad}, io.Discard)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitRuleErrors))
+	})
+
+	It("rejects both --schema and --sample", func() {
+		code, err := runCheckConfig([]string{"--rules", "r", "--type", "T", "--schema", "s", "--sample", "s", "c"}, io.Discard)
+		Expect(err).To(HaveOccurred())
+		Expect(code).To(Equal(exitUsage))
+	})
+})
+
+var _ = Describe("scaffold", func() {
+	It("writes starter rules for a type parsed from source", func() {
+		dir := GinkgoT().TempDir()
+		src := `package model
+
+type Address struct {
+	City string
+}
+
+type User struct {
+	Name    string
+	Age     int
+	Tags    []string
+	Address *Address
+	secret  string
+}
+`
+		Expect(os.WriteFile(filepath.Join(dir, "model.go"), []byte(src), 0o600)).To(Succeed())
+
+		var out bytes.Buffer
+		code, err := runScaffold([]string{"--type", dir + ".User"}, &out)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitOK))
+
+		type Address struct{ City string }
+		type User struct {
+			Name    string
+			Age     int
+			Tags    []string
+			Address *Address
+		}
+		var fromReflection bytes.Buffer
+		Expect(celvalidator.WriteScaffold(&fromReflection, "User", celvalidator.ScaffoldFields(User{}))).To(Succeed())
+		Expect(out.String()).To(Equal(fromReflection.String()))
+		Expect(out.String()).To(ContainSubstring(`# - rule: "Address.City != ''"`))
+	})
+
+	It("rejects a type without package", func() {
+		code, err := runScaffold([]string{"--type", "User"}, io.Discard)
+		Expect(err).To(HaveOccurred())
+		Expect(code).To(Equal(exitUsage))
+	})
+})
+
+var _ = Describe("lint", func() {
+	It("reports complex rules unless waived", func() {
+		rules := `User:
+  Default:
+    - rule: "Age > 1 && Age < 2 && Age != 3"
+      enabled: true
+    - rule: "Age > 1 && Age < 2 && Name != ''"
+      enabled: true
+      lint_waivers: [max_operators]
+`
+		path := filepath.Join(GinkgoT().TempDir(), "rules.yaml")
+		Expect(os.WriteFile(path, []byte(rules), 0o600)).To(Succeed())
+
+		var out bytes.Buffer
+		code, err := runLint([]string{"--rules", path, "--max-operators", "4"}, &out)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitRuleErrors))
+		Expect(out.String()).To(Equal(path + `:3: User.Default "Age > 1 && Age < 2 && Age != 3": max_operators 5 exceeds 4, consider splitting the rule into a then chain` + "\n"))
+
+		code, err = runLint([]string{"--rules", path}, io.Discard)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitOK))
+	})
+
+	It("reports suspicious whitespace and rejects ambiguous scalars", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "rules.yaml")
+		Expect(os.WriteFile(path, []byte("User:\n  Default:\n    - rule: \"Age > 1\"\n      enabled: true \n"), 0o600)).To(Succeed())
+
+		var out bytes.Buffer
+		code, err := runLint([]string{"--rules", path}, &out)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitRuleErrors))
+		Expect(out.String()).To(Equal(path + ":4: trailing whitespace\n"))
+
+		Expect(os.WriteFile(path, []byte("User:\n  Default:\n    - rule: \"Age > 1\"\n      enabled: yes\n"), 0o600)).To(Succeed())
+		code, err = runLint([]string{"--rules", path}, io.Discard)
+		Expect(err).To(MatchError(celvalidator.ErrAmbiguousScalar))
+		Expect(code).To(Equal(exitError))
+	})
+})
+
+var _ = Describe("watch", func() {
+	var dir, fixtures string
+
+	write := func(path, content string) {
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		fixtures = filepath.Join(dir, "fixtures")
+		Expect(os.Mkdir(fixtures, 0o700)).To(Succeed())
+		write(filepath.Join(dir, "rules.yaml"), `User:
+  Default:
+    - id: adult
+      rule: "Age >= 18"
+      enabled: true
+    - rule: "Name != ''"
+      enabled: true
+`)
+		write(filepath.Join(fixtures, "adult.yaml"), `struct: User
+document:
+  Name: Ann
+  Age: 30
+expect:
+  adult: pass
+  "Name != ''": pass
+`)
+	})
+
+	It("runs the fixtures once", func() {
+		var out bytes.Buffer
+		code, err := runWatch([]string{"--rules", filepath.Join(dir, "rules.yaml"), "--fixtures", fixtures, "--once", "--no-color"}, &out)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitOK))
+		Expect(out.String()).To(Equal("PASS adult.yaml\n1 fixtures, 0 failed, 0 failed examples, 0 lint findings\n"))
+	})
+
+	It("prints a colored diff of mismatching outcomes", func() {
+		write(filepath.Join(fixtures, "minor.yaml"), "struct: User\ndocument:\n  Name: Bob\n  Age: 12\nexpect:\n  adult: pass\n  missing: fail\n")
+
+		var out bytes.Buffer
+		code, err := runWatch([]string{"--rules", filepath.Join(dir, "rules.yaml"), "--fixtures", fixtures, "--once"}, &out)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitRuleErrors))
+		Expect(out.String()).To(ContainSubstring(colorRed + "FAIL" + colorReset + " minor.yaml\n"))
+		Expect(out.String()).To(ContainSubstring(colorRed + "  - adult: pass" + colorReset + "\n" + colorGreen + "  + adult: fail" + colorReset))
+		Expect(out.String()).To(ContainSubstring("  + missing: not evaluated"))
+		Expect(out.String()).To(ContainSubstring("2 fixtures, 1 failed, 0 failed examples, 0 lint findings"))
+	})
+
+	It("verifies the examples of rules", func() {
+		write(filepath.Join(dir, "rules.yaml"), `User:
+  Default:
+    - id: adult
+      rule: "Age >= 18"
+      enabled: true
+      examples:
+        pass: [{Age: 18}]
+        fail: [{Age: 30}]
+    - rule: "Name != ''"
+      enabled: true
+`)
+		var out bytes.Buffer
+		code, err := runWatch([]string{"--rules", filepath.Join(dir, "rules.yaml"), "--fixtures", fixtures, "--once", "--no-color"}, &out)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitRuleErrors))
+		Expect(out.String()).To(ContainSubstring(`rules.yaml:3: User.Default "Age >= 18": example {"Age":30} should fail, got pass` + "\n"))
+		Expect(out.String()).To(ContainSubstring("PASS adult.yaml\n1 fixtures, 0 failed, 1 failed examples, 0 lint findings"))
+	})
+
+	It("runs again when a watched file changes", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		var runs atomic.Int32
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			watchFiles(ctx, []string{filepath.Join(dir, "rules.yaml"), fixtures}, 10*time.Millisecond, func() { runs.Add(1) })
+		}()
+		Eventually(runs.Load).Should(Equal(int32(1)))
+
+		write(filepath.Join(fixtures, "new.yaml"), "struct: User\n")
+		Eventually(runs.Load).Should(Equal(int32(2)))
+		Consistently(runs.Load, 50*time.Millisecond).Should(Equal(int32(2)))
+
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+})
+
+var _ = Describe("docs", func() {
+	It("writes the rules and their examples as Markdown", func() {
+		rules := `User:
+  Default:
+    - rule: "Age >= 18"
+      enabled: true
+      message: must be an adult
+      examples:
+        pass: [{Age: 18}]
+        fail: [{Age: 10}]
+      then:
+        - rule: "Name != ''"
+          enabled: true
+`
+		path := filepath.Join(GinkgoT().TempDir(), "rules.yaml")
+		Expect(os.WriteFile(path, []byte(rules), 0o600)).To(Succeed())
+
+		var out bytes.Buffer
+		code, err := runDocs([]string{"--rules", path}, &out)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitOK))
+		Expect(out.String()).To(Equal("## User\n\n### Default\n\n- `Age >= 18`: must be an adult\n  - passes `{\"Age\":18}`\n  - fails `{\"Age\":10}`\n  - `Name != ''`\n"))
+	})
+})
+
+var _ = Describe("diff", func() {
+	It("reports semantic rule changes", func() {
+		dir := GinkgoT().TempDir()
+		oldPath, newPath := filepath.Join(dir, "old.yaml"), filepath.Join(dir, "new.yaml")
+		Expect(os.WriteFile(oldPath, []byte(`User:
+  Default:
+    - id: adult
+      rule: "Age >= 18"
+      enabled: true
+      message: too young
+    - rule: "Email != ''"
+      enabled: true
+`), 0o600)).To(Succeed())
+		Expect(os.WriteFile(newPath, []byte(`User:
+  Default:
+    - id: adult
+      rule: "Age >= 21"
+      enabled: false
+      message: too young
+    - rule: "Name != ''"
+      enabled: true
+`), 0o600)).To(Succeed())
+
+		var out bytes.Buffer
+		code, err := runDiff([]string{oldPath, newPath}, &out)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitOK))
+		Expect(out.String()).To(Equal(`~ User.Default adult: expression "Age >= 18" -> "Age >= 21"
+~ User.Default adult: disabled
++ User.Default "Name != ''"
+- User.Default "Email != ''"
+`))
+
+		out.Reset()
+		code, err = runDiff([]string{oldPath, oldPath}, &out)
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal(exitOK))
+		Expect(out.String()).To(Equal("no rule changes\n"))
+	})
+})
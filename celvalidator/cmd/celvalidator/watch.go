@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdbranco/celvalidator"
+	"gopkg.in/yaml.v3"
+)
+
+// ANSI colors of the watch output
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorReset = "\x1b[0m"
+)
+
+// fixture is a document validated against the rule file, with the expected
+// outcome of its rules
+type fixture struct {
+	Struct    string         `yaml:"struct"`
+	Operation string         `yaml:"op"`
+	Document  map[string]any `yaml:"document"`
+	// Expect maps rule ids, or the expression of rules without id, to pass or fail
+	Expect map[string]string `yaml:"expect"`
+}
+
+// watchConfig is the parsed arguments of the watch command
+type watchConfig struct {
+	rules    string
+	fixtures string
+	interval time.Duration
+	color    bool
+}
+
+// runWatch lints the rule file and runs the fixture tests on every change of
+// the rule file or fixtures, until interrupted. With --once it runs a single
+// time and returns the exit code.
+func runWatch(args []string, stdout io.Writer) (int, error) {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	rulesPath := fs.String("rules", "", "rule file (YAML)")
+	fixtures := fs.String("fixtures", "", "directory of fixture files (YAML)")
+	interval := fs.Duration("interval", 500*time.Millisecond, "polling interval")
+	once := fs.Bool("once", false, "run once and exit")
+	noColor := fs.Bool("no-color", os.Getenv("NO_COLOR") != "", "disable colored output")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if *rulesPath == "" || *fixtures == "" || fs.NArg() != 0 {
+		return exitUsage, errors.New("watch expects --rules and --fixtures")
+	}
+
+	cfg := watchConfig{rules: *rulesPath, fixtures: *fixtures, interval: *interval, color: !*noColor}
+	if *once {
+		return runCycle(cfg, stdout), nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	watchFiles(ctx, []string{cfg.rules, cfg.fixtures}, cfg.interval, func() {
+		fmt.Fprintf(stdout, "\n--- %s ---\n", time.Now().Format(time.TimeOnly))
+		runCycle(cfg, stdout)
+	})
+	return exitOK, nil
+}
+
+// watchFiles calls run once, then after every change of the files under
+// paths, polling their modification times until ctx is done
+func watchFiles(ctx context.Context, paths []string, interval time.Duration, run func()) {
+	last := fingerprint(paths)
+	run()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if current := fingerprint(paths); current != last {
+			last = current
+			run()
+		}
+	}
+}
+
+// fingerprint summarizes the names, sizes and modification times of the files under paths
+func fingerprint(paths []string) string {
+	var b strings.Builder
+	for _, root := range paths {
+		_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				fmt.Fprintf(&b, "%s:%d:%d\n", path, info.Size(), info.ModTime().UnixNano())
+			}
+			return nil
+		})
+	}
+	return b.String()
+}
+
+// runCycle lints the rule file, verifies the examples of its rules and runs the
+// fixtures, returning exitRuleErrors when a lint check, example or fixture fails
+func runCycle(cfg watchConfig, w io.Writer) int {
+	file, err := celvalidator.LoadRuleFileFromYAML(cfg.rules, celvalidator.WithProvenance(""))
+	if err != nil {
+		fmt.Fprintf(w, "%s\n", cfg.paint(colorRed, "error: "+err.Error()))
+		return exitError
+	}
+
+	findings := celvalidator.CheckRuleSet(file.Rules, celvalidator.DefaultLintThresholds())
+	for _, f := range findings {
+		fmt.Fprintf(w, "%s:%d: %s\n", f.Source.File, f.Source.Line, f)
+	}
+
+	v, err := newValidator(file)
+	if err != nil {
+		fmt.Fprintf(w, "%s\n", cfg.paint(colorRed, "error: "+err.Error()))
+		return exitError
+	}
+	examples := v.VerifyExamples(file.Rules)
+	for _, f := range examples {
+		fmt.Fprintf(w, "%s %s:%d: %s\n", cfg.paint(colorRed, "FAIL"), f.Source.File, f.Source.Line, f)
+	}
+
+	paths, err := fixturePaths(cfg.fixtures)
+	if err != nil {
+		fmt.Fprintf(w, "%s\n", cfg.paint(colorRed, "error: "+err.Error()))
+		return exitError
+	}
+	failed := 0
+	for _, path := range paths {
+		if !cfg.runFixture(w, file, path) {
+			failed++
+		}
+	}
+
+	summary := fmt.Sprintf("%d fixtures, %d failed, %d failed examples, %d lint findings", len(paths), failed, len(examples), len(findings))
+	if failed > 0 || len(examples) > 0 || len(findings) > 0 {
+		fmt.Fprintln(w, cfg.paint(colorRed, summary))
+		return exitRuleErrors
+	}
+	fmt.Fprintln(w, cfg.paint(colorGreen, summary))
+	return exitOK
+}
+
+// fixturePaths returns the YAML files of the fixtures directory in name order
+func fixturePaths(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if ext := filepath.Ext(entry.Name()); !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// runFixture validates a fixture and prints the expected and actual outcomes
+// of mismatching rules as a diff, reporting whether it passed
+func (cfg watchConfig) runFixture(w io.Writer, file *celvalidator.RuleFile, path string) bool {
+	name := filepath.Base(path)
+	fail := func(err error) bool {
+		fmt.Fprintf(w, "%s %s: %v\n", cfg.paint(colorRed, "FAIL"), name, err)
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fail(err)
+	}
+	var fx fixture
+	if err := yaml.Unmarshal(data, &fx); err != nil {
+		return fail(fmt.Errorf("unmarshalling YAML: %w", err))
+	}
+	if fx.Struct == "" {
+		return fail(errors.New("fixture has no struct"))
+	}
+	document, err := json.Marshal(fx.Document)
+	if err != nil {
+		return fail(err)
+	}
+
+	op := resolveOperation(file, fx.Struct, fx.Operation)
+	metadata := celvalidator.ValidationMetadata{StructName: fx.Struct, Operation: op, RuleIndex: -1}
+	v, err := newValidator(file)
+	if err != nil {
+		return fail(err)
+	}
+	results, err := v.ValidateJSON(document, celvalidator.GetRulesForStruct(fx.Struct, op, file.Rules), metadata)
+	if err != nil {
+		return fail(err)
+	}
+
+	actual := map[string]string{}
+	for _, r := range results {
+		key := r.ID
+		if key == "" {
+			key = r.Rule
+		}
+		switch {
+		case r.Error != nil:
+			actual[key] = "error"
+		case r.Passed:
+			actual[key] = "pass"
+		default:
+			actual[key] = "fail"
+		}
+	}
+
+	keys := make([]string, 0, len(fx.Expect))
+	for key := range fx.Expect {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var diff []string
+	for _, key := range keys {
+		got, ok := actual[key]
+		if !ok {
+			got = "not evaluated"
+		}
+		if got != fx.Expect[key] {
+			diff = append(diff,
+				cfg.paint(colorRed, fmt.Sprintf("  - %s: %s", key, fx.Expect[key])),
+				cfg.paint(colorGreen, fmt.Sprintf("  + %s: %s", key, got)))
+		}
+	}
+
+	if len(diff) > 0 {
+		fmt.Fprintf(w, "%s %s\n%s\n", cfg.paint(colorRed, "FAIL"), name, strings.Join(diff, "\n"))
+		return false
+	}
+	fmt.Fprintf(w, "%s %s\n", cfg.paint(colorGreen, "PASS"), name)
+	return true
+}
+
+// paint colors s when colors are enabled
+func (cfg watchConfig) paint(color, s string) string {
+	if !cfg.color {
+		return s
+	}
+	return color + s + colorReset
+}
@@ -0,0 +1,120 @@
+package celvalidator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// ErrRuleCompile matches, with errors.Is, the error of a result whose rule
+// could not be compiled into a program
+var ErrRuleCompile = errors.New("rule compile error")
+
+// compileError marks err as a compile error, keeping its message
+type compileError struct {
+	err error
+}
+
+func (e compileError) Error() string {
+	return e.err.Error()
+}
+
+func (e compileError) Unwrap() error {
+	return e.err
+}
+
+func (e compileError) Is(target error) bool {
+	return target == ErrRuleCompile
+}
+
+// CompileErrorStat counts a distinct compile error of a rule since process start
+type CompileErrorStat struct {
+	RuleHash  string    `json:"rule_hash"`
+	Rule      string    `json:"rule"`
+	Error     string    `json:"error"`
+	Count     uint64    `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// ruleHash identifies a rule expression across processes
+func ruleHash(rule string) string {
+	sum := sha256.Sum256([]byte(rule))
+	return hex.EncodeToString(sum[:6])
+}
+
+// recordCompileError counts a compile error, d.mu must be held
+func (d *debugState) recordCompileError(r ValidationResult, now time.Time) {
+	hash := ruleHash(r.Rule)
+	msg := errorString(r.Error)
+	key := hash + "\x00" + msg
+
+	stat, ok := d.compileErrors[key]
+	if !ok {
+		evictOne(d.compileErrors, debugMaxEntries)
+		stat = &CompileErrorStat{RuleHash: hash, Rule: r.Rule, Error: msg, FirstSeen: now}
+		d.compileErrors[key] = stat
+	}
+	stat.Count++
+	stat.LastSeen = now
+	d.compileErrorTotal++
+}
+
+// compileErrorStats copies the counters, most frequent first, d.mu must be held
+func (d *debugState) compileErrorStats() []CompileErrorStat {
+	stats := make([]CompileErrorStat, 0, len(d.compileErrors))
+	for _, stat := range d.compileErrors {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].RuleHash < stats[j].RuleHash
+	})
+	return stats
+}
+
+// CompileErrorStats returns the distinct compile errors encountered since
+// process start by validators created WithDebugRecording, most frequent first
+func CompileErrorStats() []CompileErrorStat {
+	debug.mu.Lock()
+	defer debug.mu.Unlock()
+	return debug.compileErrorStats()
+}
+
+// LogCompileErrors logs a summary line every interval in which new compile
+// errors were encountered, until ctx is done. It is meant to be run in its own
+// goroutine so a bad rule push shows up in fleet logs.
+func LogCompileErrors(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var logged uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		debug.mu.Lock()
+		total := debug.compileErrorTotal
+		stats := debug.compileErrorStats()
+		debug.mu.Unlock()
+
+		if total == logged {
+			continue
+		}
+		attrs := []any{"new", total - logged, "total", total, "distinct", len(stats)}
+		if len(stats) > 0 {
+			attrs = append(attrs, "top_rule_hash", stats[0].RuleHash, "top_error", stats[0].Error, "top_count", stats[0].Count)
+		}
+		logger.WarnContext(ctx, "celvalidator compile errors", attrs...)
+		logged = total
+	}
+}
@@ -0,0 +1,79 @@
+package celvalidator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+)
+
+// CompiledRuleSet is a rule set compiled once for a struct type, safe for
+// concurrent use. Evaluating it skips parsing and type checking, and program
+// planning unless resolvers are registered, as they are bound per validation.
+type CompiledRuleSet struct {
+	v        *Validator
+	typ      reflect.Type
+	env      *cel.Env
+	rules    []RuleEntry
+	compiled map[string]*compiledRule
+}
+
+// Compile compiles rules, including Then children, for objects of obj's type.
+// Field types are inferred from obj, so nested pointers of the sample should be
+// set for the fields under them to be declared. Rules failing to compile are
+// reported when evaluated, like with Validate.
+func (v *Validator) Compile(obj any, rules []RuleEntry) (*CompiledRuleSet, error) {
+	env, vars, err := v.buildEnv(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &CompiledRuleSet{
+		v:        v,
+		typ:      reflect.TypeOf(obj),
+		env:      env,
+		rules:    copyRuleEntries(rules),
+		compiled: map[string]*compiledRule{},
+	}
+	prgOpts := v.programOptions()
+	for _, entry := range flattenRuleTree(rules) {
+		if _, ok := cs.compiled[entry.Rule]; ok {
+			continue
+		}
+		c := compileRule(env, entry.Rule, vars)
+		if (c.iss == nil || c.iss.Err() == nil) && len(v.resolvers) == 0 {
+			// Program errors are reported on evaluation, planning again
+			c.prg, _ = env.Program(c.ast, prgOpts...)
+			if v.checkedArith {
+				c.tracked, _ = env.Program(c.ast, trackedProgramOptions(prgOpts)...)
+			}
+		}
+		cs.compiled[entry.Rule] = c
+	}
+	return cs, nil
+}
+
+// Evaluate evaluates the compiled rules against obj
+func (cs *CompiledRuleSet) Evaluate(obj any) ([]ValidationResult, error) {
+	return cs.EvaluateContext(context.Background(), obj, ValidationMetadata{StructName: getStructName(obj), RuleIndex: -1})
+}
+
+// EvaluateContext evaluates the compiled rules against obj like ValidateContext
+func (cs *CompiledRuleSet) EvaluateContext(ctx context.Context, obj any, metadata ValidationMetadata) (results []ValidationResult, err error) {
+	metadata = cs.v.identify(obj, metadata)
+	if cs.v.recover {
+		defer cs.v.recoverValidation(metadata, &results, &err)
+	}
+	if t := reflect.TypeOf(obj); t != cs.typ {
+		return nil, fmt.Errorf("rule set compiled for %v, got %v", cs.typ, t)
+	}
+
+	vars := cs.v.objectFields(obj)
+	cs.v.addNativeVariable(vars, obj)
+
+	e := cs.v.newEvaluation(ctx, cs.env, vars, metadata)
+	e.compiled = cs.compiled
+	err = e.evalRules(cs.rules, metadata)
+	return e.results, e.finish(err)
+}
@@ -0,0 +1,56 @@
+package celvalidator
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CompiledRuleSet", func() {
+	rules := []RuleEntry{
+		{Rule: "Age > 18", Enabled: true, FailureMessage: "too young", Then: []RuleEntry{
+			{Rule: "Email != ''", Enabled: true},
+		}},
+		{Rule: "Unknown == 1", Enabled: true},
+	}
+
+	It("evaluates like Validate without recompiling", func() {
+		v := NewValidator(WithPartialEval())
+		compiled, err := v.Compile(Sample{}, rules)
+		Expect(err).To(BeNil())
+
+		for _, obj := range []Sample{{Age: 30, Email: "a@b.c"}, {Age: 10}} {
+			got, err := compiled.Evaluate(obj)
+			Expect(err).To(BeNil())
+			want, err := v.Validate(obj, rules, ValidationMetadata{StructName: "Sample", RuleIndex: -1})
+			Expect(err).To(BeNil())
+			Expect(EqualReports(got, want)).To(BeTrue())
+		}
+	})
+
+	It("is safe for concurrent use", func() {
+		compiled, err := NewValidator(WithPartialEval()).Compile(Sample{}, rules)
+		Expect(err).To(BeNil())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(age int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				results, err := compiled.Evaluate(Sample{Age: age})
+				Expect(err).To(BeNil())
+				Expect(results[0].Passed).To(Equal(age > 18))
+			}(i * 5)
+		}
+		wg.Wait()
+	})
+
+	It("rejects objects of another type", func() {
+		compiled, err := NewValidator().Compile(Sample{}, rules)
+		Expect(err).To(BeNil())
+		_, err = compiled.Evaluate(&Sample{})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,180 @@
+package celvalidator
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// validatorConfigKey is the reserved top-level key of the validator
+// configuration in rule and config files
+const validatorConfigKey = "validator"
+
+// ValidatorConfig configures a Validator from YAML, under a validator: block of
+// a rule file or of a separate config file
+type ValidatorConfig struct {
+	PartialEval     bool `yaml:"partial_eval"`
+	FailFast        bool `yaml:"fail_fast"`
+	SuccessMessages bool `yaml:"success_messages"`
+	NestedFields    bool `yaml:"nested_fields"`
+	Self            bool `yaml:"self"`
+	// NativeTypes names the variable exposing the object with its Go type
+	NativeTypes string `yaml:"native_types"`
+	// Methods exposes the methods of native types, see WithMethods
+	Methods bool `yaml:"methods"`
+	// AdaptiveOrdering runs likely failing rules first in fail-fast mode
+	AdaptiveOrdering bool `yaml:"adaptive_ordering"`
+	// RuleTimeout is the timeout of rules without their own, e.g. 100ms
+	RuleTimeout time.Duration `yaml:"rule_timeout"`
+	// RuleCostLimit and TotalCostLimit cap the actual CEL cost of a rule and of a validation
+	RuleCostLimit  uint64 `yaml:"rule_cost_limit"`
+	TotalCostLimit uint64 `yaml:"total_cost_limit"`
+	// Concurrency is the number of workers evaluating top-level rules
+	Concurrency int `yaml:"concurrency"`
+	// FiniteFloats reports rules referencing NaN or infinite fields as errors
+	FiniteFloats bool `yaml:"finite_floats"`
+	// Recover reports panics during validations as error results
+	Recover bool `yaml:"recover"`
+	// ErrorPolicy is system_errors (default) or failures
+	ErrorPolicy string `yaml:"error_policy"`
+	// FieldNaming is go (default), json or tag, see FieldNaming
+	FieldNaming string `yaml:"field_naming"`
+	// Extensions enables decimal, money, checked_arithmetic and std_helpers, and
+	// cel-go's strings, math, sets, lists, encoders and optional_types
+	Extensions []string `yaml:"extensions"`
+	// Globals declares variables available to every rule, see WithGlobals
+	Globals map[string]any `yaml:"globals"`
+	// Now declares now, the system time of the validation, see WithClock
+	Now bool `yaml:"now"`
+	// Degradation is the DegradationMode of a DegradingProvider, see ParseDegradationMode
+	Degradation string `yaml:"degradation"`
+}
+
+var configErrorPolicies = map[string]ErrorPolicy{
+	"":              ErrorsAsSystemErrors,
+	"system_errors": ErrorsAsSystemErrors,
+	"failures":      ErrorsAsFailures,
+}
+
+var configFieldNamings = map[string]FieldNaming{
+	"":     FieldNamesGo,
+	"go":   FieldNamesGo,
+	"json": FieldNamesJSON,
+	"tag":  FieldNamesTag,
+}
+
+var configExtensions = map[string]ValidatorOption{
+	"decimal":            WithDecimal(),
+	"money":              WithMoney(),
+	"checked_arithmetic": WithCheckedArithmetic(),
+	"std_helpers":        WithStdHelpers(),
+}
+
+// Options returns the validator options described by the configuration
+func (c ValidatorConfig) Options() ([]ValidatorOption, error) {
+	var opts []ValidatorOption
+	if c.PartialEval {
+		opts = append(opts, WithPartialEval())
+	}
+	if c.FailFast {
+		opts = append(opts, WithFailFast())
+	}
+	if c.SuccessMessages {
+		opts = append(opts, WithSuccessMessages())
+	}
+	if c.NestedFields {
+		opts = append(opts, WithNestedFields())
+	}
+	if c.Self {
+		opts = append(opts, WithSelf())
+	}
+	if c.NativeTypes != "" {
+		opts = append(opts, WithNativeTypes(c.NativeTypes))
+	}
+	if c.Methods {
+		opts = append(opts, WithMethods())
+	}
+	if len(c.Globals) > 0 {
+		opts = append(opts, WithGlobals(c.Globals))
+	}
+	if c.Now {
+		opts = append(opts, WithClock(SystemClock))
+	}
+	if c.AdaptiveOrdering {
+		opts = append(opts, WithRuleOptimizer(NewRuleOptimizer()))
+	}
+	if c.RuleTimeout > 0 {
+		opts = append(opts, WithRuleTimeout(c.RuleTimeout))
+	}
+	if c.RuleCostLimit > 0 || c.TotalCostLimit > 0 {
+		opts = append(opts, WithCostLimit(c.RuleCostLimit, c.TotalCostLimit))
+	}
+	if c.FiniteFloats {
+		opts = append(opts, WithFiniteFloats())
+	}
+	if c.Recover {
+		opts = append(opts, WithRecover())
+	}
+	if c.Concurrency > 1 {
+		opts = append(opts, WithConcurrency(c.Concurrency))
+	}
+
+	policy, ok := configErrorPolicies[c.ErrorPolicy]
+	if !ok {
+		return nil, fmt.Errorf("unknown error_policy %q", c.ErrorPolicy)
+	}
+	opts = append(opts, WithErrorPolicy(policy))
+
+	naming, ok := configFieldNamings[c.FieldNaming]
+	if !ok {
+		return nil, fmt.Errorf("unknown field_naming %q", c.FieldNaming)
+	}
+	opts = append(opts, WithFieldNaming(naming))
+
+	for _, name := range c.Extensions {
+		if lib, ok := celExtensions[name]; ok {
+			opts = append(opts, WithExtensions(lib))
+			continue
+		}
+		ext, ok := configExtensions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown extension %q", name)
+		}
+		opts = append(opts, ext)
+	}
+	return opts, nil
+}
+
+// LoadValidatorConfig loads the validator: block of a rule or config file,
+// returning a zero configuration when the file has none
+func LoadValidatorConfig(path string) (*ValidatorConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var raw struct {
+		Validator ValidatorConfig `yaml:"validator"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshalling config: %w", err)
+	}
+	return &raw.Validator, nil
+}
+
+// NewValidatorFromConfig creates a validator configured by the validator: block
+// of a rule or config file, opts being applied after the configuration for
+// settings only available in code such as resolvers and audit sinks
+func NewValidatorFromConfig(path string, opts ...ValidatorOption) (*Validator, error) {
+	cfg, err := LoadValidatorConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	cfgOpts, err := cfg.Options()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return NewValidator(append(cfgOpts, opts...)...), nil
+}
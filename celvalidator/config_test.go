@@ -0,0 +1,66 @@
+package celvalidator
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validator configuration", func() {
+	ruleFile := `validator:
+  partial_eval: true
+  fail_fast: true
+  error_policy: failures
+  extensions: [decimal]
+Sample:
+  Create:
+    - rule: "Unknown == 1"
+      enabled: true
+    - rule: "Age > 40"
+      enabled: true
+    - rule: "Age > 50"
+      enabled: true
+`
+
+	It("reads the validator block of a rule file", func() {
+		file, err := ParseRuleFileYAML([]byte(ruleFile))
+		Expect(err).To(BeNil())
+		Expect(file.Rules).To(HaveLen(1))
+		Expect(file.Validator).To(Equal(&ValidatorConfig{
+			PartialEval: true,
+			FailFast:    true,
+			ErrorPolicy: "failures",
+			Extensions:  []string{"decimal"},
+		}))
+	})
+
+	It("creates a configured validator", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "rules.yaml")
+		Expect(os.WriteFile(path, []byte(ruleFile), 0o600)).To(Succeed())
+
+		v, err := NewValidatorFromConfig(path)
+		Expect(err).To(BeNil())
+		Expect(v.partialEval).To(BeTrue())
+		Expect(v.failFast).To(BeTrue())
+		Expect(v.errorPolicy).To(Equal(ErrorsAsFailures))
+		Expect(v.decimal).To(BeTrue())
+
+		file, err := LoadRuleFileFromYAML(path)
+		Expect(err).To(BeNil())
+		obj := Sample{Age: 30}
+		results, err := v.Validate(obj, GetRulesFor(obj, "Create", file.Rules), ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(1))
+	})
+
+	It("rejects unknown settings values", func() {
+		_, err := ValidatorConfig{Extensions: []string{"nope"}}.Options()
+		Expect(err).To(MatchError(ContainSubstring("nope")))
+		_, err = ValidatorConfig{ErrorPolicy: "ignore"}.Options()
+		Expect(err).To(HaveOccurred())
+		_, err = ValidatorConfig{FieldNaming: "yaml"}.Options()
+		Expect(err).To(MatchError(ContainSubstring("field_naming")))
+	})
+})
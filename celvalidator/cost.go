@@ -0,0 +1,83 @@
+package celvalidator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker"
+	"github.com/google/cel-go/interpreter"
+)
+
+var (
+	// ErrRuleCostLimit is reported on the result of a rule exceeding the per-rule cost limit
+	ErrRuleCostLimit = errors.New("rule cost limit exceeded")
+	// ErrTotalCostLimit is returned when the rules of a validation exceed the total cost limit
+	ErrTotalCostLimit = errors.New("total cost limit exceeded")
+)
+
+// WithCostLimit caps the actual CEL cost of a single rule and of all the rules
+// of a validation, 0 meaning no limit. A rule exceeding perRule is aborted and
+// reported like a timed out rule, a validation exceeding total stops with the
+// results evaluated so far.
+func WithCostLimit(perRule, total uint64) ValidatorOption {
+	return func(v *Validator) {
+		v.ruleCostLimit = perRule
+		v.totalCostLimit = total
+	}
+}
+
+// costError maps cel-go's cost limit cancellation to ErrRuleCostLimit
+func (v *Validator) costError(err error) error {
+	var cancelled interpreter.EvalCancelledError
+	if errors.As(err, &cancelled) && cancelled.Cause == interpreter.CostLimitExceeded {
+		return fmt.Errorf("%w: limit %d", ErrRuleCostLimit, v.ruleCostLimit)
+	}
+	return err
+}
+
+// addCost adds the cost of a rule to the validation, returning
+// ErrTotalCostLimit once the total cost limit is exceeded
+func (e *evaluation) addCost(cost uint64) error {
+	total := e.cost.Add(cost)
+	if limit := e.v.totalCostLimit; limit > 0 && total > limit {
+		return fmt.Errorf("%w: %d exceeds %d", ErrTotalCostLimit, total, limit)
+	}
+	return nil
+}
+
+// actualCost returns the cost tracked while evaluating a rule
+func actualCost(details *cel.EvalDetails) uint64 {
+	if cost := details.ActualCost(); cost != nil {
+		return *cost
+	}
+	return 0
+}
+
+// estimatedValueSize is the size assumed for strings, lists and maps whose
+// size is unknown when estimating rule cost
+const estimatedValueSize = 1024
+
+// estimateRuleCost returns the worst case estimated cost of a rule expression
+func estimateRuleCost(env *cel.Env, rule string) (uint64, error) {
+	ast, iss := env.Compile(rule)
+	if iss != nil && iss.Err() != nil {
+		return 0, fmt.Errorf("compiling %q: %w", rule, iss.Err())
+	}
+	cost, err := env.EstimateCost(ast, boundedSizeEstimator{})
+	if err != nil {
+		return 0, fmt.Errorf("estimating cost of %q: %w", rule, err)
+	}
+	return cost.Max, nil
+}
+
+// boundedSizeEstimator bounds unknown sizes with estimatedValueSize
+type boundedSizeEstimator struct{}
+
+func (boundedSizeEstimator) EstimateSize(checker.AstNode) *checker.SizeEstimate {
+	return &checker.SizeEstimate{Min: 0, Max: estimatedValueSize}
+}
+
+func (boundedSizeEstimator) EstimateCallCost(string, string, *checker.AstNode, []checker.AstNode) *checker.CallEstimate {
+	return nil
+}
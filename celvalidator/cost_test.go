@@ -0,0 +1,115 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cost limits", func() {
+	md := ValidationMetadata{StructName: "Sample"}
+	// runawayRule iterates 10^6 times over resolved lists
+	runawayRule := "ext.outer.all(a, ext.inner.all(b, a + b >= 0))"
+	lists := map[string][]any{"outer": make([]any, 1000), "inner": make([]any, 1000)}
+	for _, list := range lists {
+		for i := range list {
+			list[i] = i
+		}
+	}
+	withLists := WithVariableResolver("ext", VariableResolverFunc(func(rc ResolverContext, name string) (any, error) {
+		return lists[name], nil
+	}))
+
+	It("reports the actual cost of each rule", func() {
+		rules := []RuleEntry{
+			{Rule: "Age == 0", Enabled: true},
+			{Rule: "[1, 2, 3].all(x, x > Age)", Enabled: true},
+		}
+		results, err := NewValidator().Validate(Sample{}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Cost).To(BeNumerically(">", 0))
+		Expect(results[1].Cost).To(BeNumerically(">", results[0].Cost))
+	})
+
+	It("aborts rules exceeding the per-rule limit", func() {
+		rules := []RuleEntry{
+			{Rule: runawayRule, Enabled: true},
+			{Rule: "Age == 0", Enabled: true},
+		}
+		results, err := NewValidator(withLists, WithPartialEval(), WithCostLimit(1000, 0)).Validate(Sample{}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Error).To(MatchError(ErrRuleCostLimit))
+		Expect(results[0].Outcome).To(Equal(OutcomeError))
+		Expect(results[1].Passed).To(BeTrue())
+	})
+
+	It("tracks costs with checked arithmetic", func() {
+		rules := []RuleEntry{
+			{Rule: runawayRule, Enabled: true},
+			{Rule: "double(Age) * 1e308 * 10.0 > 0.0", Enabled: true},
+		}
+		results, err := NewValidator(withLists, WithPartialEval(), WithCheckedArithmetic(), WithCostLimit(1000, 0)).Validate(Sample{Age: 1}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Error).To(MatchError(ErrRuleCostLimit))
+		Expect(results[1].Error).To(MatchError(ErrArithmeticOverflow))
+		Expect(results[1].Cost).To(BeNumerically(">", 0))
+	})
+
+	It("stops at a rule exceeding the per-rule limit without partial evaluation", func() {
+		rules := []RuleEntry{
+			{Rule: runawayRule, Enabled: true},
+			{Rule: "Age == 0", Enabled: true},
+		}
+		results, err := NewValidator(withLists, WithCostLimit(1000, 0)).Validate(Sample{}, rules, md)
+		Expect(err).To(MatchError(ErrRuleCostLimit))
+		Expect(results).To(HaveLen(1))
+	})
+
+	It("stops validations exceeding the total limit", func() {
+		rules := []RuleEntry{
+			{Rule: "[1, 2, 3].all(x, x > Age)", Enabled: true, Then: []RuleEntry{
+				{Rule: "[4, 5, 6].all(x, x > Age)", Enabled: true},
+			}},
+			{Rule: "Age == 0", Enabled: true},
+		}
+		all, err := NewValidator().Validate(Sample{}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(all).To(HaveLen(3))
+
+		results, err := NewValidator(WithPartialEval(), WithCostLimit(0, all[0].Cost)).Validate(Sample{}, rules, md)
+		Expect(err).To(MatchError(ErrTotalCostLimit))
+		Expect(results).To(HaveLen(2))
+	})
+
+	It("counts message and suggestion expressions in the total limit", func() {
+		rules := []RuleEntry{
+			{Rule: "Age > 0", Enabled: true,
+				MessageExpression:    "[1, 2, 3].all(x, x > Age) ? 'positive' : 'zero'",
+				SuggestionExpression: "[4, 5, 6].all(x, x > Age)"},
+			{Rule: "Age == 0", Enabled: true},
+		}
+		results, err := NewValidator(WithPartialEval(), WithCostLimit(0, 10)).Validate(Sample{}, rules, md)
+		Expect(err).To(MatchError(ErrTotalCostLimit))
+		Expect(results).To(HaveLen(1))
+	})
+
+	It("shares the total limit across concurrent chains", func() {
+		rules := []RuleEntry{
+			{Rule: "[1, 2, 3].all(x, x > Age)", Enabled: true},
+			{Rule: "[4, 5, 6].all(x, x > Age)", Enabled: true},
+			{Rule: "[7, 8, 9].all(x, x > Age)", Enabled: true},
+		}
+		_, err := NewValidator(WithConcurrency(3), WithCostLimit(0, 10)).Validate(Sample{}, rules, md)
+		Expect(err).To(MatchError(ErrTotalCostLimit))
+	})
+
+	It("reads cost limits from the validator config", func() {
+		opts, err := ValidatorConfig{RuleCostLimit: 1000}.Options()
+		Expect(err).To(BeNil())
+		results, err := NewValidator(append(opts, withLists, WithPartialEval())...).Validate(Sample{}, []RuleEntry{{Rule: runawayRule, Enabled: true}}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Error).To(MatchError(ErrRuleCostLimit))
+	})
+})
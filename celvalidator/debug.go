@@ -0,0 +1,231 @@
+package celvalidator
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// debugRecentFailures is the size of the recent failures ring buffer
+const debugRecentFailures = 100
+
+// debugMaxEntries bounds the rule statuses and the distinct compile errors
+// kept, so dynamic rule sets do not grow them without limit
+const debugMaxEntries = 1000
+
+// WithDebugRecording records the compile status and failures of the
+// validator's rules for DebugHandler, PublishExpvar and CompileErrorStats.
+// Validations are otherwise not recorded, as recording takes a process-wide lock.
+func WithDebugRecording() ValidatorOption {
+	return func(v *Validator) {
+		v.debugRecording = true
+	}
+}
+
+// DebugFailure is a failed rule kept in the recent failures ring buffer
+type DebugFailure struct {
+	Time       time.Time `json:"time"`
+	StructName string    `json:"struct"`
+	Operation  string    `json:"operation"`
+	Rule       string    `json:"rule"`
+	Message    string    `json:"message,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// DebugRuleStatus is the last observed compile status of a rule
+type DebugRuleStatus struct {
+	StructName string `json:"struct"`
+	ID         string `json:"id,omitempty"`
+	Rule       string `json:"rule"`
+	Compiled   bool   `json:"compiled"`
+	Error      string `json:"error,omitempty"`
+}
+
+// DebugCacheStats reports the Check rule file cache usage
+type DebugCacheStats struct {
+	Entries int    `json:"entries"`
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+}
+
+// DebugSnapshot is the state served by DebugHandler
+type DebugSnapshot struct {
+	RuleSets       map[string]RuleSetMap `json:"rule_sets"`
+	RuleStatus     []DebugRuleStatus     `json:"rule_status"`
+	Cache          DebugCacheStats       `json:"cache"`
+	RecentFailures []DebugFailure        `json:"recent_failures"`
+	CompileErrors  []CompileErrorStat    `json:"compile_errors"`
+}
+
+type debugState struct {
+	mu       sync.Mutex
+	ruleSets map[string]func() RuleSetMap
+	status   map[string]DebugRuleStatus
+	failures []DebugFailure
+	next     int
+	hits     uint64
+	misses   uint64
+
+	compileErrors     map[string]*CompileErrorStat
+	compileErrorTotal uint64
+}
+
+var debug = &debugState{
+	ruleSets: map[string]func() RuleSetMap{},
+	status:   map[string]DebugRuleStatus{},
+
+	compileErrors: map[string]*CompileErrorStat{},
+}
+
+// RegisterDebugRuleSet exposes a rule set on the debug handler under name,
+// rules being called on every request so reloads are reflected
+func RegisterDebugRuleSet(name string, rules func() RuleSetMap) {
+	debug.mu.Lock()
+	defer debug.mu.Unlock()
+	debug.ruleSets[name] = rules
+}
+
+// recordValidation tracks compile status and failures of a validation
+func (d *debugState) recordValidation(metadata ValidationMetadata, results []ValidationResult) {
+	now := time.Now().UTC()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, r := range results {
+		status := DebugRuleStatus{
+			StructName: metadata.StructName,
+			ID:         r.ID,
+			Rule:       r.Rule,
+			Compiled:   true,
+		}
+		if isCompileError(r) {
+			status.Compiled = false
+			status.Error = errorString(r.Error)
+			d.recordCompileError(r, now)
+		}
+		key := metadata.StructName + "\x00" + ruleKey(r.ID, r.Rule)
+		if _, ok := d.status[key]; !ok {
+			evictOne(d.status, debugMaxEntries)
+		}
+		d.status[key] = status
+
+		if r.Passed {
+			continue
+		}
+		failure := DebugFailure{
+			Time:       now,
+			StructName: metadata.StructName,
+			Operation:  metadata.Operation,
+			Rule:       r.Rule,
+			Message:    r.Message,
+			Error:      errorString(r.Error),
+		}
+		if len(d.failures) < debugRecentFailures {
+			d.failures = append(d.failures, failure)
+		} else {
+			d.failures[d.next] = failure
+		}
+		d.next = (d.next + 1) % debugRecentFailures
+	}
+}
+
+// isCompileError reports whether a result failed before evaluation, because
+// its rule did not compile or could not be made a program
+func isCompileError(r ValidationResult) bool {
+	return r.Outcome == OutcomeError && errors.Is(r.Error, ErrRuleCompile)
+}
+
+// evictOne removes an arbitrary entry of m once it holds max entries, making
+// room for a new one
+func evictOne[V any](m map[string]V, max int) {
+	if len(m) < max {
+		return
+	}
+	for key := range m {
+		delete(m, key)
+		return
+	}
+}
+
+func (d *debugState) recordCacheLookup(hit bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if hit {
+		d.hits++
+	} else {
+		d.misses++
+	}
+}
+
+func (d *debugState) snapshot() DebugSnapshot {
+	snap := DebugSnapshot{RuleSets: map[string]RuleSetMap{}}
+
+	checkCacheMu.Lock()
+	for path, cached := range checkCache {
+		snap.RuleSets[path] = cached.file.Rules
+	}
+	snap.Cache.Entries = len(checkCache)
+	checkCacheMu.Unlock()
+
+	d.mu.Lock()
+	providers := make(map[string]func() RuleSetMap, len(d.ruleSets))
+	for name, rules := range d.ruleSets {
+		providers[name] = rules
+	}
+	for _, status := range d.status {
+		snap.RuleStatus = append(snap.RuleStatus, status)
+	}
+	// Oldest failure first, next is the oldest slot once the buffer is full
+	oldest := 0
+	if len(d.failures) == debugRecentFailures {
+		oldest = d.next
+	}
+	snap.RecentFailures = append(snap.RecentFailures, d.failures[oldest:]...)
+	snap.RecentFailures = append(snap.RecentFailures, d.failures[:oldest]...)
+	snap.Cache.Hits = d.hits
+	snap.Cache.Misses = d.misses
+	snap.CompileErrors = d.compileErrorStats()
+	d.mu.Unlock()
+
+	for name, rules := range providers {
+		snap.RuleSets[name] = rules()
+	}
+	sort.Slice(snap.RuleStatus, func(i, j int) bool {
+		if snap.RuleStatus[i].StructName != snap.RuleStatus[j].StructName {
+			return snap.RuleStatus[i].StructName < snap.RuleStatus[j].StructName
+		}
+		return snap.RuleStatus[i].Rule < snap.RuleStatus[j].Rule
+	})
+
+	return snap
+}
+
+// DebugHandler serves loaded rule sets, per-rule compile status, cache stats
+// and recent failures as JSON, meant to be mounted under /debug/celvalidator.
+// Only validators created WithDebugRecording report their rules.
+func DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(debug.snapshot()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+var publishExpvarOnce sync.Once
+
+// PublishExpvar publishes the debug snapshot as the "celvalidator" expvar
+func PublishExpvar() {
+	publishExpvarOnce.Do(func() {
+		expvar.Publish("celvalidator", expvar.Func(func() any {
+			return debug.snapshot()
+		}))
+	})
+}
@@ -0,0 +1,110 @@
+package celvalidator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DebugHandler", func() {
+	It("serves rule sets, compile status and recent failures", func() {
+		ruleMap := RuleSetMap{
+			"Sample": {"Debug": {
+				{Rule: "Age < 0", Enabled: true, FailureMessage: "debug failure"},
+				{Rule: "DebugUnknown == 1", Enabled: true},
+			}},
+		}
+		RegisterDebugRuleSet("debug-test", func() RuleSetMap { return ruleMap })
+
+		obj := Sample{Age: 21}
+		_, err := NewValidator(WithPartialEval(), WithDebugRecording()).Validate(obj, GetRulesFor(obj, "Debug", ruleMap), NewValidationMetadata(obj, "Debug", ruleMap))
+		Expect(err).To(BeNil())
+
+		rec := httptest.NewRecorder()
+		DebugHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/celvalidator", nil))
+		Expect(rec.Code).To(Equal(200))
+
+		var snap DebugSnapshot
+		Expect(json.Unmarshal(rec.Body.Bytes(), &snap)).To(Succeed())
+		Expect(snap.RuleSets).To(HaveKey("debug-test"))
+		Expect(snap.RuleStatus).To(ContainElement(And(HaveField("Rule", "DebugUnknown == 1"), HaveField("Compiled", false))))
+		Expect(snap.RuleStatus).To(ContainElement(And(HaveField("Rule", "Age < 0"), HaveField("Compiled", true))))
+		Expect(snap.RecentFailures).To(ContainElement(HaveField("Message", "debug failure")))
+	})
+
+	It("records only validators created WithDebugRecording", func() {
+		rules := []RuleEntry{{Rule: "UnrecordedUnknown == 1", Enabled: true}}
+		results, err := NewValidator(WithPartialEval()).Validate(Sample{}, rules, ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+		Expect(isCompileError(results[0])).To(BeTrue())
+		Expect(debug.snapshot().RuleStatus).NotTo(ContainElement(HaveField("Rule", "UnrecordedUnknown == 1")))
+	})
+
+	It("bounds the rule statuses kept", func() {
+		v := NewValidator(WithDebugRecording())
+		for i := 0; i <= debugMaxEntries; i++ {
+			rules := []RuleEntry{{ID: fmt.Sprintf("bounded-%d", i), Rule: "Age >= 0", Enabled: true}}
+			_, err := v.Validate(Sample{}, rules, ValidationMetadata{StructName: "Sample"})
+			Expect(err).To(BeNil())
+		}
+		Expect(len(debug.snapshot().RuleStatus)).To(BeNumerically("<=", debugMaxEntries))
+	})
+})
+
+var _ = Describe("Compile error aggregation", func() {
+	It("counts distinct compile errors and logs a summary", func() {
+		obj := Sample{Age: 21}
+		rules := []RuleEntry{{Rule: "AggregatedUnknown == 1", Enabled: true}}
+		for i := 0; i < 3; i++ {
+			_, err := NewValidator(WithPartialEval(), WithDebugRecording()).Validate(obj, rules, ValidationMetadata{StructName: "Sample"})
+			Expect(err).To(BeNil())
+		}
+
+		var stat CompileErrorStat
+		for _, s := range CompileErrorStats() {
+			if s.Rule == "AggregatedUnknown == 1" {
+				stat = s
+			}
+		}
+		Expect(stat.Count).To(Equal(uint64(3)))
+		Expect(stat.RuleHash).To(Equal(ruleHash("AggregatedUnknown == 1")))
+		Expect(stat.Error).To(ContainSubstring("AggregatedUnknown"))
+
+		var buf safeBuffer
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			LogCompileErrors(ctx, 10*time.Millisecond, slog.New(slog.NewTextHandler(&buf, nil)))
+		}()
+		Eventually(buf.String).Should(ContainSubstring("celvalidator compile errors"))
+		cancel()
+		<-done
+	})
+})
+
+// safeBuffer is a bytes.Buffer safe for a concurrent writer and reader
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,222 @@
+package celvalidator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/interpreter"
+)
+
+// ErrArithmeticOverflow is reported on results whose evaluation overflowed
+// with WithCheckedArithmetic enabled
+var ErrArithmeticOverflow = errors.New("arithmetic overflow")
+
+const decimalTypeName = "decimal"
+
+var (
+	decimalType = cel.OpaqueType(decimalTypeName).WithTraits(
+		traits.AdderType | traits.SubtractorType | traits.MultiplierType | traits.ComparerType)
+	ratType = reflect.TypeOf(big.Rat{})
+)
+
+// Decimal is an arbitrary precision decimal CEL value, big.Rat fields are
+// exposed as decimals
+type Decimal struct {
+	rat *big.Rat
+}
+
+func newDecimal(r *big.Rat) Decimal {
+	return Decimal{rat: new(big.Rat).Set(r)}
+}
+
+// ConvertToNative supports *big.Rat, big.Rat and string
+func (d Decimal) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	switch typeDesc {
+	case reflect.TypeOf(&big.Rat{}):
+		return new(big.Rat).Set(d.rat), nil
+	case ratType:
+		return *new(big.Rat).Set(d.rat), nil
+	case reflect.TypeOf(""):
+		return d.String(), nil
+	}
+	return nil, fmt.Errorf("unsupported conversion from decimal to %v", typeDesc)
+}
+
+// ConvertToType supports conversion to string and double
+func (d Decimal) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case decimalType:
+		return d
+	case types.StringType:
+		return types.String(d.String())
+	case types.DoubleType:
+		f, _ := d.rat.Float64()
+		return types.Double(f)
+	case types.TypeType:
+		return decimalType
+	}
+	return types.NewErr("type conversion error from decimal to '%v'", typeVal)
+}
+
+// Equal compares decimals by value
+func (d Decimal) Equal(other ref.Val) ref.Val {
+	o, ok := other.(Decimal)
+	if !ok {
+		return types.False
+	}
+	return types.Bool(d.rat.Cmp(o.rat) == 0)
+}
+
+func (d Decimal) Type() ref.Type {
+	return decimalType
+}
+
+func (d Decimal) Value() any {
+	return d.rat
+}
+
+// String formats the decimal without trailing zeros
+func (d Decimal) String() string {
+	if d.rat.IsInt() {
+		return d.rat.Num().String()
+	}
+	s := d.rat.FloatString(32)
+	for s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// WithDecimal registers the decimal type: decimal("12.34") and decimal(int)
+// constructors, arithmetic (+, -, *), comparisons and string(decimal)
+func WithDecimal() ValidatorOption {
+	return func(v *Validator) {
+		v.decimal = true
+	}
+}
+
+// WithCheckedArithmetic reports a rule as failed with ErrArithmeticOverflow when
+// any double computed during its evaluation overflows to infinity. CEL already
+// errors on int and uint overflow.
+func WithCheckedArithmetic() ValidatorOption {
+	return func(v *Validator) {
+		v.checkedArith = true
+	}
+}
+
+// Add implements traits.Adder
+func (d Decimal) Add(other ref.Val) ref.Val {
+	o, ok := other.(Decimal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	return Decimal{rat: new(big.Rat).Add(d.rat, o.rat)}
+}
+
+// Subtract implements traits.Subtractor
+func (d Decimal) Subtract(other ref.Val) ref.Val {
+	o, ok := other.(Decimal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	return Decimal{rat: new(big.Rat).Sub(d.rat, o.rat)}
+}
+
+// Multiply implements traits.Multiplier
+func (d Decimal) Multiply(other ref.Val) ref.Val {
+	o, ok := other.(Decimal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	return Decimal{rat: new(big.Rat).Mul(d.rat, o.rat)}
+}
+
+// Compare implements traits.Comparer
+func (d Decimal) Compare(other ref.Val) ref.Val {
+	o, ok := other.(Decimal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	return types.Int(d.rat.Cmp(o.rat))
+}
+
+func decimalLibrary() cel.EnvOption {
+	pair := []*cel.Type{decimalType, decimalType}
+
+	return cel.Lib(envLib{opts: []cel.EnvOption{
+		cel.Function(decimalTypeName,
+			cel.Overload("decimal_string", []*cel.Type{cel.StringType}, decimalType,
+				cel.UnaryBinding(func(val ref.Val) ref.Val {
+					r, ok := new(big.Rat).SetString(string(val.(types.String)))
+					if !ok {
+						return types.NewErr("invalid decimal %q", val)
+					}
+					return Decimal{rat: r}
+				})),
+			cel.Overload("decimal_int", []*cel.Type{cel.IntType}, decimalType,
+				cel.UnaryBinding(func(val ref.Val) ref.Val {
+					return Decimal{rat: new(big.Rat).SetInt64(int64(val.(types.Int)))}
+				})),
+		),
+		cel.Function("string",
+			cel.Overload("string_decimal", []*cel.Type{decimalType}, cel.StringType,
+				cel.UnaryBinding(func(val ref.Val) ref.Val {
+					return types.String(val.(Decimal).String())
+				})),
+		),
+		// Operators dispatch to the Decimal trait implementations
+		cel.Function(operators.Add, cel.Overload("add_decimal", pair, decimalType)),
+		cel.Function(operators.Subtract, cel.Overload("subtract_decimal", pair, decimalType)),
+		cel.Function(operators.Multiply, cel.Overload("multiply_decimal", pair, decimalType)),
+		cel.Function(operators.Less, cel.Overload("less_decimal", pair, cel.BoolType)),
+		cel.Function(operators.LessEquals, cel.Overload("less_equals_decimal", pair, cel.BoolType)),
+		cel.Function(operators.Greater, cel.Overload("greater_decimal", pair, cel.BoolType)),
+		cel.Function(operators.GreaterEquals, cel.Overload("greater_equals_decimal", pair, cel.BoolType)),
+	}})
+}
+
+// checkOverflow evaluates a rule again tracking its state, as cel-go does not
+// track costs along with state, and returns ErrArithmeticOverflow when an
+// intermediate double of the evaluation is infinite
+func (e *evaluation) checkOverflow(c *compiledRule) error {
+	prg := c.tracked
+	if prg == nil {
+		var err error
+		if prg, err = e.env.Program(c.ast, trackedProgramOptions(e.prgOpts)...); err != nil {
+			return err
+		}
+	}
+	_, details, _ := prg.ContextEval(e.ctx, e.vars)
+	if details == nil || details.State() == nil {
+		return nil
+	}
+	return overflowError(details.State())
+}
+
+// trackedProgramOptions returns opts tracking the evaluation state
+func trackedProgramOptions(opts []cel.ProgramOption) []cel.ProgramOption {
+	return append(slices.Clip(opts), cel.EvalOptions(cel.OptTrackState))
+}
+
+// overflowError returns ErrArithmeticOverflow when a double of state is infinite
+func overflowError(state interpreter.EvalState) error {
+	for _, id := range state.IDs() {
+		val, ok := state.Value(id)
+		if !ok {
+			continue
+		}
+		if d, ok := val.(types.Double); ok && math.IsInf(float64(d), 0) {
+			return fmt.Errorf("%w: double result is %v", ErrArithmeticOverflow, float64(d))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package celvalidator
+
+import (
+	"math/big"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Numeric handling", func() {
+	type Invoice struct {
+		Total    *big.Rat
+		Discount big.Rat
+		Rate     float64
+	}
+	invoice := Invoice{Total: big.NewRat(1999, 100), Discount: *big.NewRat(1, 10), Rate: 1e308}
+
+	It("compares and computes with decimals", func() {
+		v := NewValidator(WithDecimal())
+		rules := []RuleEntry{
+			{Rule: "Total == decimal('19.99')", Enabled: true},
+			{Rule: "Total - Discount >= decimal('19.89')", Enabled: true},
+			{Rule: "decimal('0.1') + decimal('0.2') == decimal('0.3')", Enabled: true},
+			{Rule: "string(Total * decimal(2)) == '39.98'", Enabled: true},
+		}
+		results, err := v.Validate(invoice, rules, ValidationMetadata{StructName: "Invoice"})
+		Expect(err).To(BeNil())
+		for _, r := range results {
+			Expect(r.Passed).To(BeTrue(), r.Rule)
+		}
+	})
+
+	It("reports double overflow with checked arithmetic", func() {
+		rules := []RuleEntry{{Rule: "Rate * 10.0 > 0.0", Enabled: true}}
+
+		results, err := NewValidator().Validate(invoice, rules, ValidationMetadata{StructName: "Invoice"})
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeTrue())
+
+		results, err = NewValidator(WithCheckedArithmetic()).Validate(invoice, rules, ValidationMetadata{StructName: "Invoice"})
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeFalse())
+		Expect(results[0].Error).To(MatchError(ErrArithmeticOverflow))
+	})
+})
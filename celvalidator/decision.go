@@ -0,0 +1,160 @@
+package celvalidator
+
+// Decision is the overall outcome of a report under a DecisionPolicy
+type Decision struct {
+	Valid     bool
+	Policy    string
+	Passed    int
+	Failed    int
+	Score     float64
+	Threshold float64
+}
+
+// DecisionPolicy combines rule results into an overall Decision
+type DecisionPolicy interface {
+	Decide(report ValidationReport) Decision
+}
+
+// Decide combines the report results with policy
+func (r ValidationReport) Decide(policy DecisionPolicy) Decision {
+	return policy.Decide(r)
+}
+
+// countOutcomes counts passed and failed results, errors counting as failures
+// and sampled out rules not counting
+func countOutcomes(report ValidationReport) (passed, failed int) {
+	for _, res := range report {
+		if res.Outcome == OutcomeSampledOut {
+			continue
+		}
+		if res.Passed && res.Error == nil {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	return passed, failed
+}
+
+type allMustPass struct{}
+
+// AllMustPass is valid only when every rule passed
+func AllMustPass() DecisionPolicy {
+	return allMustPass{}
+}
+
+func (allMustPass) Decide(report ValidationReport) Decision {
+	passed, failed := countOutcomes(report)
+	return Decision{Valid: failed == 0, Policy: "AllMustPass", Passed: passed, Failed: failed, Score: ratio(passed, passed+failed), Threshold: 1}
+}
+
+type majorityPass struct{}
+
+// MajorityPass is valid when more than half of the rules passed
+func MajorityPass() DecisionPolicy {
+	return majorityPass{}
+}
+
+func (majorityPass) Decide(report ValidationReport) Decision {
+	passed, failed := countOutcomes(report)
+	return Decision{Valid: passed > failed, Policy: "MajorityPass", Passed: passed, Failed: failed, Score: ratio(passed, passed+failed), Threshold: 0.5}
+}
+
+type weightedScore struct {
+	threshold float64
+}
+
+// WeightedScore is valid when the weight of passed rules over the total weight
+// reaches threshold (0 to 1). Rules without a weight count as 1.
+func WeightedScore(threshold float64) DecisionPolicy {
+	return weightedScore{threshold: threshold}
+}
+
+func (p weightedScore) Decide(report ValidationReport) Decision {
+	passed, failed := countOutcomes(report)
+
+	var passedWeight, totalWeight float64
+	for _, res := range report {
+		if res.Outcome == OutcomeSampledOut {
+			continue
+		}
+		w := resultWeight(res)
+		totalWeight += w
+		if res.Passed && res.Error == nil {
+			passedWeight += w
+		}
+	}
+
+	score := 1.0
+	if totalWeight > 0 {
+		score = passedWeight / totalWeight
+	}
+	return Decision{Valid: score >= p.threshold, Policy: "WeightedScore", Passed: passed, Failed: failed, Score: score, Threshold: p.threshold}
+}
+
+// resultWeight returns the result weight, results built without one counting as 1
+func resultWeight(res ValidationResult) float64 {
+	if res.Weight == 0 {
+		return 1
+	}
+	return res.Weight
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 1
+	}
+	return float64(n) / float64(total)
+}
+
+// RuleContribution is how much a single rule added to a RiskScore
+type RuleContribution struct {
+	Rule         string
+	ParentRule   string
+	Weight       float64
+	Passed       bool
+	Contribution float64
+}
+
+// RiskScore is the weighted risk of a report: every failed (or errored) rule
+// contributes its weight, so higher scores mean riskier input
+type RiskScore struct {
+	Score         float64
+	MaxScore      float64
+	Contributions []RuleContribution
+}
+
+// Normalized returns the score relative to the maximum possible score (0 to 1)
+func (s RiskScore) Normalized() float64 {
+	if s.MaxScore == 0 {
+		return 0
+	}
+	return s.Score / s.MaxScore
+}
+
+// RiskScore computes the weighted risk score with a per-rule breakdown, in
+// report order. Rules without a weight count as 1, sampled out rules not
+// counting.
+func (r ValidationReport) RiskScore() RiskScore {
+	score := RiskScore{Contributions: make([]RuleContribution, 0, len(r))}
+	for _, res := range r {
+		if res.Outcome == OutcomeSampledOut {
+			continue
+		}
+		w := resultWeight(res)
+		c := RuleContribution{
+			Rule:       res.Rule,
+			ParentRule: res.Metadata.ParentRule,
+			Weight:     w,
+			Passed:     res.Passed && res.Error == nil,
+		}
+		if !c.Passed {
+			c.Contribution = w
+		}
+
+		score.Score += c.Contribution
+		score.MaxScore += w
+		score.Contributions = append(score.Contributions, c)
+	}
+	return score
+}
@@ -0,0 +1,208 @@
+package celvalidator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DegradationMode selects the rules used while rule sources or resolvers are unavailable
+type DegradationMode string
+
+const (
+	// DegradeLastKnownGood keeps validating with the last rule set loaded
+	// successfully, failing closed until a rule set was loaded
+	DegradeLastKnownGood DegradationMode = "last_known_good"
+	// DegradeBaseline validates with a minimal baseline rule set, e.g. embedded
+	// in the binary, which should not depend on resolvers
+	DegradeBaseline DegradationMode = "baseline"
+	// DegradeFailClosed rejects every validation
+	DegradeFailClosed DegradationMode = "fail_closed"
+	// DegradeNone is the active mode while every dependency is available
+	DegradeNone DegradationMode = "none"
+)
+
+// DegradationAnnotation is the metadata annotation reporting the active degradation mode
+const DegradationAnnotation = "celvalidator/degradation"
+
+// ErrRulesUnavailable is returned by fail-closed validations while degraded
+var ErrRulesUnavailable = errors.New("rules unavailable")
+
+// ParseDegradationMode parses a degradation mode name, defaulting to last_known_good
+func ParseDegradationMode(s string) (DegradationMode, error) {
+	switch mode := DegradationMode(s); mode {
+	case "":
+		return DegradeLastKnownGood, nil
+	case DegradeLastKnownGood, DegradeBaseline, DegradeFailClosed:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown degradation mode %q", s)
+}
+
+// DegradationHealth is the degradation state served by HealthHandler
+type DegradationHealth struct {
+	Degraded bool            `json:"degraded"`
+	Mode     DegradationMode `json:"mode"`
+	// Active is the mode currently applied, DegradeNone unless degraded
+	Active DegradationMode `json:"active"`
+	Since  time.Time       `json:"since"`
+	// Unavailable lists the dependencies reported unavailable, with their error
+	Unavailable map[string]string `json:"unavailable,omitempty"`
+}
+
+// DegradingProvider serves the rules of a RuleProvider, falling back according
+// to its mode while a dependency is unavailable. A failed refresh marks the
+// rule source unavailable, callers mark resolvers with MarkUnavailable.
+type DegradingProvider struct {
+	provider *RuleProvider
+	mode     DegradationMode
+	baseline *RuleSetSnapshot
+
+	mu          sync.RWMutex
+	unavailable map[string]error
+	since       time.Time
+	// loaded reports whether the provider holds rules loaded successfully
+	loaded bool
+}
+
+// rulesDependency names the rule source in the unavailable dependencies
+const rulesDependency = "rules"
+
+// NewDegradingProvider wraps provider, serving baseline rules in DegradeBaseline
+// mode. Rules already held by provider count as the last known good ones.
+func NewDegradingProvider(provider *RuleProvider, mode DegradationMode, baseline *RuleFile) *DegradingProvider {
+	p := &DegradingProvider{
+		provider:    provider,
+		mode:        mode,
+		unavailable: map[string]error{},
+		loaded:      len(provider.Snapshot().rules) > 0,
+	}
+	if baseline != nil {
+		p.baseline = NewRuleProvider(nil).Swap(baseline)
+	}
+	return p
+}
+
+// Refresh loads the rule file with load and swaps it in, marking the rule
+// source unavailable on error and available again on success
+func (p *DegradingProvider) Refresh(load func() (*RuleFile, error)) error {
+	file, err := load()
+	if err != nil {
+		p.MarkUnavailable(rulesDependency, err)
+		return err
+	}
+	p.provider.Swap(file)
+	p.mu.Lock()
+	p.loaded = true
+	p.mu.Unlock()
+	p.MarkAvailable(rulesDependency)
+	return nil
+}
+
+// Reload refreshes from a rule file on disk
+func (p *DegradingProvider) Reload(path string, opts ...LoadOption) error {
+	return p.Refresh(func() (*RuleFile, error) {
+		return LoadRuleFileFromYAML(path, opts...)
+	})
+}
+
+// MarkUnavailable reports a dependency, e.g. a resolver backend, as unavailable
+func (p *DegradingProvider) MarkUnavailable(dependency string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.unavailable) == 0 {
+		p.since = time.Now().UTC()
+	}
+	p.unavailable[dependency] = err
+}
+
+// MarkAvailable reports a dependency as available again
+func (p *DegradingProvider) MarkAvailable(dependency string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.unavailable, dependency)
+	if len(p.unavailable) == 0 {
+		p.since = time.Time{}
+	}
+}
+
+// Active returns the degradation mode currently applied
+func (p *DegradingProvider) Active() DegradationMode {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.active()
+}
+
+// active returns the applied mode, p.mu must be held
+func (p *DegradingProvider) active() DegradationMode {
+	if len(p.unavailable) == 0 {
+		return DegradeNone
+	}
+	if p.mode == DegradeBaseline && p.baseline == nil {
+		return DegradeFailClosed
+	}
+	if p.mode == DegradeLastKnownGood && !p.loaded {
+		return DegradeFailClosed
+	}
+	return p.mode
+}
+
+// Health returns the degradation state
+func (p *DegradingProvider) Health() DegradationHealth {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	h := DegradationHealth{
+		Degraded: len(p.unavailable) > 0,
+		Mode:     p.mode,
+		Active:   p.active(),
+		Since:    p.since,
+	}
+	if h.Degraded {
+		h.Unavailable = make(map[string]string, len(p.unavailable))
+		for name, err := range p.unavailable {
+			h.Unavailable[name] = errorString(err)
+		}
+	}
+	return h
+}
+
+// HealthHandler serves the degradation state as JSON, with status 503 while
+// validations fail closed
+func (p *DegradingProvider) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := p.Health()
+		w.Header().Set("Content-Type", "application/json")
+		if h.Active == DegradeFailClosed {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(h)
+	})
+}
+
+// Validate validates obj for an operation with the rules of the active mode,
+// reporting the mode under DegradationAnnotation in the results metadata.
+// Failing closed returns a single error result along with ErrRulesUnavailable.
+func (p *DegradingProvider) Validate(v *Validator, obj any, operation string) ([]ValidationResult, error) {
+	active := p.Active()
+	snapshot := p.provider.Snapshot()
+	if active == DegradeBaseline {
+		snapshot = p.baseline
+	}
+
+	metadata := MetadataBuilderFrom(snapshot.Metadata(obj, operation)).
+		WithAnnotation(DegradationAnnotation, string(active)).
+		Build()
+	if active == DegradeFailClosed {
+		return []ValidationResult{{
+			Passed:   false,
+			Error:    ErrRulesUnavailable,
+			Severity: SeverityError,
+			Outcome:  OutcomeError,
+			Metadata: metadata,
+		}}, ErrRulesUnavailable
+	}
+	return v.Validate(obj, snapshot.RulesFor(obj, metadata.Operation), metadata)
+}
@@ -0,0 +1,102 @@
+package celvalidator
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Degradation", func() {
+	current := &RuleFile{Rules: RuleSetMap{"Sample": {"Default": {
+		{Rule: "Age >= 18", Enabled: true},
+		{Rule: "Email != ''", Enabled: true},
+	}}}}
+	baseline := &RuleFile{Rules: RuleSetMap{"Sample": {"Default": {
+		{Rule: "Age >= 0", Enabled: true},
+	}}}}
+	outage := errors.New("connection refused")
+	obj := Sample{Age: 20}
+
+	newProvider := func(mode DegradationMode) *DegradingProvider {
+		p := NewDegradingProvider(NewRuleProvider(nil), mode, baseline)
+		Expect(p.Refresh(func() (*RuleFile, error) { return current, nil })).To(Succeed())
+		return p
+	}
+
+	It("reports no degradation while dependencies are available", func() {
+		p := newProvider(DegradeFailClosed)
+		results, err := p.Validate(NewValidator(), obj, "Create")
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Metadata.Annotations).To(HaveKeyWithValue(DegradationAnnotation, "none"))
+	})
+
+	It("keeps the last known good rules when a refresh fails", func() {
+		p := newProvider(DegradeLastKnownGood)
+		Expect(p.Refresh(func() (*RuleFile, error) { return nil, outage })).To(MatchError(outage))
+
+		results, err := p.Validate(NewValidator(), obj, "Create")
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Metadata.Annotations).To(HaveKeyWithValue(DegradationAnnotation, "last_known_good"))
+		Expect(p.Health().Unavailable).To(HaveKeyWithValue("rules", "connection refused"))
+
+		Expect(p.Refresh(func() (*RuleFile, error) { return current, nil })).To(Succeed())
+		Expect(p.Active()).To(Equal(DegradeNone))
+	})
+
+	It("fails closed until the last known good rules were loaded", func() {
+		p := NewDegradingProvider(NewRuleProvider(nil), DegradeLastKnownGood, nil)
+		Expect(p.Refresh(func() (*RuleFile, error) { return nil, outage })).To(MatchError(outage))
+
+		results, err := p.Validate(NewValidator(), obj, "Create")
+		Expect(err).To(MatchError(ErrRulesUnavailable))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Passed).To(BeFalse())
+		Expect(p.Active()).To(Equal(DegradeFailClosed))
+
+		Expect(p.Refresh(func() (*RuleFile, error) { return current, nil })).To(Succeed())
+		Expect(p.Refresh(func() (*RuleFile, error) { return nil, outage })).To(MatchError(outage))
+		Expect(p.Active()).To(Equal(DegradeLastKnownGood))
+	})
+
+	It("switches to the baseline while a resolver is unavailable", func() {
+		p := newProvider(DegradeBaseline)
+		p.MarkUnavailable("accounts", outage)
+
+		results, err := p.Validate(NewValidator(), obj, "Create")
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Rule).To(Equal("Age >= 0"))
+		Expect(results[0].Metadata.Annotations).To(HaveKeyWithValue(DegradationAnnotation, "baseline"))
+
+		p.MarkAvailable("accounts")
+		Expect(p.Health().Degraded).To(BeFalse())
+	})
+
+	It("fails closed and reports it on the health endpoint", func() {
+		p := newProvider(DegradeFailClosed)
+		p.MarkUnavailable("accounts", outage)
+
+		results, err := p.Validate(NewValidator(), obj, "Create")
+		Expect(err).To(MatchError(ErrRulesUnavailable))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Outcome).To(Equal(OutcomeError))
+
+		rec := httptest.NewRecorder()
+		p.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(rec.Body.String()).To(ContainSubstring(`"active":"fail_closed"`))
+	})
+
+	It("parses configured modes", func() {
+		mode, err := ParseDegradationMode("")
+		Expect(err).To(BeNil())
+		Expect(mode).To(Equal(DegradeLastKnownGood))
+		_, err = ParseDegradationMode("open")
+		Expect(err).NotTo(BeNil())
+	})
+})
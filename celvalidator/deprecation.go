@@ -0,0 +1,91 @@
+package celvalidator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrRuleSunset is returned when loading a rule file holding a deprecated rule
+// past its sunset date
+var ErrRuleSunset = errors.New("rule past sunset")
+
+// sunsetLayout formats sunset dates as written in rule files
+const sunsetLayout = "2006-01-02"
+
+// RuleDeprecation reports a deprecated rule found while loading a rule file
+type RuleDeprecation struct {
+	StructName string
+	Operation  string
+	ID         string
+	Rule       string
+	// Sunset is when the rule stops loading, zero when not scheduled
+	Sunset time.Time
+	Source RuleSource
+}
+
+func (d RuleDeprecation) String() string {
+	name := d.ID
+	if name == "" {
+		name = d.Rule
+	}
+	msg := fmt.Sprintf("%s.%s: rule %q is deprecated", d.StructName, d.Operation, name)
+	if !d.Sunset.IsZero() {
+		msg += ", sunset " + d.Sunset.Format(sunsetLayout)
+	}
+	return msg
+}
+
+// WithLoadTime sets the time sunset dates are checked against, instead of now
+func WithLoadTime(now time.Time) LoadOption {
+	return func(c *loadConfig) {
+		c.now = now
+	}
+}
+
+// deprecated reports whether the rule is deprecated, a sunset date implying it
+func (r RuleEntry) deprecated() bool {
+	return r.Deprecated || !r.Sunset.IsZero()
+}
+
+// ruleDeprecations lists the deprecated rules of rules, Then children
+// included, failing with ErrRuleSunset for those past their sunset at now
+func ruleDeprecations(rules RuleSetMap, now time.Time) ([]RuleDeprecation, error) {
+	var found []RuleDeprecation
+	var walk func(structName, op string, entries []RuleEntry)
+	walk = func(structName, op string, entries []RuleEntry) {
+		for _, entry := range entries {
+			if entry.deprecated() {
+				found = append(found, RuleDeprecation{
+					StructName: structName,
+					Operation:  op,
+					ID:         entry.ID,
+					Rule:       entry.Rule,
+					Sunset:     entry.Sunset,
+					Source:     entry.Source,
+				})
+			}
+			walk(structName, op, entry.Then)
+		}
+	}
+	for structName, ops := range rules {
+		for op, entries := range ops {
+			walk(structName, op, entries)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].StructName != found[j].StructName {
+			return found[i].StructName < found[j].StructName
+		}
+		return found[i].Operation < found[j].Operation
+	})
+
+	var errs []error
+	for _, d := range found {
+		if !d.Sunset.IsZero() && !now.Before(d.Sunset) {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrRuleSunset, d))
+		}
+	}
+	return found, errors.Join(errs...)
+}
@@ -0,0 +1,37 @@
+package celvalidator
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deprecation", func() {
+	data := []byte("Sample:\n  Create:\n    - rule: \"Age > 0\"\n      enabled: true\n      deprecated: true\n      then:\n        - rule: \"Age < 100\"\n          enabled: true\n          id: age-max\n          sunset: 2027-01-01\n    - rule: \"Active\"\n      enabled: true\n")
+
+	It("lists deprecated rules while loading", func() {
+		file, err := ParseRuleFileYAML(data, WithLoadTime(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(err).To(BeNil())
+		Expect(file.Deprecations).To(HaveLen(2))
+		Expect(file.Deprecations[0].Rule).To(Equal("Age > 0"))
+		Expect(file.Deprecations[1].ID).To(Equal("age-max"))
+		Expect(file.Deprecations[1].String()).To(Equal(`Sample.Create: rule "age-max" is deprecated, sunset 2027-01-01`))
+	})
+
+	It("fails to load rules past their sunset", func() {
+		_, err := ParseRuleFileYAML(data, WithLoadTime(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(err).To(MatchError(ErrRuleSunset))
+	})
+
+	It("flags the results of deprecated rules", func() {
+		file, err := ParseRuleFileYAML(data, WithLoadTime(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(err).To(BeNil())
+		results, err := NewValidator().Validate(Sample{Age: 30}, file.Rules["Sample"]["Create"], ValidationMetadata{StructName: "Sample", Operation: "Create"})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].Deprecated).To(BeTrue())
+		Expect(results[1].Deprecated).To(BeTrue())
+		Expect(results[2].Deprecated).To(BeFalse())
+	})
+})
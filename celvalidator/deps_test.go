@@ -0,0 +1,61 @@
+package celvalidator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// coreDependencies are the only direct dependencies the core module may have.
+// Adapters pulling heavier clients (gRPC, client-go, Kafka...) belong in their
+// own module under adapters/ so embedding the core stays dependency-light.
+var coreDependencies = []string{
+	"github.com/google/cel-go",
+	"github.com/onsi/ginkgo/v2",
+	"github.com/onsi/gomega",
+	"google.golang.org/genproto/googleapis/api",
+	"gopkg.in/yaml.v3",
+}
+
+var _ = Describe("Core module", func() {
+	It("only depends on the core dependencies", func() {
+		data, err := os.ReadFile("go.mod")
+		Expect(err).To(BeNil())
+
+		var direct []string
+		inRequire := false
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "require ("):
+				inRequire = true
+			case inRequire && line == ")":
+				inRequire = false
+			case strings.HasPrefix(line, "require "):
+				line = strings.TrimPrefix(line, "require ")
+				fallthrough
+			case inRequire && line != "":
+				if !strings.HasSuffix(line, "// indirect") {
+					direct = append(direct, strings.Fields(line)[0])
+				}
+			}
+		}
+
+		Expect(direct).To(ConsistOf(coreDependencies))
+	})
+
+	It("keeps every adapter in its own module", func() {
+		adapters, err := os.ReadDir("adapters")
+		Expect(err).To(BeNil())
+		Expect(adapters).NotTo(BeEmpty())
+		for _, adapter := range adapters {
+			Expect(adapter.IsDir()).To(BeTrue(), adapter.Name())
+			data, err := os.ReadFile(filepath.Join("adapters", adapter.Name(), "go.mod"))
+			Expect(err).To(BeNil(), adapter.Name())
+			Expect(string(data)).To(HavePrefix("module github.com/gdbranco/celvalidator/adapters/" + adapter.Name() + "\n"))
+		}
+	})
+})
@@ -0,0 +1,125 @@
+package celvalidator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	expr "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"gopkg.in/yaml.v3"
+)
+
+// DocumentSchema describes a document without a Go type as flattened field
+// names ("Server.Port") to CEL type names: string, int, uint, double, bool,
+// bytes, list, map or dyn
+type DocumentSchema map[string]string
+
+var schemaTypes = map[string]*expr.Type{
+	"string": decls.String,
+	"int":    decls.Int,
+	"uint":   decls.Uint,
+	"double": decls.Double,
+	"bool":   decls.Bool,
+	"bytes":  decls.Bytes,
+	"list":   decls.NewListType(decls.Dyn),
+	"map":    decls.NewMapType(decls.String, decls.Dyn),
+	"dyn":    decls.Dyn,
+}
+
+// LoadDocumentSchema loads a YAML document schema
+func LoadDocumentSchema(path string) (DocumentSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading schema file: %w", err)
+	}
+
+	var schema DocumentSchema
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("unmarshalling schema: %w", err)
+	}
+	for field, typ := range schema {
+		if _, ok := schemaTypes[typ]; !ok {
+			return nil, fmt.Errorf("field %s: unknown type %q", field, typ)
+		}
+	}
+	return schema, nil
+}
+
+// SchemaFromSample infers a document schema from a representative YAML or JSON document
+func SchemaFromSample(sample []byte) (DocumentSchema, error) {
+	fields, err := flattenDocument(sample)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := make(DocumentSchema, len(fields))
+	for name, val := range fields {
+		schema[name] = schemaTypeName(inferType(val))
+	}
+	return schema, nil
+}
+
+func schemaTypeName(t *expr.Type) string {
+	names := make([]string, 0, len(schemaTypes))
+	for name := range schemaTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if schemaTypes[name].String() == t.String() {
+			return name
+		}
+	}
+	return "dyn"
+}
+
+// flattenDocument decodes a YAML or JSON object into flattened fields
+func flattenDocument(data []byte) (map[string]any, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+
+	result := make(map[string]any)
+	flattenJSONObject("", doc, result)
+	return result, nil
+}
+
+// newEnvFromSchema declares a CEL variable per schema field
+func newEnvFromSchema(schema DocumentSchema, opts ...cel.EnvOption) (*cel.Env, error) {
+	declarations := make([]*expr.Decl, 0, len(schema))
+	for name, typ := range schema {
+		t, ok := schemaTypes[typ]
+		if !ok {
+			return nil, fmt.Errorf("field %s: unknown type %q", name, typ)
+		}
+		declarations = append(declarations, decls.NewVar(name, t))
+	}
+	return cel.NewEnv(append([]cel.EnvOption{cel.Declarations(declarations...)}, opts...)...)
+}
+
+// ValidateDocument evaluates rules against a YAML or JSON document. Fields are
+// declared from schema, or inferred from the document itself when schema is nil.
+func (v *Validator) ValidateDocument(data []byte, schema DocumentSchema, rules []RuleEntry, metadata ValidationMetadata) ([]ValidationResult, error) {
+	fields, err := flattenDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var env *cel.Env
+	if schema != nil {
+		env, err = newEnvFromSchema(schema, append(v.envOptions(), v.globalDeclarations(func(name string) bool {
+			_, ok := schema[name]
+			return ok
+		})...)...)
+	} else {
+		env, err = v.documentEnv(fields)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.validate(context.Background(), env, fields, rules, metadata)
+}
@@ -0,0 +1,64 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateDocument", func() {
+	config := []byte("Replicas: 3\nServer:\n  Port: 8080\n  Host: example.com\nRatio: 0.5\n")
+	metadata := ValidationMetadata{StructName: "AppConfig", Operation: "Default", RuleIndex: -1}
+	rules := []RuleEntry{
+		{Rule: "Replicas >= 2", Enabled: true},
+		{Rule: "Server.Port < 1024", Enabled: true, FailureMessage: "privileged port required"},
+		{Rule: "Ratio <= 1.0", Enabled: true},
+	}
+
+	It("validates a YAML document with inferred types", func() {
+		results, err := NewValidator().ValidateDocument(config, nil, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[1].Passed).To(BeFalse())
+		Expect(results[1].Message).To(Equal("privileged port required"))
+		Expect(results[2].Passed).To(BeTrue())
+	})
+
+	It("infers a schema from a sample", func() {
+		schema, err := SchemaFromSample(config)
+		Expect(err).To(BeNil())
+		Expect(schema).To(Equal(DocumentSchema{
+			"Replicas":    "int",
+			"Server":      "map",
+			"Server.Port": "int",
+			"Server.Host": "string",
+			"Ratio":       "double",
+		}))
+	})
+
+	It("compiles rules on fields missing from the document through the schema", func() {
+		schema := DocumentSchema{"Replicas": "int", "Server.Port": "int", "Ratio": "double", "Debug": "bool"}
+		results, err := NewValidator(WithPartialEval()).ValidateDocument([]byte(`{"Replicas": 1}`), schema,
+			[]RuleEntry{{Rule: "Debug == false", Enabled: true}}, metadata)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(1))
+		Expect(isCompileError(results[0])).To(BeFalse())
+		Expect(results[0].Error).To(MatchError(ContainSubstring("Debug")))
+	})
+
+	It("keeps nested objects addressable as maps", func() {
+		doc := []byte("Locations:\n  hq:\n    City: Lisbon\n    Floors: [1, 2]\n  lab:\n    City: ''\n")
+		results, err := NewValidator().ValidateDocument(doc, nil, []RuleEntry{
+			{Rule: "Locations['hq'].City != ''", Enabled: true},
+			{Rule: "Locations.all(k, Locations[k].City != '')", Enabled: true},
+			{Rule: "Locations['hq'].Floors.exists(f, f == 2)", Enabled: true},
+			{Rule: "Locations.hq.City == 'Lisbon'", Enabled: true},
+		}, metadata)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(4))
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[1].Passed).To(BeFalse())
+		Expect(results[2].Passed).To(BeTrue())
+		Expect(results[3].Passed).To(BeTrue())
+	})
+})
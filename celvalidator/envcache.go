@@ -0,0 +1,160 @@
+package celvalidator
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+)
+
+// maxCachedEnvs bounds the environments a validator caches, as the shapes of
+// JSON objects are chosen by whoever sends them. Environments past it are
+// built for each validation.
+const maxCachedEnvs = 1024
+
+// envCache holds the environments built by a validator. Declarations are
+// inferred from values, a nil pointer declaring a dyn field instead of the
+// fields under it, so environments are keyed by type and by the shape of the
+// inferred declarations.
+type envCache struct {
+	mu   sync.RWMutex
+	envs map[envKey]*cel.Env
+	keys map[*cel.Env]envKey
+}
+
+type envKey struct {
+	typ   reflect.Type
+	shape string
+}
+
+// jsonObject is the type environments of JSON and YAML documents are keyed by
+type jsonObject struct{}
+
+func newEnvCache() *envCache {
+	return &envCache{envs: map[envKey]*cel.Env{}, keys: map[*cel.Env]envKey{}}
+}
+
+func (c *envCache) get(key envKey) (*cel.Env, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	env, ok := c.envs[key]
+	return env, ok
+}
+
+func (c *envCache) put(key envKey, env *cel.Env) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.envs) >= maxCachedEnvs {
+		return
+	}
+	c.envs[key] = env
+	c.keys[env] = key
+}
+
+// keyOf returns the key of a cached environment
+func (c *envCache) keyOf(env *cel.Env) (envKey, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key, ok := c.keys[env]
+	return key, ok
+}
+
+// len returns the number of cached environments
+func (c *envCache) len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.envs)
+}
+
+// documentEnv returns the environment declaring the fields of a JSON or YAML
+// document, shared by documents of the same shape
+func (v *Validator) documentEnv(fields map[string]any) (*cel.Env, error) {
+	key := envKey{typ: reflect.TypeOf(jsonObject{}), shape: fieldsShape(fields)}
+	if v.envs != nil {
+		if env, ok := v.envs.get(key); ok {
+			return env, nil
+		}
+	}
+	env, err := newEnvFromFields(fields, append(v.envOptions(), v.globalDeclarations(declaredIn(fields))...)...)
+	if err != nil {
+		return nil, err
+	}
+	if v.envs != nil {
+		v.envs.put(key, env)
+	}
+	return env, nil
+}
+
+// fieldsShape fingerprints the declarations newEnvFromFields infers from fields
+func fieldsShape(fields map[string]any) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte(':')
+		tag := typeTag(fields[name])
+		b.WriteByte(tag)
+		if tag == 'T' {
+			b.WriteString(reflect.TypeOf(fields[name]).String())
+		}
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+// typeTag identifies the type inferType declares for val
+func typeTag(val any) byte {
+	switch val.(type) {
+	case map[string]any:
+		return 'm'
+	case []any:
+		return 'l'
+	case []map[string]any:
+		return 'M'
+	case []string:
+		return 'S'
+	case []int, []int64:
+		return 'I'
+	case []float32, []float64:
+		return 'F'
+	case []bool:
+		return 'B'
+	case string:
+		return 's'
+	case int, int64:
+		return 'i'
+	case float32, float64:
+		return 'd'
+	case bool:
+		return 'b'
+	case Decimal:
+		return 'D'
+	case time.Time:
+		return 't'
+	// Nullable scalars of pointer fields
+	case *string:
+		return 'p'
+	case *bool:
+		return 'q'
+	case *int, *int32, *int64:
+		return 'r'
+	case *uint, *uint32, *uint64:
+		return 'u'
+	case *float32, *float64:
+		return 'f'
+	default:
+		// Typed from the Go type of the value, written after the tag
+		if _, ok := reflectType(reflect.TypeOf(val)); ok {
+			return 'T'
+		}
+		return '?'
+	}
+}
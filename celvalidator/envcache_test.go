@@ -0,0 +1,73 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type contact struct {
+	Address *Address
+}
+
+type labelled struct {
+	Labels any
+}
+
+var _ = Describe("Environment cache", func() {
+	rules := []RuleEntry{{Rule: "Age > 18", Enabled: true}}
+	metadata := ValidationMetadata{StructName: "Sample"}
+
+	It("reuses environments across validations of a type", func() {
+		v := NewValidator()
+		for _, age := range []int{10, 20, 30} {
+			_, err := v.Validate(Sample{Age: age}, rules, metadata)
+			Expect(err).To(BeNil())
+		}
+		Expect(v.envs.len()).To(Equal(1))
+
+		_, err := v.Validate(&Sample{Age: 40}, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(v.envs.len()).To(Equal(2))
+	})
+
+	It("builds separate environments for different field shapes", func() {
+		v := NewValidator(WithPartialEval())
+		rules := []RuleEntry{{Rule: "Address.City == 'Toronto'", Enabled: true}}
+
+		results, err := v.Validate(contact{Address: &Address{City: "Toronto"}}, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeTrue())
+
+		results, err = v.Validate(contact{}, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeFalse())
+		Expect(v.envs.len()).To(Equal(2))
+	})
+
+	It("shares environments and programs across JSON objects of a shape", func() {
+		v := NewValidator()
+		for _, data := range []string{`{"Age": 10}`, `{"Age": 30}`} {
+			_, err := v.ValidateJSON([]byte(data), rules, metadata)
+			Expect(err).To(BeNil())
+		}
+		Expect(v.envs.len()).To(Equal(1))
+		Expect(v.Stats().Hits).To(Equal(uint64(1)))
+
+		_, err := v.ValidateJSON([]byte(`{"Age": 40, "Email": "a@b.c"}`), rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(v.envs.len()).To(Equal(2))
+	})
+
+	It("builds separate environments for different map types", func() {
+		v := NewValidator()
+		rules := []RuleEntry{{Rule: "Labels['a'] == 1", Enabled: true}}
+
+		results, err := v.Validate(labelled{Labels: map[string]int{"a": 1}}, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeTrue())
+
+		_, err = v.Validate(labelled{Labels: map[string]string{"a": "1"}}, rules, metadata)
+		Expect(err).To(MatchError(ContainSubstring("no matching overload")))
+		Expect(v.envs.len()).To(Equal(2))
+	})
+})
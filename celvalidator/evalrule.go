@@ -0,0 +1,57 @@
+package celvalidator
+
+import (
+	"context"
+	"fmt"
+)
+
+// EvalRule evaluates a single rule against obj and reports whether it holds,
+// for callers needing a quick predicate rather than a report. The environment
+// and program are cached like those of Validate, but no result is built, so
+// audit sinks, failure routers and optimizers do not see the evaluation.
+func (v *Validator) EvalRule(obj any, rule string) (bool, error) {
+	return v.EvalRuleContext(context.Background(), obj, rule)
+}
+
+// EvalRuleContext is EvalRule with a context
+func (v *Validator) EvalRuleContext(ctx context.Context, obj any, rule string) (bool, error) {
+	env, vars, err := v.buildEnv(obj)
+	if err != nil {
+		return false, err
+	}
+	e := v.newEvaluation(ctx, env, vars, ValidationMetadata{StructName: getStructName(obj), RuleIndex: -1})
+	return e.evalRule(rule)
+}
+
+// evalRule evaluates rule like evalEntry, returning the outcome instead of a result
+func (e *evaluation) evalRule(rule string) (bool, error) {
+	c := e.compileRule(rule)
+	if iss := c.iss; iss != nil && iss.Err() != nil {
+		return false, iss.Err()
+	}
+	if err := e.nonFiniteError(rule); err != nil {
+		return false, err
+	}
+	prg, err := e.program(c)
+	if err != nil {
+		return false, err
+	}
+
+	out, _, err := e.evalProgram(prg, RuleEntry{Rule: rule})
+	if ctxErr := e.ctx.Err(); err != nil && ctxErr != nil {
+		err = ctxErr
+	}
+	if err = e.v.costError(err); err != nil {
+		return false, err
+	}
+	if e.v.checkedArith {
+		if err := e.checkOverflow(c); err != nil {
+			return false, err
+		}
+	}
+	passed, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("rule %q evaluated to %v, not a bool", rule, out.Value())
+	}
+	return passed, nil
+}
@@ -0,0 +1,47 @@
+package celvalidator
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Single rule evaluation", func() {
+	It("reports whether the rule holds", func() {
+		v := NewValidator()
+		passed, err := v.EvalRule(Sample{Age: 20}, "Age >= 18")
+		Expect(err).To(BeNil())
+		Expect(passed).To(BeTrue())
+
+		passed, err = v.EvalRule(&Sample{Age: 10}, "Age >= 18")
+		Expect(err).To(BeNil())
+		Expect(passed).To(BeFalse())
+	})
+
+	It("reports compile, runtime and non-bool errors", func() {
+		v := NewValidator()
+		_, err := v.EvalRule(Sample{}, "Unknown > 1")
+		Expect(err).To(MatchError(ContainSubstring("undeclared reference")))
+		_, err = v.EvalRule(Sample{}, "Age + 1")
+		Expect(err).To(MatchError(ContainSubstring("not a bool")))
+		_, err = NewValidator(WithCostLimit(1, 0)).EvalRule(Sample{Email: "a@b.c"}, "[1, 2, 3].all(i, i > 0) && Email != ''")
+		Expect(err).To(MatchError(ErrRuleCostLimit))
+	})
+
+	It("reuses cached programs and allocates less than a validation", func() {
+		v := NewValidator()
+		obj := Sample{Age: 20}
+		_, err := v.EvalRule(obj, "Age >= 18")
+		Expect(err).To(BeNil())
+		_, err = v.EvalRule(obj, "Age >= 18")
+		Expect(err).To(BeNil())
+		Expect(v.Stats()).To(Equal(ProgramCacheStats{Size: 1, Capacity: defaultProgramCacheSize, Hits: 1, Misses: 1}))
+
+		rules := []RuleEntry{{Rule: "Age >= 18", Enabled: true}}
+		md := ValidationMetadata{StructName: "Sample"}
+		validate := testing.AllocsPerRun(100, func() { _, _ = v.Validate(obj, rules, md) })
+		eval := testing.AllocsPerRun(100, func() { _, _ = v.EvalRule(obj, "Age >= 18") })
+		Expect(eval).To(BeNumerically("<", validate))
+	})
+})
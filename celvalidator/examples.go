@@ -0,0 +1,165 @@
+package celvalidator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// RuleExamples are sample field values a rule passes and fails, e.g. {Age: 20}
+// and {Age: 10} for Age >= 18. Nested maps are flattened like JSON documents.
+type RuleExamples struct {
+	Pass []map[string]any `yaml:"pass,omitempty"`
+	Fail []map[string]any `yaml:"fail,omitempty"`
+}
+
+// ExampleFailure is an example whose outcome is not the documented one
+type ExampleFailure struct {
+	StructName string
+	Operation  string
+	ID         string
+	Rule       string
+	Example    map[string]any
+	// WantPass is set for pass examples
+	WantPass bool
+	Passed   bool
+	// Error is the error evaluating the rule against the example, if any
+	Error  error
+	Source RuleSource
+}
+
+func (f ExampleFailure) String() string {
+	want, got := outcomeName(f.WantPass), outcomeName(f.Passed)
+	if f.Error != nil {
+		got = "error: " + f.Error.Error()
+	}
+	return fmt.Sprintf("%s.%s %q: example %s should %s, got %s", f.StructName, f.Operation, f.Rule, exampleString(f.Example), want, got)
+}
+
+func outcomeName(passed bool) string {
+	if passed {
+		return "pass"
+	}
+	return "fail"
+}
+
+// exampleString renders an example as compact JSON
+func exampleString(example map[string]any) string {
+	data, err := json.Marshal(example)
+	if err != nil {
+		return fmt.Sprint(example)
+	}
+	return string(data)
+}
+
+// VerifyExamples evaluates every rule of a rule set, including Then children,
+// against its examples and returns the examples whose outcome differs
+func (v *Validator) VerifyExamples(rules RuleSetMap) []ExampleFailure {
+	var failures []ExampleFailure
+	for _, structName := range sortedRuleSetKeys(rules) {
+		operations := rules[structName]
+		ops := make([]string, 0, len(operations))
+		for op := range operations {
+			ops = append(ops, op)
+		}
+		sort.Strings(ops)
+		for _, op := range ops {
+			for _, entry := range flattenRuleTree(operations[op]) {
+				failures = v.verifyExamples(failures, structName, op, entry)
+			}
+		}
+	}
+	return failures
+}
+
+func (v *Validator) verifyExamples(failures []ExampleFailure, structName, op string, entry RuleEntry) []ExampleFailure {
+	check := func(example map[string]any, wantPass bool) {
+		passed, err := v.evalExample(structName, op, entry.Rule, example)
+		if err == nil && passed == wantPass {
+			return
+		}
+		failures = append(failures, ExampleFailure{
+			StructName: structName,
+			Operation:  op,
+			ID:         entry.ID,
+			Rule:       entry.Rule,
+			Example:    example,
+			WantPass:   wantPass,
+			Passed:     passed,
+			Error:      err,
+			Source:     entry.Source,
+		})
+	}
+	for _, example := range entry.Examples.Pass {
+		check(example, true)
+	}
+	for _, example := range entry.Examples.Fail {
+		check(example, false)
+	}
+	return failures
+}
+
+// evalExample evaluates rule against the fields of an example like ValidateJSON
+func (v *Validator) evalExample(structName, op, rule string, example map[string]any) (bool, error) {
+	data, err := json.Marshal(example)
+	if err != nil {
+		return false, fmt.Errorf("marshalling example: %w", err)
+	}
+	metadata := ValidationMetadata{StructName: structName, Operation: op, RuleIndex: -1}
+	results, err := v.ValidateJSON(data, []RuleEntry{{Rule: rule, Enabled: true}}, metadata)
+	if err != nil {
+		return false, err
+	}
+	return results[0].Passed, results[0].Error
+}
+
+// WriteRuleDocs writes the rules of a rule set as Markdown, a section per
+// struct and operation listing each rule with its message and examples
+func WriteRuleDocs(w io.Writer, rules RuleSetMap) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	var writeEntries func(entries []RuleEntry, indent string)
+	writeEntries = func(entries []RuleEntry, indent string) {
+		for _, entry := range entries {
+			if entry.Override != "" {
+				continue
+			}
+			printf("%s- `%s`", indent, entry.Rule)
+			if entry.FailureMessage != "" {
+				printf(": %s", entry.FailureMessage)
+			}
+			printf("\n")
+			for _, example := range entry.Examples.Pass {
+				printf("%s  - passes `%s`\n", indent, exampleString(example))
+			}
+			for _, example := range entry.Examples.Fail {
+				printf("%s  - fails `%s`\n", indent, exampleString(example))
+			}
+			writeEntries(entry.Then, indent+"  ")
+		}
+	}
+
+	for i, structName := range sortedRuleSetKeys(rules) {
+		if i > 0 {
+			printf("\n")
+		}
+		printf("## %s\n", structName)
+		operations := rules[structName]
+		ops := make([]string, 0, len(operations))
+		for op := range operations {
+			ops = append(ops, op)
+		}
+		sort.Strings(ops)
+		for _, op := range ops {
+			printf("\n### %s\n\n", op)
+			writeEntries(operations[op], "")
+		}
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package celvalidator
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rule examples", func() {
+	rules := RuleSetMap{
+		"User": {
+			"Default": {{
+				ID: "adult", Rule: "Age >= 18", Enabled: true,
+				Examples: RuleExamples{
+					Pass: []map[string]any{{"Age": 18}},
+					Fail: []map[string]any{{"Age": 10}, {"Age": 30}},
+				},
+				Then: []RuleEntry{{
+					Rule: "Address.City != ''", Enabled: true,
+					Examples: RuleExamples{Pass: []map[string]any{{"Address": map[string]any{"City": "Oslo"}}, {"Name": "Ann"}}},
+				}},
+			}},
+		},
+	}
+
+	It("reports the examples whose outcome differs, Then children included", func() {
+		failures := NewValidator(WithPartialEval()).VerifyExamples(rules)
+		Expect(failures).To(HaveLen(2))
+		Expect(failures[0].ID).To(Equal("adult"))
+		Expect(failures[0].WantPass).To(BeFalse())
+		Expect(failures[0].Passed).To(BeTrue())
+		Expect(failures[0].String()).To(Equal(`User.Default "Age >= 18": example {"Age":30} should fail, got pass`))
+		Expect(failures[1].Rule).To(Equal("Address.City != ''"))
+		Expect(failures[1].Error).To(MatchError(ContainSubstring("undeclared reference")))
+	})
+
+	It("renders examples in rule docs", func() {
+		var b strings.Builder
+		Expect(WriteRuleDocs(&b, rules)).To(Succeed())
+		Expect(b.String()).To(Equal("## User\n\n### Default\n\n" +
+			"- `Age >= 18`\n  - passes `{\"Age\":18}`\n  - fails `{\"Age\":10}`\n  - fails `{\"Age\":30}`\n" +
+			"  - `Address.City != ''`\n    - passes `{\"Address\":{\"City\":\"Oslo\"}}`\n    - passes `{\"Name\":\"Ann\"}`\n"))
+	})
+
+	It("loads examples from YAML", func() {
+		file, err := ParseRuleFileYAML([]byte("User:\n  Default:\n    - rule: \"Age >= 18\"\n      enabled: true\n      examples:\n        pass: [{Age: 18}]\n        fail: [{Age: 1}]\n"))
+		Expect(err).To(BeNil())
+		Expect(file.Rules["User"]["Default"][0].Examples).To(Equal(RuleExamples{
+			Pass: []map[string]any{{"Age": 18}},
+			Fail: []map[string]any{{"Age": 1}},
+		}))
+		Expect(NewValidator().VerifyExamples(file.Rules)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,25 @@
+package celvalidator
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+// WithExtensions adds CEL environment options to the validator, e.g. cel-go's
+// extension libraries: WithExtensions(ext.Strings(), ext.Math())
+func WithExtensions(opts ...cel.EnvOption) ValidatorOption {
+	return func(v *Validator) {
+		v.extensions = append(v.extensions, opts...)
+	}
+}
+
+// celExtensions are the cel-go extension libraries enabled by name in the
+// validator configuration
+var celExtensions = map[string]cel.EnvOption{
+	"strings":        ext.Strings(),
+	"math":           ext.Math(),
+	"sets":           ext.Sets(),
+	"lists":          ext.Lists(),
+	"encoders":       ext.Encoders(),
+	"optional_types": cel.OptionalTypes(),
+}
@@ -0,0 +1,42 @@
+package celvalidator
+
+import (
+	"github.com/google/cel-go/ext"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CEL extensions", func() {
+	md := ValidationMetadata{StructName: "User"}
+	user := User{Name: "ANN", Age: 30, Address: Address{Zip: 12}}
+
+	validate := func(v *Validator, rule string) ValidationResult {
+		results, err := v.Validate(user, []RuleEntry{{Rule: rule, Enabled: true}}, md)
+		Expect(err).To(BeNil())
+		return results[0]
+	}
+
+	It("enables cel-go extension libraries", func() {
+		v := NewValidator(WithExtensions(ext.Strings(), ext.Math()))
+		Expect(validate(v, "Name.lowerAscii() == 'ann'").Passed).To(BeTrue())
+		Expect(validate(v, "math.greatest(Age, Address.Zip) == 30").Passed).To(BeTrue())
+	})
+
+	It("leaves extensions disabled by default", func() {
+		result := validate(NewValidator(WithPartialEval()), "Name.lowerAscii() == 'ann'")
+		Expect(result.Error).To(HaveOccurred())
+	})
+
+	It("enables extensions by name in the validator config", func() {
+		opts, err := ValidatorConfig{Extensions: []string{"strings", "sets", "lists", "encoders", "optional_types", "math"}}.Options()
+		Expect(err).To(BeNil())
+		v := NewValidator(opts...)
+		Expect(validate(v, "sets.contains([1, 2, 3], [Age / 10])").Passed).To(BeTrue())
+		Expect(validate(v, "[3, 1, 2].sort() == [1, 2, 3]").Passed).To(BeTrue())
+		Expect(validate(v, "base64.encode(b'hi') == 'aGk='").Passed).To(BeTrue())
+		Expect(validate(v, "optional.of(Name).orValue('') == 'ANN'").Passed).To(BeTrue())
+
+		_, err = ValidatorConfig{Extensions: []string{"bogus"}}.Options()
+		Expect(err).To(MatchError(ContainSubstring("unknown extension")))
+	})
+})
@@ -0,0 +1,154 @@
+package celvalidator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+)
+
+// RuleVariables returns the sorted variable paths referenced by a rule, e.g.
+// Age and Address.City, derived from its parsed AST. Paths under self are
+// reported without the self prefix.
+func RuleVariables(rule string) ([]string, error) {
+	env, err := cel.NewEnv(parsePresenceMacro())
+	if err != nil {
+		return nil, err
+	}
+	parsed, iss := env.Parse(rule)
+	if iss != nil && iss.Err() != nil {
+		return nil, iss.Err()
+	}
+
+	paths := map[string]bool{}
+	collectVariables(parsed.NativeRep().Expr(), map[string]bool{}, paths)
+
+	vars := make([]string, 0, len(paths))
+	for path := range paths {
+		vars = append(vars, path)
+	}
+	sort.Strings(vars)
+	return vars, nil
+}
+
+// collectVariables adds the paths referenced by e to paths, bound holding the
+// comprehension variables in scope
+func collectVariables(e ast.Expr, bound map[string]bool, paths map[string]bool) {
+	if path, ok := selectPath(e); ok {
+		root, rest, _ := strings.Cut(path, ".")
+		switch {
+		case bound[root]:
+		case root == selfVariable:
+			if rest != "" {
+				paths[rest] = true
+			}
+		default:
+			paths[path] = true
+		}
+		return
+	}
+
+	switch e.Kind() {
+	case ast.SelectKind:
+		collectVariables(e.AsSelect().Operand(), bound, paths)
+
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.IsMemberFunction() {
+			collectVariables(call.Target(), bound, paths)
+		}
+		for _, arg := range call.Args() {
+			collectVariables(arg, bound, paths)
+		}
+
+	case ast.ComprehensionKind:
+		c := e.AsComprehension()
+		collectVariables(c.IterRange(), bound, paths)
+
+		inner := make(map[string]bool, len(bound)+3)
+		for name := range bound {
+			inner[name] = true
+		}
+		inner[c.IterVar()] = true
+		inner[c.AccuVar()] = true
+		if c.HasIterVar2() {
+			inner[c.IterVar2()] = true
+		}
+		collectVariables(c.AccuInit(), inner, paths)
+		collectVariables(c.LoopCondition(), inner, paths)
+		collectVariables(c.LoopStep(), inner, paths)
+		collectVariables(c.Result(), inner, paths)
+
+	case ast.ListKind:
+		for _, el := range e.AsList().Elements() {
+			collectVariables(el, bound, paths)
+		}
+
+	case ast.MapKind:
+		for _, entry := range e.AsMap().Entries() {
+			collectVariables(entry.AsMapEntry().Key(), bound, paths)
+			collectVariables(entry.AsMapEntry().Value(), bound, paths)
+		}
+
+	case ast.StructKind:
+		for _, field := range e.AsStruct().Fields() {
+			collectVariables(field.AsStructField().Value(), bound, paths)
+		}
+	}
+}
+
+// selectPath returns the dotted path of an identifier or of a chain of field
+// selections, including has() tests, on an identifier
+func selectPath(e ast.Expr) (string, bool) {
+	switch e.Kind() {
+	case ast.IdentKind:
+		return e.AsIdent(), true
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		operand, ok := selectPath(sel.Operand())
+		if !ok {
+			return "", false
+		}
+		return operand + "." + sel.FieldName(), true
+	}
+	return "", false
+}
+
+// SelectRulesByFieldMask keeps the rules referencing a path of mask, the paths
+// being written, so an update only runs the rules relevant to changed fields.
+// A path matches the fields under it, e.g. Address matches Address.City. A rule
+// whose Then children match is kept as their guard, with only matching children.
+// Rules that cannot be parsed are kept so their error is still reported.
+func SelectRulesByFieldMask(rules []RuleEntry, mask []string) []RuleEntry {
+	var selected []RuleEntry
+	for _, entry := range rules {
+		children := SelectRulesByFieldMask(entry.Then, mask)
+		if len(children) == 0 && !ruleMatchesMask(entry.Rule, mask) {
+			continue
+		}
+		entry.Then = children
+		selected = append(selected, entry)
+	}
+	return selected
+}
+
+func ruleMatchesMask(rule string, mask []string) bool {
+	vars, err := RuleVariables(rule)
+	if err != nil {
+		return true
+	}
+	return variablesMatchMask(vars, mask)
+}
+
+// variablesMatchMask reports whether any variable path is under or above a mask path
+func variablesMatchMask(vars, mask []string) bool {
+	for _, v := range vars {
+		for _, path := range mask {
+			if v == path || strings.HasPrefix(v, path+".") || strings.HasPrefix(path, v+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
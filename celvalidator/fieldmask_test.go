@@ -0,0 +1,38 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Field mask selection", func() {
+	It("derives referenced variables from the rule AST", func() {
+		vars, err := RuleVariables("Address.City == 'LA' && Tags.all(t, t != Name) && has(self.Zip) && size(Email) > 0")
+		Expect(err).To(BeNil())
+		Expect(vars).To(Equal([]string{"Address.City", "Email", "Name", "Tags", "Zip"}))
+	})
+
+	It("selects rules intersecting the mask", func() {
+		rules := []RuleEntry{
+			{Rule: "Age > 18", Enabled: true},
+			{Rule: "Address.City != ''", Enabled: true},
+			{Rule: "Active", Enabled: true, Then: []RuleEntry{
+				{Rule: "Email != ''", Enabled: true},
+				{Rule: "Age < 100", Enabled: true},
+			}},
+		}
+
+		selected := SelectRulesByFieldMask(rules, []string{"Address"})
+		Expect(selected).To(ConsistOf(HaveField("Rule", "Address.City != ''")))
+
+		selected = SelectRulesByFieldMask(rules, []string{"Email"})
+		Expect(selected).To(HaveLen(1))
+		Expect(selected[0].Rule).To(Equal("Active"))
+		Expect(selected[0].Then).To(ConsistOf(HaveField("Rule", "Email != ''")))
+
+		selected = SelectRulesByFieldMask(rules, []string{"Age"})
+		Expect(selected).To(HaveLen(2))
+		Expect(selected[1].Then).To(ConsistOf(HaveField("Rule", "Age < 100")))
+		Expect(rules[2].Then).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,38 @@
+package celvalidator
+
+import "strings"
+
+// fieldPaths returns the request fields a failure of entry is attributed to,
+// its FieldPath when set, otherwise the fields of the validated object the
+// rule references. Globals and comprehension variables are left out.
+func (e *evaluation) fieldPaths(entry RuleEntry) []string {
+	if entry.FieldPath != "" {
+		return []string{entry.FieldPath}
+	}
+	vars, err := RuleVariables(entry.Rule)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, path := range vars {
+		if isObjectField(e.fields, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// isObjectField reports whether path, or a field above it for nested fields,
+// is a field of the validated object
+func isObjectField(fields map[string]any, path string) bool {
+	for {
+		if _, ok := fields[path]; ok {
+			return true
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			return false
+		}
+		path = path[:i]
+	}
+}
@@ -0,0 +1,45 @@
+package celvalidator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Field paths", func() {
+	md := ValidationMetadata{StructName: "User", Operation: "Default", RuleIndex: -1}
+	user := User{Name: "", Age: 10, Address: Address{City: ""}}
+
+	It("attributes failures to the fields the rule references", func() {
+		for _, v := range []*Validator{NewValidator(), NewValidator(WithNestedFields())} {
+			results, err := v.Validate(user, []RuleEntry{
+				{Rule: "Age >= 18 || Name == 'root'", Enabled: true},
+				{Rule: "Address.City != ''", Enabled: true},
+				{Rule: "Age > 0", Enabled: true},
+			}, md)
+			Expect(err).To(BeNil())
+			Expect(results[0].FieldPaths).To(Equal([]string{"Age", "Name"}))
+			Expect(results[1].FieldPaths).To(Equal([]string{"Address.City"}))
+			Expect(results[2].Passed).To(BeTrue())
+			Expect(results[2].FieldPaths).To(BeNil())
+		}
+	})
+
+	It("prefers the field path of the rule", func() {
+		results, err := NewValidator().Validate(user, []RuleEntry{
+			{Rule: "Age >= 18 || Name == 'root'", Enabled: true, FieldPath: "Age"},
+		}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].FieldPaths).To(Equal([]string{"Age"}))
+	})
+
+	It("leaves out globals and comprehension variables", func() {
+		v := NewValidator(WithGlobals(map[string]any{"minAge": 18}))
+		results, err := v.ValidateContext(context.Background(), user, []RuleEntry{
+			{Rule: "Age >= minAge && [Name].all(n, n != '')", Enabled: true},
+		}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].FieldPaths).To(Equal([]string{"Age", "Name"}))
+	})
+})
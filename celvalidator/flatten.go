@@ -0,0 +1,106 @@
+package celvalidator
+
+import (
+	"reflect"
+	"sync"
+)
+
+// flattenPlan lists the variables a struct type flattens to, computed once per type
+type flattenPlan struct {
+	naming FieldNaming
+	fields []planField
+}
+
+// planField is an exported field of a struct type or of its nested value structs
+type planField struct {
+	// index is the index path of the field from the planned type
+	index []int
+	// name is the dotted variable name of the field
+	name string
+	// dynamic is set for pointer and interface fields, whose value decides
+	// whether they are flattened further
+	dynamic bool
+	// scalar is set for pointers to scalars, declared nullable
+	scalar bool
+}
+
+// flattenPlans caches the flattenPlan of each struct type, per field naming
+var flattenPlans [FieldNamesTag + 1]sync.Map
+
+// planFor returns the cached flattenPlan of a struct type
+func planFor(typ reflect.Type, naming FieldNaming) *flattenPlan {
+	if plan, ok := flattenPlans[naming].Load(typ); ok {
+		return plan.(*flattenPlan)
+	}
+	plan := &flattenPlan{naming: naming}
+	plan.add(typ, nil, "")
+	actual, _ := flattenPlans[naming].LoadOrStore(typ, plan)
+	return actual.(*flattenPlan)
+}
+
+// add appends the fields of typ, inlining nested value structs. Pointer fields
+// are planned when flattened, so recursive types are planned lazily.
+func (p *flattenPlan) add(typ reflect.Type, index []int, prefix string) {
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		fieldName, ok := p.naming.fieldName(field)
+		if !field.IsExported() || !ok {
+			continue
+		}
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		name := prefix + fieldName
+
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			if !isValueStructType(field.Type) {
+				p.add(field.Type, fieldIndex, name+".")
+				continue
+			}
+		case reflect.Ptr, reflect.Interface:
+			if isScalarPointer(field.Type) {
+				p.fields = append(p.fields, planField{index: fieldIndex, name: name, scalar: true})
+				continue
+			}
+			p.fields = append(p.fields, planField{index: fieldIndex, name: name, dynamic: true})
+			continue
+		}
+		p.fields = append(p.fields, planField{index: fieldIndex, name: name})
+	}
+}
+
+// flattenStruct flattens struct fields (including nested), named by naming
+func flattenStruct(obj any, naming FieldNaming) map[string]any {
+	val := indirect(reflect.ValueOf(obj))
+	if val.Kind() != reflect.Struct {
+		return map[string]any{}
+	}
+	plan := planFor(val.Type(), naming)
+	result := make(map[string]any, len(plan.fields))
+	plan.flatten(result, "", val)
+	return result
+}
+
+// flatten writes the variables of val to result, prefixing their names
+func (p *flattenPlan) flatten(result map[string]any, prefix string, val reflect.Value) {
+	for _, f := range p.fields {
+		value := val.FieldByIndex(f.index)
+		name := f.name
+		if prefix != "" {
+			name = prefix + name
+		}
+		if f.scalar {
+			result[name] = value.Interface()
+			continue
+		}
+		if !f.dynamic {
+			result[name] = celValue(value, p.naming)
+			continue
+		}
+
+		if value = indirect(value); value.Kind() == reflect.Struct && !isValueStruct(value) {
+			planFor(value.Type(), p.naming).flatten(result, name+".", value)
+			continue
+		}
+		result[name] = celValue(value, p.naming)
+	}
+}
@@ -0,0 +1,60 @@
+package celvalidator
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type flattenNode struct {
+	Name  string
+	Next  *flattenNode
+	Meta  any
+	Tags  []string
+	Level Severity
+	inner string
+}
+
+type flattenWide struct {
+	User     User
+	Manager  *User
+	Score    float64
+	Count    int64
+	Verified bool
+}
+
+var _ = Describe("Flattening", func() {
+	It("flattens nested value structs and set pointers", func() {
+		manager := &User{Name: "Ann", Address: Address{City: "Oslo"}}
+		fields := flattenStruct(&flattenWide{User: User{Age: 30}, Manager: manager, Score: 1.5, Count: 7}, FieldNamesGo)
+		Expect(fields).To(HaveKeyWithValue("User.Age", 30))
+		Expect(fields).To(HaveKeyWithValue("User.Address.Zip", 0))
+		Expect(fields).To(HaveKeyWithValue("Manager.Name", "Ann"))
+		Expect(fields).To(HaveKeyWithValue("Manager.Address.City", "Oslo"))
+		Expect(fields).To(HaveKeyWithValue("Score", 1.5))
+		Expect(fields).To(HaveKeyWithValue("Count", int64(7)))
+		Expect(fields).To(HaveKeyWithValue("Verified", false))
+		Expect(fields).To(HaveLen(17))
+	})
+
+	It("keeps nil pointers as null and flattens recursive types by value", func() {
+		fields := flattenStruct(flattenNode{Name: "a", Next: &flattenNode{Name: "b", Meta: &Address{City: "Rome"}}, Level: SeverityWarning, inner: "x"}, FieldNamesGo)
+		Expect(fields).To(HaveKeyWithValue("Name", "a"))
+		Expect(fields).To(HaveKeyWithValue("Meta", BeNil()))
+		Expect(fields).To(HaveKeyWithValue("Level", SeverityWarning))
+		Expect(fields).To(HaveKeyWithValue("Next.Name", "b"))
+		Expect(fields).To(HaveKeyWithValue("Next.Next", BeNil()))
+		Expect(fields).To(HaveKeyWithValue("Next.Meta.City", "Rome"))
+		Expect(fields).NotTo(HaveKey("inner"))
+	})
+
+	It("allocates no more than one value per field", func() {
+		obj := flattenWide{User: User{Name: "Bob", Age: 30, Email: "bob@example.com"}, Score: 1.5}
+		fields := flattenStruct(obj, FieldNamesGo)
+		allocs := testing.AllocsPerRun(100, func() {
+			flattenStruct(obj, FieldNamesGo)
+		})
+		Expect(allocs).To(BeNumerically("<=", len(fields)))
+	})
+})
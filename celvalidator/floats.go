@@ -0,0 +1,82 @@
+package celvalidator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ErrNonFiniteFloat is reported on the result of a rule referencing a NaN or
+// infinite field when WithFiniteFloats is set
+var ErrNonFiniteFloat = errors.New("non-finite float value")
+
+// WithFiniteFloats reports rules referencing a NaN or infinite float field as
+// errors with ErrNonFiniteFloat instead of evaluating them. Otherwise CEL
+// errors when ordering NaN but compares it unequal to everything, itself
+// included, and orders infinities like any other double.
+func WithFiniteFloats() ValidatorOption {
+	return func(v *Validator) {
+		v.finiteFloats = true
+	}
+}
+
+// nonFiniteFields returns the sorted paths of the NaN or infinite floats of
+// vars, following nested maps and lists
+func nonFiniteFields(vars map[string]any) []string {
+	var paths []string
+	var walk func(path string, val any)
+	walk = func(path string, val any) {
+		switch val := val.(type) {
+		case float64:
+			if math.IsNaN(val) || math.IsInf(val, 0) {
+				paths = append(paths, path)
+			}
+		case float32:
+			walk(path, float64(val))
+		case map[string]any:
+			for k, v := range val {
+				walk(path+"."+k, v)
+			}
+		case []any:
+			for _, v := range val {
+				walk(path, v)
+			}
+		case []map[string]any:
+			for _, v := range val {
+				walk(path, v)
+			}
+		case []float64:
+			for _, v := range val {
+				walk(path, v)
+			}
+		case []float32:
+			for _, v := range val {
+				walk(path, v)
+			}
+		}
+	}
+	for name, val := range vars {
+		walk(name, val)
+	}
+	sort.Strings(paths)
+	return slices.Compact(paths)
+}
+
+// nonFiniteError returns an ErrNonFiniteFloat error when rule references a
+// non-finite field of the validation
+func (e *evaluation) nonFiniteError(rule string) error {
+	if len(e.nonFinite) == 0 {
+		return nil
+	}
+	for _, ref := range referencedFields(rule) {
+		for _, path := range e.nonFinite {
+			if path == ref || strings.HasPrefix(ref, path+".") || strings.HasPrefix(path, ref+".") {
+				return fmt.Errorf("%w: %s", ErrNonFiniteFloat, path)
+			}
+		}
+	}
+	return nil
+}
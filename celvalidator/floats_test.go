@@ -0,0 +1,73 @@
+package celvalidator
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type Measurement struct {
+	Name     string
+	Value    float64
+	Limit    float64
+	Readings []float64
+}
+
+var _ = Describe("Non-finite floats", func() {
+	md := ValidationMetadata{StructName: "Measurement"}
+	rules := []RuleEntry{
+		{Rule: "Value < Limit", Enabled: true},
+		{Rule: "!(Value < Limit)", Enabled: true},
+		{Rule: "Name == ''", Enabled: true},
+		{Rule: "Readings.all(r, r >= 0.0)", Enabled: true},
+	}
+
+	It("compares NaN unequal to itself and orders infinities by default", func() {
+		obj := Measurement{Value: math.NaN(), Limit: math.Inf(1)}
+		results, err := NewValidator(WithPartialEval()).Validate(obj, []RuleEntry{
+			{Rule: "Value == Value", Enabled: true},
+			{Rule: "Value < Limit", Enabled: true},
+			{Rule: "Limit > 1e308", Enabled: true},
+		}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeFalse())
+		Expect(results[0].Error).To(BeNil())
+		Expect(results[1].Error).To(MatchError(ContainSubstring("NaN values cannot be ordered")))
+		Expect(results[2].Passed).To(BeTrue())
+	})
+
+	It("reports rules referencing non-finite fields as errors", func() {
+		obj := Measurement{Value: math.NaN(), Limit: 1, Readings: []float64{1, math.Inf(-1)}}
+		results, err := NewValidator(WithPartialEval(), WithFiniteFloats()).Validate(obj, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(4))
+		Expect(results[0].Error).To(MatchError(ErrNonFiniteFloat))
+		Expect(results[0].Error).To(MatchError(ContainSubstring("Value")))
+		Expect(results[0].Outcome).To(Equal(OutcomeError))
+		Expect(results[1].Error).To(MatchError(ErrNonFiniteFloat))
+		Expect(results[2].Passed).To(BeTrue())
+		Expect(results[3].Error).To(MatchError(ContainSubstring("Readings")))
+	})
+
+	It("evaluates finite values normally", func() {
+		results, err := NewValidator(WithFiniteFloats()).Validate(Measurement{Value: 0.5, Limit: 1}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[0].Error).To(BeNil())
+	})
+
+	DescribeTable("helpers",
+		func(rule string, passed bool) {
+			obj := Measurement{Value: math.NaN(), Limit: math.Inf(1)}
+			results, err := NewValidator(WithStdHelpers()).Validate(obj, []RuleEntry{{Rule: rule, Enabled: true}}, md)
+			Expect(err).To(BeNil())
+			Expect(results[0].Error).To(BeNil())
+			Expect(results[0].Passed).To(Equal(passed))
+		},
+		Entry("isNaN", "Value.isNaN()", true),
+		Entry("isInf", "Limit.isInf() && !Value.isInf()", true),
+		Entry("isFinite", "Value.isFinite() || Limit.isFinite()", false),
+		Entry("finite literal", "(1.5).isFinite()", true),
+	)
+})
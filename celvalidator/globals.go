@@ -0,0 +1,64 @@
+package celvalidator
+
+import (
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+)
+
+// WithGlobals declares variables available to every rule, e.g. maxQuota,
+// allowedRegions or feature flags, typed from their values. Fields of the
+// validated object shadow globals of the same name.
+func WithGlobals(globals map[string]any) ValidatorOption {
+	return func(v *Validator) {
+		if v.globals == nil {
+			v.globals = make(map[string]any, len(globals))
+		}
+		for name, val := range globals {
+			v.globals[name] = celValue(reflect.ValueOf(val), FieldNamesGo)
+		}
+	}
+}
+
+// globalDeclarations declares the globals, and now with WithClock, not shadowed
+// by a declared variable
+func (v *Validator) globalDeclarations(declared func(name string) bool) []cel.EnvOption {
+	var opts []cel.EnvOption
+	for name, val := range v.globals {
+		if !declared(name) {
+			opts = append(opts, cel.Declarations(decls.NewVar(name, inferType(val))))
+		}
+	}
+	if v.clock != nil && !declared(nowVariable) {
+		opts = append(opts, cel.Declarations(decls.NewVar(nowVariable, decls.Timestamp)))
+	}
+	return opts
+}
+
+// withGlobals returns vars extended with the globals, and now with WithClock,
+// it does not shadow, vars itself is left untouched
+func (v *Validator) withGlobals(vars map[string]any) map[string]any {
+	if len(v.globals) == 0 && v.clock == nil {
+		return vars
+	}
+	extended := make(map[string]any, len(vars)+len(v.globals)+1)
+	for name, val := range v.globals {
+		extended[name] = val
+	}
+	if v.clock != nil {
+		extended[nowVariable] = v.clock.Now()
+	}
+	for name, val := range vars {
+		extended[name] = val
+	}
+	return extended
+}
+
+// declaredIn reports whether names are declared as variables of fields
+func declaredIn(fields map[string]any) func(string) bool {
+	return func(name string) bool {
+		_, ok := fields[name]
+		return ok
+	}
+}
@@ -0,0 +1,57 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Globals", func() {
+	md := ValidationMetadata{StructName: "Sample", Operation: "Create"}
+	globals := map[string]any{
+		"maxAge":         65,
+		"allowedDomains": []string{"example.com"},
+		"flags":          map[string]bool{"strict": true},
+		"Age":            1000,
+	}
+
+	It("declares globals for every rule, fields shadowing them", func() {
+		v := NewValidator(WithPartialEval(), WithGlobals(globals))
+		rules := []RuleEntry{
+			{Rule: "Age <= maxAge", Enabled: true},
+			{Rule: "allowedDomains.exists(d, Email.endsWith('@' + d))", Enabled: true},
+			{Rule: "!flags.strict || Active", Enabled: true},
+			{Rule: "Age < 100", Enabled: true},
+		}
+		results, err := v.Validate(Sample{Age: 30, Email: "a@example.com", Active: true}, rules, md)
+		Expect(err).To(BeNil())
+		for _, res := range results {
+			Expect(res.Error).To(BeNil())
+			Expect(res.Passed).To(BeTrue(), res.Rule)
+		}
+
+		results, err = v.Validate(Sample{Age: 70, Email: "a@other.org"}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeFalse())
+		Expect(results[1].Passed).To(BeFalse())
+		Expect(results[2].Passed).To(BeFalse())
+	})
+
+	It("declares globals for JSON documents and leaves them out of unmapped fields", func() {
+		v := NewValidator(WithGlobals(globals))
+		results, err := v.ValidateJSON([]byte(`{"Age": 70}`), []RuleEntry{{Rule: "Age > maxAge", Enabled: true}}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeTrue())
+
+		Expect(v.UnmappedFields(Sample{}, []RuleEntry{{Rule: "Age <= maxAge && Missing", Enabled: true}})).To(Equal([]string{"Missing"}))
+	})
+
+	It("reads globals from the configuration", func() {
+		file, err := ParseRuleFileYAML([]byte("validator:\n  globals:\n    maxAge: 65\nSample:\n  Default:\n    - rule: \"Age <= maxAge\"\n      enabled: true\n"))
+		Expect(err).To(BeNil())
+		opts, err := file.Validator.Options()
+		Expect(err).To(BeNil())
+		results, err := NewValidator(opts...).Validate(Sample{Age: 70}, file.Rules["Sample"]["Default"], md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeFalse())
+	})
+})
@@ -0,0 +1,36 @@
+package celvalidator
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Summary headers written by SetSummaryHeaders
+const (
+	HeaderFailedCount = "X-Validation-Failed-Count"
+	HeaderRuleIDs     = "X-Validation-Rule-Ids"
+)
+
+// SetSummaryHeaders writes on h the number of results not passing and the IDs
+// of their rules, comma separated, rules without an ID being counted only. Both
+// headers are written even when every rule passed, so edge proxies and
+// analytics observe the validations of requests let through in shadow mode
+// without parsing bodies. Call it before the response header is written.
+func SetSummaryHeaders(h http.Header, results []ValidationResult) {
+	failed := 0
+	var ids []string
+	seen := map[string]bool{}
+	for _, res := range results {
+		if outcome := resultOutcome(res); outcome == OutcomePassed || outcome == OutcomeSampledOut {
+			continue
+		}
+		failed++
+		if res.ID != "" && !seen[res.ID] {
+			seen[res.ID] = true
+			ids = append(ids, res.ID)
+		}
+	}
+	h.Set(HeaderFailedCount, strconv.Itoa(failed))
+	h.Set(HeaderRuleIDs, strings.Join(ids, ","))
+}
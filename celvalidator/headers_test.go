@@ -0,0 +1,31 @@
+package celvalidator
+
+import (
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SetSummaryHeaders", func() {
+	It("writes the failed count and the IDs of failing rules", func() {
+		h := http.Header{}
+		SetSummaryHeaders(h, []ValidationResult{
+			{ID: "age-min", Passed: false},
+			{ID: "email", Passed: true},
+			{Rule: "Active", Passed: false},
+			{ID: "quota", Error: errors.New("boom")},
+			{ID: "age-min", Passed: false},
+		})
+		Expect(h.Get(HeaderFailedCount)).To(Equal("4"))
+		Expect(h.Get(HeaderRuleIDs)).To(Equal("age-min,quota"))
+	})
+
+	It("writes the headers for passing validations", func() {
+		h := http.Header{}
+		SetSummaryHeaders(h, []ValidationResult{{ID: "email", Passed: true}})
+		Expect(h.Get(HeaderFailedCount)).To(Equal("0"))
+		Expect(h.Values(HeaderRuleIDs)).To(Equal([]string{""}))
+	})
+})
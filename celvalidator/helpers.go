@@ -0,0 +1,94 @@
+package celvalidator
+
+import (
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// WithStdHelpers registers validation helpers usable in rules:
+// s.isEmail(), s.isURL(), s.isUUID(), x.inSet(list),
+// s.lengthBetween(min, max) for strings (in characters) and lists, bounds
+// included, and d.isNaN(), d.isInf() and d.isFinite() for doubles. Regular
+// expressions are matched with CEL's s.matches(re).
+func WithStdHelpers() ValidatorOption {
+	return func(v *Validator) {
+		v.stdHelpers = true
+	}
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// isEmail reports whether s is a bare address, without display name or brackets
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+// isURL reports whether s is an absolute URL with a host
+func isURL(s string) bool {
+	u, err := url.ParseRequestURI(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// stringPredicate binds a string predicate as a CEL unary function
+func stringPredicate(fn func(string) bool) cel.OverloadOpt {
+	return cel.UnaryBinding(func(val ref.Val) ref.Val {
+		return types.Bool(fn(string(val.(types.String))))
+	})
+}
+
+// doublePredicate binds a double predicate as a CEL unary function
+func doublePredicate(fn func(float64) bool) cel.OverloadOpt {
+	return cel.UnaryBinding(func(val ref.Val) ref.Val {
+		return types.Bool(fn(float64(val.(types.Double))))
+	})
+}
+
+// between reports whether n is within the int bounds, inclusive
+func between(n int, lo, hi ref.Val) ref.Val {
+	return types.Bool(int64(lo.(types.Int)) <= int64(n) && int64(n) <= int64(hi.(types.Int)))
+}
+
+func helpersLibrary() cel.EnvOption {
+	elem := cel.TypeParamType("T")
+
+	return cel.Lib(envLib{opts: []cel.EnvOption{
+		cel.Function("isEmail",
+			cel.MemberOverload("string_is_email", []*cel.Type{cel.StringType}, cel.BoolType, stringPredicate(isEmail))),
+		cel.Function("isURL",
+			cel.MemberOverload("string_is_url", []*cel.Type{cel.StringType}, cel.BoolType, stringPredicate(isURL))),
+		cel.Function("isUUID",
+			cel.MemberOverload("string_is_uuid", []*cel.Type{cel.StringType}, cel.BoolType, stringPredicate(uuidPattern.MatchString))),
+		cel.Function("isNaN",
+			cel.MemberOverload("double_is_nan", []*cel.Type{cel.DoubleType}, cel.BoolType, doublePredicate(math.IsNaN))),
+		cel.Function("isInf",
+			cel.MemberOverload("double_is_inf", []*cel.Type{cel.DoubleType}, cel.BoolType,
+				doublePredicate(func(d float64) bool { return math.IsInf(d, 0) }))),
+		cel.Function("isFinite",
+			cel.MemberOverload("double_is_finite", []*cel.Type{cel.DoubleType}, cel.BoolType,
+				doublePredicate(func(d float64) bool { return !math.IsNaN(d) && !math.IsInf(d, 0) }))),
+		cel.Function("inSet",
+			cel.MemberOverload("in_set", []*cel.Type{elem, cel.ListType(elem)}, cel.BoolType,
+				cel.BinaryBinding(func(val, set ref.Val) ref.Val {
+					return set.(traits.Container).Contains(val)
+				}))),
+		cel.Function("lengthBetween",
+			cel.MemberOverload("string_length_between", []*cel.Type{cel.StringType, cel.IntType, cel.IntType}, cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					return between(utf8.RuneCountInString(string(args[0].(types.String))), args[1], args[2])
+				})),
+			cel.MemberOverload("list_length_between", []*cel.Type{cel.ListType(elem), cel.IntType, cel.IntType}, cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					return between(int(args[0].(traits.Lister).Size().(types.Int)), args[1], args[2])
+				})),
+		),
+	}})
+}
@@ -0,0 +1,46 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Standard helpers", func() {
+	md := ValidationMetadata{StructName: "User"}
+	user := User{Name: "Zoë", Email: "zoe@example.com", Address: Address{City: "https://example.com/a?b=c", Country: "0f8fad5b-d9cb-469f-a165-70867728950e"}}
+
+	DescribeTable("evaluates helpers",
+		func(rule string, passed bool) {
+			results, err := NewValidator(WithStdHelpers()).Validate(user, []RuleEntry{{Rule: rule, Enabled: true}}, md)
+			Expect(err).To(BeNil())
+			Expect(results[0].Error).To(BeNil())
+			Expect(results[0].Passed).To(Equal(passed))
+		},
+		Entry("email", "Email.isEmail()", true),
+		Entry("email with display name", "'Zoe <zoe@example.com>'.isEmail()", false),
+		Entry("not an email", "Name.isEmail()", false),
+		Entry("url", "Address.City.isURL()", true),
+		Entry("relative url", "'/a/b'.isURL()", false),
+		Entry("uuid", "Address.Country.isUUID()", true),
+		Entry("not a uuid", "Name.isUUID()", false),
+		Entry("matches", "Email.matches('^[a-z]+@')", true),
+		Entry("in set", "Name.inSet(['Zoë', 'Ann'])", true),
+		Entry("not in set", "Age.inSet([1, 2])", false),
+		Entry("string length in characters", "Name.lengthBetween(3, 3)", true),
+		Entry("string too short", "Name.lengthBetween(4, 10)", false),
+		Entry("list length", "[1, 2, 3].lengthBetween(1, 3)", true),
+	)
+
+	It("requires the option", func() {
+		results, _ := NewValidator(WithPartialEval()).Validate(user, []RuleEntry{{Rule: "Email.isEmail()", Enabled: true}}, md)
+		Expect(results[0].Error).To(HaveOccurred())
+	})
+
+	It("is enabled by the std_helpers extension", func() {
+		opts, err := ValidatorConfig{Extensions: []string{"std_helpers"}}.Options()
+		Expect(err).To(BeNil())
+		results, err := NewValidator(opts...).Validate(user, []RuleEntry{{Rule: "Email.isEmail()", Enabled: true}}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeTrue())
+	})
+})
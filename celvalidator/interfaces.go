@@ -0,0 +1,47 @@
+package celvalidator
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+var (
+	interfaceTypesMu sync.RWMutex
+	interfaceTypes   = map[string]reflect.Type{}
+)
+
+// RegisterInterface lets rule sets key rules by an interface name. Objects
+// implementing the interface get its rules in addition to their own. iface must
+// be a nil pointer to the interface, e.g. (*Auditable)(nil).
+func RegisterInterface(name string, iface any) bool {
+	t := reflect.TypeOf(iface)
+	if name == "" || t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Interface {
+		return false
+	}
+
+	interfaceTypesMu.Lock()
+	defer interfaceTypesMu.Unlock()
+	interfaceTypes[name] = t.Elem()
+	return true
+}
+
+// implementedInterfaces returns the sorted names of the registered interfaces obj implements
+func implementedInterfaces(obj any) []string {
+	t := reflect.TypeOf(obj)
+	if t == nil {
+		return nil
+	}
+
+	interfaceTypesMu.RLock()
+	defer interfaceTypesMu.RUnlock()
+	var names []string
+	for name, iface := range interfaceTypes {
+		// Methods with pointer receivers count for values too, the rules see the same fields
+		if t.Implements(iface) || (t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(iface)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
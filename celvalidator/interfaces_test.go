@@ -0,0 +1,66 @@
+package celvalidator
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type auditable interface {
+	AuditOwner() string
+}
+
+type invoice struct {
+	Owner  string
+	Amount int
+}
+
+func (i *invoice) AuditOwner() string { return i.Owner }
+
+var _ = Describe("Interface rules", func() {
+	BeforeEach(func() {
+		Expect(RegisterInterface("Auditable", (*auditable)(nil))).To(BeTrue())
+	})
+
+	rules := RuleSetMap{
+		"invoice": {
+			"Default": {{Rule: "Amount > 0", Enabled: true}},
+		},
+		"Auditable": {
+			"Default": {{Rule: "Owner != ''", Enabled: true}, {Rule: "Amount > 0", Enabled: true}},
+			"Delete":  {{Rule: "Owner == 'admin'", Enabled: true}},
+		},
+	}
+
+	It("rejects values that are not interface pointers", func() {
+		Expect(RegisterInterface("Bad", invoice{})).To(BeFalse())
+		Expect(RegisterInterface("Bad", (*invoice)(nil))).To(BeFalse())
+	})
+
+	It("adds interface rules after the concrete type rules", func() {
+		for _, obj := range []any{invoice{}, &invoice{}} {
+			merged := GetRulesFor(obj, "Delete", rules)
+			Expect(merged).To(HaveLen(3))
+			Expect(merged[0].Rule).To(Equal("Amount > 0"))
+			Expect(merged[1].Rule).To(Equal("Owner != ''"))
+			Expect(merged[2].Rule).To(Equal("Owner == 'admin'"))
+		}
+		Expect(GetRulesFor(Sample{}, "Delete", rules)).To(BeEmpty())
+	})
+
+	It("validates objects with inherited rules", func() {
+		obj := invoice{Owner: "bob", Amount: 10}
+		results, err := NewValidator().Validate(obj, GetRulesFor(obj, "Delete", rules), ValidationMetadata{StructName: "invoice"})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(3))
+		Expect(results[2].Passed).To(BeFalse())
+	})
+
+	It("counts interface rules in strict lookups", func() {
+		_, err := GetRulesForStrict(invoice{}, "Update", RuleSetMap{"Auditable": rules["Auditable"]})
+		Expect(err).To(BeNil())
+		_, err = GetRulesForStrict(Sample{}, "Update", rules)
+		Expect(errors.Is(err, ErrNoRulesForType)).To(BeTrue())
+	})
+})
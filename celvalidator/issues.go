@@ -0,0 +1,80 @@
+package celvalidator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+)
+
+// IssueSeverity classifies a rule issue
+type IssueSeverity string
+
+const (
+	IssueError   IssueSeverity = "error"
+	IssueWarning IssueSeverity = "warning"
+)
+
+// RuleIssue is a problem found when compiling a rule, with its location in
+// the expression when known (1-based line, 0-based column)
+type RuleIssue struct {
+	Severity IssueSeverity
+	Message  string
+	Line     int
+	Column   int
+}
+
+func (i RuleIssue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s: %d:%d: %s", i.Severity, i.Line, i.Column, i.Message)
+}
+
+// compileIssues normalizes the issues reported by cel-go, which are all errors
+func compileIssues(iss *cel.Issues) []RuleIssue {
+	var issues []RuleIssue
+	for _, err := range iss.Errors() {
+		issue := RuleIssue{Severity: IssueError, Message: err.Message}
+		if err.Location != nil {
+			issue.Line, issue.Column = err.Location.Line(), err.Location.Column()
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// ruleWarnings reports constructs of a compiled rule that work today but are
+// likely mistakes, currently comprehension variables shadowing a declared variable
+func ruleWarnings(compiled *cel.Ast, vars map[string]any) []RuleIssue {
+	declared := make(map[string]bool, len(vars))
+	for name := range vars {
+		root, _, _ := strings.Cut(name, ".")
+		declared[root] = true
+	}
+
+	native := compiled.NativeRep()
+	var warnings []RuleIssue
+	visit := ast.NewExprVisitor(func(e ast.Expr) {
+		if e.Kind() != ast.ComprehensionKind {
+			return
+		}
+		c := e.AsComprehension()
+		for _, iterVar := range []string{c.IterVar(), c.IterVar2()} {
+			if iterVar == "" || !declared[iterVar] {
+				continue
+			}
+			issue := RuleIssue{
+				Severity: IssueWarning,
+				Message:  fmt.Sprintf("comprehension variable %s shadows the field %s", iterVar, iterVar),
+			}
+			if loc := native.SourceInfo().GetStartLocation(e.ID()); loc.Line() > 0 {
+				issue.Line, issue.Column = loc.Line(), loc.Column()
+			}
+			warnings = append(warnings, issue)
+		}
+	})
+	ast.PostOrderVisit(native.Expr(), visit)
+	return warnings
+}
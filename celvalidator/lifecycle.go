@@ -0,0 +1,45 @@
+package celvalidator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// LifecycleReport maps operations to the report of validating an object with
+// their rules
+type LifecycleReport map[string]ValidationReport
+
+// Eligible returns the sorted operations whose rules all passed, e.g. the
+// transitions a workflow engine may take with the object
+func (r LifecycleReport) Eligible() []string {
+	var eligible []string
+	for op, report := range r {
+		if report.Decide(AllMustPass()).Valid {
+			eligible = append(eligible, op)
+		}
+	}
+	sort.Strings(eligible)
+	return eligible
+}
+
+// ValidateLifecycle validates obj against the rules of each operation, e.g.
+// Create, Activate and Publish, in one call
+func (v *Validator) ValidateLifecycle(obj any, operations []string, rulesMap RuleSetMap) (LifecycleReport, error) {
+	return v.ValidateLifecycleContext(context.Background(), obj, operations, rulesMap)
+}
+
+// ValidateLifecycleContext is ValidateLifecycle with a context, returning the
+// reports of the operations validated before an error
+func (v *Validator) ValidateLifecycleContext(ctx context.Context, obj any, operations []string, rulesMap RuleSetMap) (LifecycleReport, error) {
+	report := make(LifecycleReport, len(operations))
+	for _, op := range operations {
+		metadata := NewValidationMetadata(obj, op, rulesMap)
+		results, err := v.ValidateContext(ctx, obj, GetRulesFor(obj, op, rulesMap), metadata)
+		if err != nil {
+			return report, fmt.Errorf("validating %s: %w", op, err)
+		}
+		report[op] = results
+	}
+	return report, nil
+}
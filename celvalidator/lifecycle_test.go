@@ -0,0 +1,42 @@
+package celvalidator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lifecycle validation", func() {
+	rules := RuleSetMap{
+		"Sample": {
+			"Default":  {{Rule: "Age >= 18", Enabled: true}},
+			"Activate": {{Rule: "Email != ''", Enabled: true}},
+			"Publish":  {{Rule: "Active", Enabled: true}},
+		},
+	}
+	operations := []string{"Create", "Activate", "Publish"}
+
+	It("reports each operation and the ones the object is eligible for", func() {
+		report, err := NewValidator(WithPartialEval()).ValidateLifecycle(Sample{Age: 20, Email: "a@b.c"}, operations, rules)
+		Expect(err).To(BeNil())
+		Expect(report).To(HaveLen(3))
+		Expect(report["Create"]).To(HaveLen(1))
+		Expect(report["Activate"]).To(HaveLen(2))
+		Expect(report["Activate"][1].Metadata.Operation).To(Equal("Activate"))
+		Expect(report["Publish"][1].Passed).To(BeFalse())
+		Expect(report.Eligible()).To(Equal([]string{"Activate", "Create"}))
+
+		report, err = NewValidator().ValidateLifecycle(Sample{Age: 10}, operations, rules)
+		Expect(err).To(BeNil())
+		Expect(report.Eligible()).To(BeEmpty())
+	})
+
+	It("returns the reports validated before an error", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		report, err := NewValidator().ValidateLifecycleContext(ctx, Sample{Age: 20}, operations, rules)
+		Expect(err).To(MatchError(ContainSubstring("validating Create")))
+		Expect(report).To(BeEmpty())
+	})
+})
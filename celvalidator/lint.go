@@ -0,0 +1,176 @@
+package celvalidator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+)
+
+// Lint checks, named in RuleEntry.LintWaivers to waive them for a rule
+const (
+	LintMaxLength    = "max_length"
+	LintMaxOperators = "max_operators"
+	LintMaxDepth     = "max_depth"
+)
+
+// LintThresholds bounds the complexity of rule expressions, a zero threshold
+// disabling its check
+type LintThresholds struct {
+	MaxLength    int `yaml:"max_length"`
+	MaxOperators int `yaml:"max_operators"`
+	MaxDepth     int `yaml:"max_depth"`
+}
+
+// DefaultLintThresholds returns thresholds past which a rule is better split
+// into a Then chain
+func DefaultLintThresholds() LintThresholds {
+	return LintThresholds{MaxLength: 200, MaxOperators: 12, MaxDepth: 4}
+}
+
+// RuleComplexity measures a rule expression. Operators counts operator calls,
+// e.g. && and ==, and Depth the nesting of function calls, macros and literals.
+type RuleComplexity struct {
+	Length    int
+	Operators int
+	Depth     int
+}
+
+// MeasureRule measures the complexity of a rule expression as written
+func MeasureRule(rule string) (RuleComplexity, error) {
+	// Without macros, x.all(v, p) stays a call instead of a comprehension
+	env, err := cel.NewEnv(cel.ClearMacros())
+	if err != nil {
+		return RuleComplexity{}, err
+	}
+	parsed, iss := env.Parse(rule)
+	if iss != nil && iss.Err() != nil {
+		return RuleComplexity{}, iss.Err()
+	}
+
+	c := RuleComplexity{Length: len(rule)}
+	c.Depth = measureExpr(parsed.NativeRep().Expr(), &c.Operators)
+	return c, nil
+}
+
+// measureExpr returns the nesting depth of e, counting operators into ops
+func measureExpr(e ast.Expr, ops *int) int {
+	depth := 0
+	nested := func(children ...ast.Expr) {
+		for _, child := range children {
+			depth = max(depth, measureExpr(child, ops))
+		}
+	}
+
+	switch e.Kind() {
+	case ast.SelectKind:
+		nested(e.AsSelect().Operand())
+		return depth
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.IsMemberFunction() {
+			nested(call.Target())
+		}
+		nested(call.Args()...)
+		if _, ok := operators.FindReverse(call.FunctionName()); ok {
+			*ops++
+			return depth
+		}
+	case ast.ListKind:
+		nested(e.AsList().Elements()...)
+	case ast.MapKind:
+		for _, entry := range e.AsMap().Entries() {
+			nested(entry.AsMapEntry().Key(), entry.AsMapEntry().Value())
+		}
+	case ast.StructKind:
+		for _, field := range e.AsStruct().Fields() {
+			nested(field.AsStructField().Value())
+		}
+	default:
+		return 0
+	}
+	return depth + 1
+}
+
+// LintFinding is a rule exceeding a lint threshold
+type LintFinding struct {
+	StructName string
+	Operation  string
+	ID         string
+	Rule       string
+	Check      string
+	Value      int
+	Limit      int
+	Source     RuleSource
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s.%s %q: %s %d exceeds %d, consider splitting the rule into a then chain",
+		f.StructName, f.Operation, f.Rule, f.Check, f.Value, f.Limit)
+}
+
+// CheckRuleSet lints every rule of a rule set, including Then children, against
+// the thresholds. Rules failing to parse are left to compilation to report.
+func CheckRuleSet(rules RuleSetMap, thresholds LintThresholds) []LintFinding {
+	var findings []LintFinding
+	for _, structName := range sortedRuleSetKeys(rules) {
+		operations := rules[structName]
+		ops := make([]string, 0, len(operations))
+		for op := range operations {
+			ops = append(ops, op)
+		}
+		sort.Strings(ops)
+		for _, op := range ops {
+			findings = lintEntries(findings, structName, op, operations[op], thresholds)
+		}
+	}
+	return findings
+}
+
+func lintEntries(findings []LintFinding, structName, op string, entries []RuleEntry, thresholds LintThresholds) []LintFinding {
+	for _, entry := range entries {
+		findings = lintEntries(findings, structName, op, entry.Then, thresholds)
+		if entry.Rule == "" {
+			continue
+		}
+		c, err := MeasureRule(entry.Rule)
+		if err != nil {
+			continue
+		}
+		for _, check := range []struct {
+			name         string
+			value, limit int
+		}{
+			{LintMaxLength, c.Length, thresholds.MaxLength},
+			{LintMaxOperators, c.Operators, thresholds.MaxOperators},
+			{LintMaxDepth, c.Depth, thresholds.MaxDepth},
+		} {
+			if check.limit <= 0 || check.value <= check.limit || slices.Contains(entry.LintWaivers, check.name) {
+				continue
+			}
+			findings = append(findings, LintFinding{
+				StructName: structName,
+				Operation:  op,
+				ID:         entry.ID,
+				Rule:       entry.Rule,
+				Check:      check.name,
+				Value:      check.value,
+				Limit:      check.limit,
+				Source:     entry.Source,
+			})
+		}
+	}
+	return findings
+}
+
+func sortedRuleSetKeys(rules RuleSetMap) []string {
+	keys := make([]string, 0, len(rules))
+	for k := range rules {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,44 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rule linting", func() {
+	It("measures expressions as written", func() {
+		c, err := MeasureRule("Age > 18 && Email.endsWith('@example.com')")
+		Expect(err).To(BeNil())
+		Expect(c).To(Equal(RuleComplexity{Length: 42, Operators: 2, Depth: 1}))
+
+		c, err = MeasureRule("size(Tags.filter(t, t in ['a', 'b'])) > 1")
+		Expect(err).To(BeNil())
+		Expect(c.Operators).To(Equal(2))
+		Expect(c.Depth).To(Equal(3))
+
+		_, err = MeasureRule("Age >")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("reports rules and Then children over the thresholds", func() {
+		long := "Email != '' && Email != 'a' && Email != 'b' && Email != 'c'"
+		rules := RuleSetMap{
+			"Sample": {
+				"Default": {
+					{Rule: "Age > 1", Enabled: true, Then: []RuleEntry{{ID: "long", Rule: long, Enabled: true}}},
+					{Rule: long, Enabled: true, LintWaivers: []string{LintMaxOperators, LintMaxLength}},
+					{Rule: "Age >", Enabled: true},
+				},
+			},
+		}
+
+		findings := CheckRuleSet(rules, LintThresholds{MaxLength: 40, MaxOperators: 5})
+		Expect(findings).To(HaveLen(2))
+		Expect(findings[0].ID).To(Equal("long"))
+		Expect(findings[0].Check).To(Equal(LintMaxLength))
+		Expect(findings[1].Check).To(Equal(LintMaxOperators))
+		Expect(findings[1].Value).To(Equal(7))
+
+		Expect(CheckRuleSet(rules, LintThresholds{})).To(BeEmpty())
+	})
+})
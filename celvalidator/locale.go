@@ -0,0 +1,37 @@
+package celvalidator
+
+import (
+	"context"
+	"strings"
+)
+
+type localeKey struct{}
+
+// WithLocale selects the locale of failure messages for validations run with ctx,
+// rules without a translation for it keep their message
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFrom returns the locale attached with WithLocale
+func LocaleFrom(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// failureMessage returns the failure message translated to locale, falling
+// back from a regional locale such as fr-CA to its language
+func (r RuleEntry) failureMessage(locale string) string {
+	if locale == "" || len(r.Messages) == 0 {
+		return r.FailureMessage
+	}
+	if msg, ok := r.Messages[locale]; ok {
+		return msg
+	}
+	if lang, _, found := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-"); found {
+		if msg, ok := r.Messages[lang]; ok {
+			return msg
+		}
+	}
+	return r.FailureMessage
+}
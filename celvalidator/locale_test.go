@@ -0,0 +1,41 @@
+package celvalidator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Message bundles", func() {
+	ruleFile := []byte(`Sample:
+  Create:
+    - rule: "Age > 40"
+      enabled: true
+      message: "too young"
+      messages:
+        fr: "trop jeune"
+        de: "zu jung"
+`)
+	obj := Sample{Age: 30}
+	metadata := ValidationMetadata{StructName: "Sample", Operation: "Create"}
+
+	validate := func(ctx context.Context) string {
+		file, err := ParseRuleFileYAML(ruleFile)
+		Expect(err).To(BeNil())
+		results, err := NewValidator().ValidateContext(ctx, obj, GetRulesFor(obj, "Create", file.Rules), metadata)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(1))
+		return results[0].Message
+	}
+
+	It("selects the message of the requested locale", func() {
+		Expect(validate(WithLocale(context.Background(), "fr"))).To(Equal("trop jeune"))
+		Expect(validate(WithLocale(context.Background(), "de-AT"))).To(Equal("zu jung"))
+	})
+
+	It("falls back to the default message", func() {
+		Expect(validate(context.Background())).To(Equal("too young"))
+		Expect(validate(WithLocale(context.Background(), "es"))).To(Equal("too young"))
+	})
+})
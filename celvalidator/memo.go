@@ -0,0 +1,48 @@
+package celvalidator
+
+import (
+	"sync"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// memo caches the values computed during a validation, such as resolver
+// results. Each key is computed once, concurrent requests for a key being
+// computed waiting for its value.
+type memo struct {
+	mu    sync.Mutex
+	calls map[string]*memoCall
+}
+
+type memoCall struct {
+	done chan struct{}
+	val  ref.Val
+	// computed is false when the computation panicked
+	computed bool
+}
+
+func newMemo() *memo {
+	return &memo{calls: map[string]*memoCall{}}
+}
+
+// do returns the value of key, computing it with fn on first request
+func (m *memo) do(key string, fn func() ref.Val) ref.Val {
+	m.mu.Lock()
+	if c, ok := m.calls[key]; ok {
+		m.mu.Unlock()
+		<-c.done
+		if !c.computed {
+			return types.NewErr("computing %s panicked", key)
+		}
+		return c.val
+	}
+	c := &memoCall{done: make(chan struct{})}
+	m.calls[key] = c
+	m.mu.Unlock()
+
+	defer close(c.done)
+	c.val = fn()
+	c.computed = true
+	return c.val
+}
@@ -0,0 +1,69 @@
+package celvalidator
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Memoization", func() {
+	md := ValidationMetadata{StructName: "Sample"}
+
+	It("calls resolvers once per distinct arguments within a validation", func() {
+		var mu sync.Mutex
+		calls := map[string]int{}
+		v := NewValidator(WithResolver("score", 1, func(rc ResolverContext, args []any) (any, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[args[0].(string)]++
+			return int64(len(args[0].(string))), nil
+		}))
+		rules := []RuleEntry{
+			{Rule: "score(Email) > 0", Enabled: true},
+			{Rule: "score(Email) < 100", Enabled: true, Then: []RuleEntry{
+				{Rule: "score('x') == 1", Enabled: true},
+			}},
+			{Rule: "score('x') + score(Email) > 1", Enabled: true},
+		}
+
+		results, err := v.Validate(Sample{Email: "a@b.c"}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(4))
+		Expect(calls).To(Equal(map[string]int{"a@b.c": 1, "x": 1}))
+
+		_, err = v.Validate(Sample{Email: "a@b.c"}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(calls).To(Equal(map[string]int{"a@b.c": 2, "x": 2}))
+	})
+
+	It("shares calls in flight across concurrent rules", func() {
+		var calls atomic.Int32
+		slow := WithResolver("slow", 1, func(rc ResolverContext, args []any) (any, error) {
+			calls.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			return true, nil
+		})
+		facts := WithVariableResolver("ext", VariableResolverFunc(func(rc ResolverContext, name string) (any, error) {
+			calls.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			return int64(1), nil
+		}))
+		rules := []RuleEntry{
+			{Rule: "slow(Age) && ext.limit == 1", Enabled: true},
+			{Rule: "slow(Age) && ext.limit > 0", Enabled: true},
+			{Rule: "slow(Age) && ext.limit < 2", Enabled: true},
+			{Rule: "slow(Age) && ext.limit != 0", Enabled: true},
+		}
+
+		results, err := NewValidator(slow, facts, WithConcurrency(4)).Validate(Sample{}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(4))
+		for _, r := range results {
+			Expect(r.Passed).To(BeTrue())
+		}
+		Expect(calls.Load()).To(Equal(int32(2)))
+	})
+})
@@ -0,0 +1,82 @@
+package celvalidator
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// messagePlaceholder matches the {Field} placeholders of messages, dotted for
+// the fields of nested structs, e.g. {Address.City}
+var messagePlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*)\}`)
+
+// renderMessage replaces the {Field} placeholders of msg with the values of
+// the validated fields, e.g. "Age must be >= 18, got {Age}". Placeholders not
+// naming a field are left as written.
+func (e *evaluation) renderMessage(msg string) string {
+	if !strings.Contains(msg, "{") {
+		return msg
+	}
+	return messagePlaceholder.ReplaceAllStringFunc(msg, func(placeholder string) string {
+		val, ok := fieldValue(e.fields, placeholder[1:len(placeholder)-1])
+		if !ok {
+			return placeholder
+		}
+		return formatMessageValue(val)
+	})
+}
+
+// expressionMessage sets the message of a failed rule on result from its
+// MessageExpression. An expression failing to evaluate to a string is reported
+// as a warning issue, keeping the failure message.
+func (e *evaluation) expressionMessage(entry RuleEntry, result *ValidationResult) {
+	if entry.MessageExpression == "" {
+		return
+	}
+
+	value, err := e.evalExpression(entry, entry.MessageExpression)
+	if err == nil {
+		msg, ok := value.(string)
+		if ok {
+			result.Message = msg
+			return
+		}
+		err = fmt.Errorf("evaluated to %T, not a string", value)
+	}
+	result.Issues = append(result.Issues, RuleIssue{
+		Severity: IssueWarning,
+		Message:  fmt.Sprintf("message_expression: %v", err),
+	})
+}
+
+// fieldValue looks name up in fields, either flattened or nested in maps
+func fieldValue(fields map[string]any, name string) (any, bool) {
+	if val, ok := fields[name]; ok {
+		return val, true
+	}
+	var cur any = fields
+	for _, step := range strings.Split(name, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[step]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// formatMessageValue formats a field value for a message, nil values and
+// pointers as null and set pointers as the value they point to
+func formatMessageValue(val any) string {
+	v := reflect.ValueOf(val)
+	switch {
+	case !v.IsValid(), v.Kind() == reflect.Ptr && v.IsNil():
+		return "null"
+	case v.Kind() == reflect.Ptr:
+		return fmt.Sprint(v.Elem().Interface())
+	}
+	return fmt.Sprint(val)
+}
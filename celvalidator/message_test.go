@@ -0,0 +1,56 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Message templates", func() {
+	type Address struct {
+		City string
+	}
+	type Person struct {
+		Age     int
+		Nick    *string
+		Address Address
+	}
+	md := ValidationMetadata{StructName: "Person", Operation: "Create"}
+	rules := []RuleEntry{
+		{Rule: "Age >= 18", Enabled: true, FailureMessage: "Age must be >= 18, got {Age}"},
+		{Rule: "Address.City == 'Paris'", Enabled: true, FailureMessage: "{Address.City} is not Paris, nick {Nick}, {Unknown} and {not a field}"},
+	}
+
+	DescribeTable("renders field values in failure messages",
+		func(v *Validator) {
+			results, err := v.Validate(Person{Age: 12, Address: Address{City: "Lyon"}}, rules, md)
+			Expect(err).To(BeNil())
+			Expect(results[0].Message).To(Equal("Age must be >= 18, got 12"))
+			Expect(results[1].Message).To(Equal("Lyon is not Paris, nick null, {Unknown} and {not a field}"))
+		},
+		Entry("with flattened fields", NewValidator(WithPartialEval())),
+		Entry("with nested fields", NewValidator(WithPartialEval(), WithNestedFields())),
+	)
+
+	It("renders pointers and success messages", func() {
+		nick := "bob"
+		v := NewValidator(WithSuccessMessages())
+		results, err := v.Validate(Person{Age: 20, Nick: &nick}, []RuleEntry{{Rule: "Age >= 18", Enabled: true, SuccessMessage: "{Nick} is {Age}"}}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Message).To(Equal("bob is 20"))
+	})
+
+	It("computes failure messages with message expressions", func() {
+		v := NewValidator(WithPartialEval())
+		results, err := v.Validate(Person{Age: 12}, []RuleEntry{
+			{Rule: "Age >= 18", Enabled: true, FailureMessage: "too young", MessageExpression: "'short by ' + string(18 - Age) + ' years'"},
+			{Rule: "Age >= 18", ID: "int", Enabled: true, FailureMessage: "too young", MessageExpression: "18 - Age"},
+			{Rule: "Age >= 18", ID: "unknown", Enabled: true, FailureMessage: "too young", MessageExpression: "Missing"},
+		}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Message).To(Equal("short by 6 years"))
+		Expect(results[1].Message).To(Equal("too young"))
+		Expect(results[1].Issues).To(ContainElement(HaveField("Message", "message_expression: evaluated to int64, not a string")))
+		Expect(results[2].Message).To(Equal("too young"))
+		Expect(results[2].Issues).To(ContainElement(HaveField("Message", ContainSubstring("message_expression: "))))
+	})
+})
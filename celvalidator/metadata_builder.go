@@ -0,0 +1,85 @@
+package celvalidator
+
+// MetadataBuilder constructs ValidationMetadata for embedding applications that
+// thread their own context (chain roots, annotations) through nested validations
+type MetadataBuilder struct {
+	metadata ValidationMetadata
+}
+
+// NewMetadataBuilder starts a builder for a struct and operation
+func NewMetadataBuilder(structName, operation string) *MetadataBuilder {
+	return &MetadataBuilder{metadata: ValidationMetadata{
+		StructName: structName,
+		Operation:  operation,
+		RuleIndex:  -1,
+	}}
+}
+
+// MetadataBuilderFrom starts a builder from existing metadata
+func MetadataBuilderFrom(metadata ValidationMetadata) *MetadataBuilder {
+	b := &MetadataBuilder{metadata: metadata}
+	b.metadata.Annotations = copyAnnotations(metadata.Annotations)
+	return b
+}
+
+// WithChainRoot sets the root of the chain path, e.g. "import-job-42"
+func (b *MetadataBuilder) WithChainRoot(root string) *MetadataBuilder {
+	b.metadata.ChainPath = root
+	return b
+}
+
+// WithObjectID sets the business identifier of the validated object
+func (b *MetadataBuilder) WithObjectID(id string) *MetadataBuilder {
+	b.metadata.ObjectID = id
+	return b
+}
+
+// WithParentRule sets the rule the metadata is chained under
+func (b *MetadataBuilder) WithParentRule(rule string) *MetadataBuilder {
+	b.metadata.ParentRule = rule
+	return b
+}
+
+// WithAnnotation sets a single annotation
+func (b *MetadataBuilder) WithAnnotation(key, value string) *MetadataBuilder {
+	if b.metadata.Annotations == nil {
+		b.metadata.Annotations = map[string]string{}
+	}
+	b.metadata.Annotations[key] = value
+	return b
+}
+
+// WithAnnotations merges annotations, overriding existing keys
+func (b *MetadataBuilder) WithAnnotations(annotations map[string]string) *MetadataBuilder {
+	for k, v := range annotations {
+		b.WithAnnotation(k, v)
+	}
+	return b
+}
+
+// Child derives a builder for a nested validation, extending the chain path
+// with step and inheriting a copy of the annotations
+func (b *MetadataBuilder) Child(step string) *MetadataBuilder {
+	child := MetadataBuilderFrom(b.metadata)
+	child.metadata.ChainPath = extendChainPath(b.metadata.ChainPath, step)
+	child.metadata.RuleIndex = -1
+	return child
+}
+
+// Build returns the metadata, safe to use while the builder keeps changing
+func (b *MetadataBuilder) Build() ValidationMetadata {
+	metadata := b.metadata
+	metadata.Annotations = copyAnnotations(b.metadata.Annotations)
+	return metadata
+}
+
+func copyAnnotations(annotations map[string]string) map[string]string {
+	if annotations == nil {
+		return nil
+	}
+	cp := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		cp[k] = v
+	}
+	return cp
+}
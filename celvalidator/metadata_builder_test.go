@@ -0,0 +1,37 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MetadataBuilder", func() {
+	It("derives children with merged annotations", func() {
+		root := NewMetadataBuilder("User", "Create").
+			WithChainRoot("import-job-42").
+			WithAnnotation("tenant", "acme")
+
+		child := root.Child("row-7").WithAnnotations(map[string]string{"row": "7", "tenant": "acme-eu"})
+
+		Expect(root.Build().ChainPath).To(Equal("import-job-42"))
+		Expect(root.Build().Annotations).To(Equal(map[string]string{"tenant": "acme"}))
+
+		metadata := child.Build()
+		Expect(metadata.ChainPath).To(Equal("import-job-42 > row-7"))
+		Expect(metadata.RuleIndex).To(Equal(-1))
+		Expect(metadata.Annotations).To(Equal(map[string]string{"tenant": "acme-eu", "row": "7"}))
+	})
+
+	It("threads the chain root and annotations into results", func() {
+		obj := Sample{Age: 21}
+		rules := []RuleEntry{{Rule: "Age > 18", Enabled: true, Then: []RuleEntry{{Rule: "Age < 99", Enabled: true}}}}
+		metadata := NewMetadataBuilder("Sample", "Create").WithChainRoot("job").WithAnnotation("k", "v").Build()
+
+		results, err := NewValidator().Validate(obj, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[1].Metadata.ChainPath).To(Equal("job > then"))
+		Expect(results[1].Metadata.ParentRule).To(Equal("Age > 18"))
+		Expect(results[1].Metadata.Annotations).To(HaveKeyWithValue("k", "v"))
+	})
+})
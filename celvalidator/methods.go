@@ -0,0 +1,112 @@
+package celvalidator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// WithMethods exposes the exported zero-argument methods of the native types
+// declared by WithNativeTypes as member functions, e.g. user.FullName() or
+// user.Address.IsDomestic(). Methods must return a bool, string, integer or
+// float, optionally followed by an error failing the rule.
+func WithMethods() ValidatorOption {
+	return func(v *Validator) {
+		v.methods = true
+	}
+}
+
+var errorType = reflect.TypeFor[error]()
+
+// methodFunctions declares the methods of typ and of the struct types of its
+// fields, visited holding the types already declared
+func methodFunctions(typ reflect.Type, visited map[reflect.Type]bool) []cel.EnvOption {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || visited[typ] || isValueStructType(typ) {
+		return nil
+	}
+	visited[typ] = true
+
+	var opts []cel.EnvOption
+	receiver := cel.ObjectType(nativeTypeName(typ))
+	ptr := reflect.PointerTo(typ)
+	for i := range ptr.NumMethod() {
+		method := ptr.Method(i)
+		result, ok := methodResultType(method.Type)
+		if !ok {
+			continue
+		}
+		overload := nativeTypeName(typ) + "_" + method.Name
+		opts = append(opts, cel.Function(method.Name,
+			cel.MemberOverload(overload, []*cel.Type{receiver}, result, cel.UnaryBinding(callMethod(method.Name)))))
+	}
+	for i := range typ.NumField() {
+		if field := typ.Field(i); field.IsExported() {
+			opts = append(opts, methodFunctions(field.Type, visited)...)
+		}
+	}
+	return opts
+}
+
+// methodResultType returns the CEL type of the result of a method taking no
+// argument, false when it cannot be exposed
+func methodResultType(fn reflect.Type) (*cel.Type, bool) {
+	// The receiver is the first input of methods of a type
+	if fn.NumIn() != 1 || fn.NumOut() == 0 || fn.NumOut() > 2 || fn.NumOut() == 2 && fn.Out(1) != errorType {
+		return nil, false
+	}
+	switch fn.Out(0).Kind() {
+	case reflect.Bool:
+		return cel.BoolType, true
+	case reflect.String:
+		return cel.StringType, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cel.IntType, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cel.UintType, true
+	case reflect.Float32, reflect.Float64:
+		return cel.DoubleType, true
+	}
+	return nil, false
+}
+
+// callMethod calls the method name on the native object of a member call
+func callMethod(name string) func(ref.Val) ref.Val {
+	return func(arg ref.Val) ref.Val {
+		val := reflect.ValueOf(arg.Value())
+		if val.Kind() == reflect.Ptr && val.IsNil() {
+			return types.NewErr("calling %s on null", name)
+		}
+		if val.Kind() != reflect.Ptr {
+			// Pointer receivers need an addressable copy
+			ptr := reflect.New(val.Type())
+			ptr.Elem().Set(val)
+			val = ptr
+		}
+		out := val.MethodByName(name).Call(nil)
+		if len(out) == 2 && !out[1].IsNil() {
+			return types.WrapErr(fmt.Errorf("%s: %w", name, out[1].Interface().(error)))
+		}
+		switch result := out[0]; result.Kind() {
+		case reflect.Bool:
+			return types.Bool(result.Bool())
+		case reflect.String:
+			return types.String(result.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return types.Int(result.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return types.Uint(result.Uint())
+		default:
+			return types.Double(result.Float())
+		}
+	}
+}
+
+// errMethodsWithoutNativeTypes is returned when methods are enabled without native types
+var errMethodsWithoutNativeTypes = errors.New("methods require native types")
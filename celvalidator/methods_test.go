@@ -0,0 +1,79 @@
+package celvalidator
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type methodAddress struct {
+	Country string
+}
+
+func (a methodAddress) IsDomestic() bool {
+	return a.Country == "CA"
+}
+
+type methodUser struct {
+	First   string
+	Last    string
+	Age     int
+	Address *methodAddress
+}
+
+func (u methodUser) FullName() string {
+	return u.First + " " + u.Last
+}
+
+func (u *methodUser) IsAdult() bool {
+	return u.Age >= 18
+}
+
+func (u methodUser) Initials() (string, error) {
+	if u.First == "" || u.Last == "" {
+		return "", errors.New("name incomplete")
+	}
+	return u.First[:1] + u.Last[:1], nil
+}
+
+// Greet takes an argument and is not exposed
+func (u methodUser) Greet(name string) string {
+	return "hi " + name
+}
+
+var _ = Describe("Methods", func() {
+	md := ValidationMetadata{StructName: "methodUser", Operation: "Create"}
+
+	It("calls zero-argument methods of native types", func() {
+		v := NewValidator(WithPartialEval(), WithNativeTypes("user"), WithMethods())
+		rules := []RuleEntry{
+			{Rule: "user.FullName() == 'Ann Lee'", Enabled: true},
+			{Rule: "user.IsAdult() && Age == 30", Enabled: true},
+			{Rule: "user.Address.IsDomestic()", Enabled: true},
+			{Rule: "user.Initials() == 'AL'", Enabled: true},
+		}
+		results, err := v.Validate(&methodUser{First: "Ann", Last: "Lee", Age: 30, Address: &methodAddress{Country: "CA"}}, rules, md)
+		Expect(err).To(BeNil())
+		for _, res := range results {
+			Expect(res.Error).To(BeNil())
+			Expect(res.Passed).To(BeTrue(), res.Rule)
+		}
+	})
+
+	It("reports method errors and unexposed methods", func() {
+		v := NewValidator(WithPartialEval(), WithNativeTypes("user"), WithMethods())
+		results, err := v.Validate(methodUser{First: "Ann"}, []RuleEntry{
+			{Rule: "user.Initials() == 'AL'", Enabled: true},
+			{Rule: "user.Greet('bob') == 'hi bob'", Enabled: true},
+		}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Error).To(MatchError(ContainSubstring("Initials: name incomplete")))
+		Expect(results[1].Error).To(MatchError(ContainSubstring("undeclared reference to 'Greet'")))
+	})
+
+	It("requires native types", func() {
+		_, err := NewValidator(WithMethods()).Validate(methodUser{}, []RuleEntry{{Rule: "Age > 1", Enabled: true}}, md)
+		Expect(err).To(MatchError(errMethodsWithoutNativeTypes))
+	})
+})
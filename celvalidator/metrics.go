@@ -0,0 +1,17 @@
+package celvalidator
+
+import "time"
+
+// Metrics receives every validation with its results, e.g. to export counters
+// and timings to a monitoring system. It must be safe for concurrent use, see
+// the adapters/statsd module for a StatsD client.
+type Metrics interface {
+	RecordValidation(metadata ValidationMetadata, results []ValidationResult, elapsed time.Duration)
+}
+
+// WithMetrics records validations in m
+func WithMetrics(m Metrics) ValidatorOption {
+	return func(v *Validator) {
+		v.metrics = m
+	}
+}
@@ -0,0 +1,195 @@
+package celvalidator
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// Money is an amount in minor units (e.g. cents) of a currency. Money fields,
+// and fields of types registered with RegisterMoneyType, are exposed to rules
+// as a map with Amount and Currency keys.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// moneyMapping holds the index paths of the amount and currency fields,
+// which may be promoted from embedded structs
+type moneyMapping struct {
+	amount   []int
+	currency []int
+}
+
+var (
+	moneyTypesMu sync.RWMutex
+	moneyTypes   = map[reflect.Type]moneyMapping{
+		reflect.TypeOf(Money{}): {amount: []int{0}, currency: []int{1}},
+	}
+)
+
+// RegisterMoneyType exposes fields of example's struct type as money, reading
+// the amount in minor units from the named integer field and the currency from
+// the named string field. It returns false when the fields do not qualify.
+func RegisterMoneyType(example any, amountField, currencyField string) bool {
+	t := indirect(reflect.ValueOf(example)).Type()
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	amount, ok := t.FieldByName(amountField)
+	if !ok || !isIntegerKind(amount.Type.Kind()) {
+		return false
+	}
+	currency, ok := t.FieldByName(currencyField)
+	if !ok || currency.Type.Kind() != reflect.String {
+		return false
+	}
+
+	moneyTypesMu.Lock()
+	defer moneyTypesMu.Unlock()
+	moneyTypes[t] = moneyMapping{amount: amount.Index, currency: currency.Index}
+	return true
+}
+
+// isIntegerKind reports whether k holds an amount in minor units without
+// rounding, unlike floats
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isMoneyType(t reflect.Type) bool {
+	moneyTypesMu.RLock()
+	defer moneyTypesMu.RUnlock()
+	_, ok := moneyTypes[t]
+	return ok
+}
+
+// moneyValue converts val to its money map when its type is a money type
+func moneyValue(val reflect.Value) (map[string]any, bool) {
+	if val.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	moneyTypesMu.RLock()
+	mapping, ok := moneyTypes[val.Type()]
+	moneyTypesMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	// Promoted fields are unreachable through nil embedded pointers
+	amount, err := val.FieldByIndexErr(mapping.amount)
+	if err != nil {
+		return nil, false
+	}
+	currency, err := val.FieldByIndexErr(mapping.currency)
+	if err != nil {
+		return nil, false
+	}
+	return map[string]any{
+		"Amount":   amount.Convert(reflect.TypeOf(int64(0))).Int(),
+		"Currency": currency.String(),
+	}, true
+}
+
+// WithMoney registers the money functions:
+// money(amount, currency), sameCurrency(a, b) and the comparisons
+// eqMoney, gtMoney, gteMoney, ltMoney and lteMoney, which error when the
+// currencies differ
+func WithMoney() ValidatorOption {
+	return func(v *Validator) {
+		v.money = true
+	}
+}
+
+func moneyLibrary() cel.EnvOption {
+	moneyType := cel.MapType(cel.StringType, cel.DynType)
+	pair := []*cel.Type{moneyType, moneyType}
+
+	compare := func(name string, test func(c int) bool) cel.EnvOption {
+		return cel.Function(name,
+			cel.Overload(name+"_money", pair, cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					a, b, errVal := moneyPair(lhs, rhs)
+					if errVal != nil {
+						return errVal
+					}
+					if a.currency != b.currency {
+						return types.NewErr("%s: currency mismatch %s != %s", name, a.currency, b.currency)
+					}
+					switch {
+					case a.amount < b.amount:
+						return types.Bool(test(-1))
+					case a.amount > b.amount:
+						return types.Bool(test(1))
+					}
+					return types.Bool(test(0))
+				})))
+	}
+
+	return cel.Lib(envLib{opts: []cel.EnvOption{
+		cel.Function("money",
+			cel.Overload("money_int_string", []*cel.Type{cel.IntType, cel.StringType}, moneyType,
+				cel.BinaryBinding(func(amount, currency ref.Val) ref.Val {
+					return types.DefaultTypeAdapter.NativeToValue(map[string]any{
+						"Amount":   int64(amount.(types.Int)),
+						"Currency": string(currency.(types.String)),
+					})
+				})),
+		),
+		cel.Function("sameCurrency",
+			cel.Overload("sameCurrency_money", pair, cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					a, b, errVal := moneyPair(lhs, rhs)
+					if errVal != nil {
+						return errVal
+					}
+					return types.Bool(a.currency == b.currency)
+				})),
+		),
+		compare("eqMoney", func(c int) bool { return c == 0 }),
+		compare("gtMoney", func(c int) bool { return c > 0 }),
+		compare("gteMoney", func(c int) bool { return c >= 0 }),
+		compare("ltMoney", func(c int) bool { return c < 0 }),
+		compare("lteMoney", func(c int) bool { return c <= 0 }),
+	}})
+}
+
+type moneyParts struct {
+	amount   int64
+	currency string
+}
+
+func moneyPair(lhs, rhs ref.Val) (moneyParts, moneyParts, ref.Val) {
+	a, errVal := moneyFromVal(lhs)
+	if errVal != nil {
+		return a, moneyParts{}, errVal
+	}
+	b, errVal := moneyFromVal(rhs)
+	return a, b, errVal
+}
+
+func moneyFromVal(val ref.Val) (moneyParts, ref.Val) {
+	m, ok := val.(traits.Mapper)
+	if !ok {
+		return moneyParts{}, types.MaybeNoSuchOverloadErr(val)
+	}
+	amount, ok := m.Get(types.String("Amount")).(types.Int)
+	if !ok {
+		return moneyParts{}, types.NewErr("money value has no int Amount")
+	}
+	currency, ok := m.Get(types.String("Currency")).(types.String)
+	if !ok {
+		return moneyParts{}, types.NewErr("money value has no string Currency")
+	}
+	return moneyParts{amount: int64(amount), currency: string(currency)}, nil
+}
@@ -0,0 +1,83 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Money", func() {
+	type Price struct {
+		Cents int
+		Ccy   string
+	}
+	type Payment struct {
+		Amount  Money
+		Limit   Money
+		Fee     Price
+		Balance Money
+	}
+
+	BeforeEach(func() {
+		Expect(RegisterMoneyType(Price{}, "Cents", "Ccy")).To(BeTrue())
+	})
+
+	payment := Payment{
+		Amount:  Money{Amount: 1500, Currency: "USD"},
+		Limit:   Money{Amount: 2000, Currency: "USD"},
+		Fee:     Price{Cents: 50, Ccy: "USD"},
+		Balance: Money{Amount: 9000, Currency: "EUR"},
+	}
+
+	It("compares money values of the same currency", func() {
+		v := NewValidator(WithMoney())
+		rules := []RuleEntry{
+			{Rule: "sameCurrency(Amount, Limit) && lteMoney(Amount, Limit)", Enabled: true},
+			{Rule: "gteMoney(Amount, money(1000, 'USD'))", Enabled: true},
+			{Rule: "ltMoney(Fee, Amount) && Fee.Amount == 50", Enabled: true},
+			{Rule: "!sameCurrency(Amount, Balance) && Amount.Currency == 'USD'", Enabled: true},
+		}
+		results, err := v.Validate(payment, rules, ValidationMetadata{StructName: "Payment"})
+		Expect(err).To(BeNil())
+		for _, r := range results {
+			Expect(r.Passed).To(BeTrue(), r.Rule)
+		}
+	})
+
+	It("reads promoted amount and currency fields", func() {
+		type Base struct {
+			Units int32
+		}
+		type Charge struct {
+			Label string
+			Base
+			Code string
+		}
+		type Invoice struct {
+			Total Charge
+		}
+		Expect(RegisterMoneyType(Charge{}, "Units", "Code")).To(BeTrue())
+
+		v := NewValidator(WithMoney())
+		invoice := Invoice{Total: Charge{Label: "total", Base: Base{Units: 700}, Code: "USD"}}
+		results, err := v.Validate(invoice, []RuleEntry{{Rule: "eqMoney(Total, money(700, 'USD'))", Enabled: true}}, ValidationMetadata{StructName: "Invoice"})
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeTrue())
+	})
+
+	It("rejects non-integer amounts", func() {
+		type FloatPrice struct {
+			Value float64
+			Ccy   string
+		}
+		Expect(RegisterMoneyType(FloatPrice{}, "Value", "Ccy")).To(BeFalse())
+		Expect(RegisterMoneyType(FloatPrice{}, "Ccy", "Ccy")).To(BeFalse())
+	})
+
+	It("errors on currency mismatch", func() {
+		v := NewValidator(WithMoney())
+		results, err := v.Validate(payment, []RuleEntry{{Rule: "gtMoney(Balance, Amount)", Enabled: true}}, ValidationMetadata{StructName: "Payment"})
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeFalse())
+		Expect(results[0].Error).To(MatchError(ContainSubstring("currency mismatch")))
+	})
+})
@@ -0,0 +1,54 @@
+package celvalidator
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldNaming selects how struct fields are named as CEL variables
+type FieldNaming int
+
+const (
+	// FieldNamesGo names variables after the Go fields, the default
+	FieldNamesGo FieldNaming = iota
+	// FieldNamesJSON names variables after the json tag of fields, e.g. so
+	// rules on API payloads use the names of the payload
+	FieldNamesJSON
+	// FieldNamesTag names variables after the cel tag of fields, falling back
+	// to their json tag
+	FieldNamesTag
+)
+
+// WithFieldNaming names the variables of struct fields with n. Fields without
+// a tag keep their Go name and fields tagged "-" are not declared.
+func WithFieldNaming(n FieldNaming) ValidatorOption {
+	return func(v *Validator) {
+		v.fieldNaming = n
+	}
+}
+
+// fieldName returns the variable name of field, false when its tag skips it
+func (n FieldNaming) fieldName(field reflect.StructField) (string, bool) {
+	if n == FieldNamesTag {
+		if name, ok := tagName(field, "cel"); name != "" || !ok {
+			return name, ok
+		}
+	}
+	if n == FieldNamesTag || n == FieldNamesJSON {
+		if name, ok := tagName(field, "json"); name != "" || !ok {
+			return name, ok
+		}
+	}
+	return field.Name, true
+}
+
+// tagName returns the name of a field in its key tag, empty without one and
+// false when the tag is "-"
+func tagName(field reflect.StructField, key string) (string, bool) {
+	tag := field.Tag.Get(key)
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, true
+}
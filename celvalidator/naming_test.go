@@ -0,0 +1,56 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Field naming", func() {
+	type Payload struct {
+		UserName string `json:"user_name,omitempty"`
+		Age      int    `json:"age" cel:"years"`
+		Secret   string `json:"-"`
+		Internal string `cel:"-"`
+		Plain    string
+		Home     Address `json:"home"`
+		Tags     []Address
+	}
+	obj := Payload{UserName: "ann", Age: 30, Plain: "p", Home: Address{City: "Oslo"}, Tags: []Address{{City: "Rome"}}}
+
+	It("names flattened fields after their json or cel tag", func() {
+		Expect(flattenStruct(obj, FieldNamesJSON)).To(SatisfyAll(
+			HaveKeyWithValue("user_name", "ann"),
+			HaveKeyWithValue("age", 30),
+			HaveKeyWithValue("Internal", ""),
+			HaveKeyWithValue("Plain", "p"),
+			HaveKeyWithValue("home.City", "Oslo"),
+			Not(HaveKey("Secret")),
+		))
+		Expect(flattenStruct(obj, FieldNamesTag)).To(SatisfyAll(
+			HaveKeyWithValue("user_name", "ann"),
+			HaveKeyWithValue("years", 30),
+			Not(HaveKey("Secret")),
+			Not(HaveKey("Internal")),
+		))
+		Expect(flattenStruct(obj, FieldNamesGo)).To(SatisfyAll(
+			HaveKeyWithValue("UserName", "ann"),
+			HaveKeyWithValue("Secret", ""),
+		))
+	})
+
+	It("validates rules written against tag names", func() {
+		rules := []RuleEntry{
+			{Rule: "user_name == 'ann' && years >= 18", Enabled: true},
+			{Rule: "home.City == 'Oslo' && Tags[0].City == 'Rome'", Enabled: true},
+		}
+		md := ValidationMetadata{StructName: "Payload", Operation: "Create"}
+		for _, v := range []*Validator{NewValidator(WithFieldNaming(FieldNamesTag)), NewValidator(WithFieldNaming(FieldNamesTag), WithNestedFields())} {
+			results, err := v.Validate(obj, rules, md)
+			Expect(err).To(BeNil())
+			for _, res := range results {
+				Expect(res.Error).To(BeNil())
+				Expect(res.Passed).To(BeTrue(), res.Rule)
+			}
+		}
+	})
+})
@@ -0,0 +1,56 @@
+package celvalidator
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+// WithNativeTypes also exposes the object as the variable name typed with its
+// Go struct type, through cel-go native types, so rules can use real member
+// selection, has() and list and map semantics, e.g.
+// has(user.Address) && user.Address.City == 'Toronto'.
+// Flattened fields stay declared so existing rules keep working.
+func WithNativeTypes(name string) ValidatorOption {
+	return func(v *Validator) {
+		v.nativeVariable = name
+	}
+}
+
+// nativeTypeName is the CEL name cel-go native types give to a struct type
+func nativeTypeName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	for i := len(pkg) - 1; i >= 0; i-- {
+		if pkg[i] == '/' {
+			pkg = pkg[i+1:]
+			break
+		}
+	}
+	return pkg + "." + t.Name()
+}
+
+// nativeEnvOptions declares the native variable for obj's type
+func (v *Validator) nativeEnvOptions(obj any) ([]cel.EnvOption, error) {
+	val := indirect(reflect.ValueOf(obj))
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("native types require a struct, got %T", obj)
+	}
+	t := val.Type()
+	opts := []cel.EnvOption{
+		ext.NativeTypes(t),
+		cel.Variable(v.nativeVariable, cel.ObjectType(nativeTypeName(t))),
+	}
+	if v.methods {
+		opts = append(opts, methodFunctions(t, map[reflect.Type]bool{})...)
+	}
+	return opts, nil
+}
+
+// addNativeVariable sets the native variable of obj in vars when enabled
+func (v *Validator) addNativeVariable(vars map[string]any, obj any) {
+	if v.nativeVariable != "" {
+		vars[v.nativeVariable] = indirect(reflect.ValueOf(obj)).Interface()
+	}
+}
@@ -0,0 +1,314 @@
+package celvalidator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FailureAlert tells the owner of a rule that it failed Failures times within Window
+type FailureAlert struct {
+	Owner      string        `json:"owner"`
+	StructName string        `json:"struct"`
+	Operation  string        `json:"operation"`
+	ObjectID   string        `json:"object_id,omitempty"`
+	ID         string        `json:"id,omitempty"`
+	Rule       string        `json:"rule"`
+	Failures   int           `json:"failures"`
+	Window     time.Duration `json:"window"`
+	// Message is the failure message, or error, of the failure raising the alert
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+	// Description and Annotations are those of the rule
+	Description string            `json:"description,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Notifier delivers failure alerts, e.g. to a team channel
+type Notifier interface {
+	Notify(alert FailureAlert) error
+}
+
+// NotifierFunc adapts a function to a Notifier
+type NotifierFunc func(alert FailureAlert) error
+
+func (f NotifierFunc) Notify(alert FailureAlert) error {
+	return f(alert)
+}
+
+// WebhookNotifier posts alerts as JSON to a URL, e.g. a team chat webhook
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// defaultWebhookTimeout bounds the delivery of an alert, which later alerts wait for
+const defaultWebhookTimeout = 5 * time.Second
+
+// NewWebhookNotifier creates a notifier posting to url
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: defaultWebhookTimeout}}
+}
+
+// Notify posts the alert, failing on non-2xx responses
+func (n *WebhookNotifier) Notify(alert FailureAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("marshalling alert: %w", err)
+	}
+	resp, err := n.Client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("posting alert: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("posting alert: %s", resp.Status)
+	}
+	return nil
+}
+
+// WithFailureRouter records failing rules in r, alerting their owners
+func WithFailureRouter(r *FailureRouter) ValidatorOption {
+	return func(v *Validator) {
+		v.failureRouter = r
+	}
+}
+
+// FailureRouter counts failures per rule and alerts the owner of a rule once
+// it fails threshold times within a window, at most once per window. Alerts
+// are delivered in the background so validations never wait for notifiers,
+// alerts overflowing the delivery queue are dropped. It is safe for
+// concurrent use and meant to be shared by validators.
+type FailureRouter struct {
+	mu        sync.Mutex
+	routes    map[string]Notifier
+	fallback  Notifier
+	threshold int
+	window    time.Duration
+	onError   func(FailureAlert, error)
+	windows   map[string]*failureWindow
+	now       func() time.Time
+	queueSize int
+	queue     chan alertDelivery
+	dropped   atomic.Uint64
+	stop      chan struct{}
+	done      chan struct{}
+	// closeMu orders queued alerts before Close, which holds it to mark the
+	// router closed
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+// alertDelivery is an alert to deliver, or a flush marker closing flushed
+// once the alerts queued before it are delivered
+type alertDelivery struct {
+	alert   FailureAlert
+	flushed chan struct{}
+}
+
+// defaultAlertQueueSize is the number of alerts waiting for delivery past
+// which alerts are dropped
+const defaultAlertQueueSize = 1000
+
+type failureWindow struct {
+	start    time.Time
+	failures int
+	alerted  bool
+}
+
+// FailureRouterOption configures a FailureRouter
+type FailureRouterOption func(*FailureRouter)
+
+// WithAlertThreshold alerts once a rule fails failures times within window,
+// by default 10 times within a minute
+func WithAlertThreshold(failures int, window time.Duration) FailureRouterOption {
+	return func(r *FailureRouter) {
+		r.threshold = failures
+		r.window = window
+	}
+}
+
+// WithFallbackNotifier alerts n for rules without owner or whose owner has no route
+func WithFallbackNotifier(n Notifier) FailureRouterOption {
+	return func(r *FailureRouter) {
+		r.fallback = n
+	}
+}
+
+// WithAlertErrorHandler receives the errors of alert deliveries, which do not
+// fail validations
+func WithAlertErrorHandler(fn func(FailureAlert, error)) FailureRouterOption {
+	return func(r *FailureRouter) {
+		r.onError = fn
+	}
+}
+
+// WithAlertQueueSize bounds the alerts waiting for delivery, by default
+// 1000, alerts raised while the queue is full are dropped. Negative sizes are
+// ignored.
+func WithAlertQueueSize(size int) FailureRouterOption {
+	return func(r *FailureRouter) {
+		if size >= 0 {
+			r.queueSize = size
+		}
+	}
+}
+
+// NewFailureRouter creates a router alerting the notifier of routes keyed by
+// rule owner, e.g. a webhook per team, and starts its delivery goroutine
+func NewFailureRouter(routes map[string]Notifier, opts ...FailureRouterOption) *FailureRouter {
+	r := &FailureRouter{
+		routes:    routes,
+		threshold: 10,
+		window:    time.Minute,
+		windows:   map[string]*failureWindow{},
+		now:       time.Now,
+		queueSize: defaultAlertQueueSize,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.queue = make(chan alertDelivery, r.queueSize)
+	go r.deliver()
+	return r
+}
+
+// Dropped returns the number of alerts dropped because the delivery queue was full
+func (r *FailureRouter) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// Flush waits for the alerts raised so far to be delivered
+func (r *FailureRouter) Flush() {
+	flushed := make(chan struct{})
+	select {
+	case r.queue <- alertDelivery{flushed: flushed}:
+	case <-r.done:
+		return
+	}
+	select {
+	case <-flushed:
+	case <-r.done:
+	}
+}
+
+// Close delivers the queued alerts and stops the delivery goroutine, alerts
+// raised afterwards are dropped
+func (r *FailureRouter) Close() {
+	r.closeMu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.stop)
+	}
+	r.closeMu.Unlock()
+	<-r.done
+}
+
+// record counts the failures of a validation and queues the alerts they
+// raise, without blocking. Alerts queued before Close are delivered, the
+// others counted as dropped.
+func (r *FailureRouter) record(metadata ValidationMetadata, results []ValidationResult) {
+	alerts := r.alerts(metadata, results)
+	r.closeMu.RLock()
+	defer r.closeMu.RUnlock()
+	for _, alert := range alerts {
+		if r.closed {
+			r.dropped.Add(1)
+			continue
+		}
+		select {
+		case r.queue <- alertDelivery{alert: alert}:
+		default:
+			r.dropped.Add(1)
+		}
+	}
+}
+
+// deliver notifies queued alerts until the router is closed, then delivers
+// the alerts still queued
+func (r *FailureRouter) deliver() {
+	defer close(r.done)
+	for {
+		select {
+		case d := <-r.queue:
+			r.notify(d)
+		case <-r.stop:
+			for {
+				select {
+				case d := <-r.queue:
+					r.notify(d)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// notify delivers an alert to the notifier of its owner
+func (r *FailureRouter) notify(d alertDelivery) {
+	if d.flushed != nil {
+		close(d.flushed)
+		return
+	}
+	n, ok := r.routes[d.alert.Owner]
+	if !ok {
+		n = r.fallback
+	}
+	if n == nil {
+		return
+	}
+	if err := n.Notify(d.alert); err != nil && r.onError != nil {
+		r.onError(d.alert, err)
+	}
+}
+
+func (r *FailureRouter) alerts(metadata ValidationMetadata, results []ValidationResult) []FailureAlert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var alerts []FailureAlert
+	now := r.now()
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+		key := optimizerKey(metadata.StructName, ruleKey(result.ID, result.Rule))
+		w := r.windows[key]
+		if w == nil || now.Sub(w.start) >= r.window {
+			w = &failureWindow{start: now}
+			r.windows[key] = w
+		}
+		w.failures++
+		if w.alerted || w.failures < r.threshold {
+			continue
+		}
+		w.alerted = true
+
+		message := result.Message
+		if result.Error != nil {
+			message = result.Error.Error()
+		}
+		alerts = append(alerts, FailureAlert{
+			Owner:       result.Owner,
+			Description: result.Description,
+			Annotations: result.Annotations,
+			StructName:  metadata.StructName,
+			Operation:   metadata.Operation,
+			ObjectID:    metadata.ObjectID,
+			ID:          result.ID,
+			Rule:        result.Rule,
+			Failures:    w.failures,
+			Window:      r.window,
+			Message:     message,
+			Time:        now,
+		})
+	}
+	return alerts
+}
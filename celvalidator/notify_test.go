@@ -0,0 +1,182 @@
+package celvalidator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rule ownership", func() {
+	It("loads descriptions, owners and annotations and attaches them to results", func() {
+		file, err := ParseRuleFileYAML([]byte(`Sample:
+  Create:
+    - rule: "Age >= 18"
+      enabled: true
+      owner: identity
+      description: members must be adults
+      annotations:
+        runbook: https://runbooks.example.com/adult
+        ticket: ID-42
+`), WithStrictScalars())
+		Expect(err).To(BeNil())
+
+		provider := NewRuleProvider(file.Rules)
+		file.Rules["Sample"]["Create"][0].Annotations["ticket"] = "changed"
+		snapshot := provider.Snapshot()
+		results, err := NewValidator().Validate(Sample{Age: 10}, snapshot.RulesFor(Sample{}, "Create"), snapshot.Metadata(Sample{}, "Create"))
+		Expect(err).To(BeNil())
+		Expect(results[0].Owner).To(Equal("identity"))
+		Expect(results[0].Description).To(Equal("members must be adults"))
+		Expect(results[0].Annotations).To(Equal(map[string]string{"runbook": "https://runbooks.example.com/adult", "ticket": "ID-42"}))
+	})
+})
+
+var _ = Describe("Failure router", func() {
+	md := ValidationMetadata{StructName: "Sample", Operation: "Create"}
+	rules := []RuleEntry{
+		{ID: "adult", Rule: "Age >= 18", Enabled: true, Owner: "identity", FailureMessage: "too young",
+			Description: "members must be adults", Annotations: map[string]string{"runbook": "https://runbooks.example.com/adult"}},
+		{Rule: "Email != ''", Enabled: true},
+	}
+	var now time.Time
+	var alerts map[string][]FailureAlert
+	var router *FailureRouter
+
+	notifier := func(name string) Notifier {
+		return NotifierFunc(func(alert FailureAlert) error {
+			alerts[name] = append(alerts[name], alert)
+			return nil
+		})
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		alerts = map[string][]FailureAlert{}
+		router = NewFailureRouter(map[string]Notifier{"identity": notifier("identity")},
+			WithAlertThreshold(2, time.Minute), WithFallbackNotifier(notifier("fallback")))
+		router.now = func() time.Time { return now }
+		DeferCleanup(router.Close)
+	})
+
+	It("alerts the owner once a rule fails the threshold within the window", func() {
+		v := NewValidator(WithPartialEval(), WithFailureRouter(router))
+		results, err := v.Validate(Sample{Age: 10}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Owner).To(Equal("identity"))
+		router.Flush()
+		Expect(alerts).To(BeEmpty())
+
+		for range 3 {
+			_, err = v.Validate(Sample{Age: 10}, rules, md)
+			Expect(err).To(BeNil())
+		}
+		router.Flush()
+		Expect(alerts["identity"]).To(Equal([]FailureAlert{{
+			Owner: "identity", StructName: "Sample", Operation: "Create", ID: "adult", Rule: "Age >= 18",
+			Failures: 2, Window: time.Minute, Message: "too young", Time: now,
+			Description: "members must be adults", Annotations: map[string]string{"runbook": "https://runbooks.example.com/adult"},
+		}}))
+		Expect(alerts["fallback"]).To(HaveLen(1))
+		Expect(alerts["fallback"][0].Rule).To(Equal("Email != ''"))
+
+		now = now.Add(time.Minute)
+		_, err = v.Validate(Sample{Age: 10, Email: "a@b.c"}, rules, md)
+		Expect(err).To(BeNil())
+		router.Flush()
+		Expect(alerts["identity"]).To(HaveLen(1))
+		_, err = v.Validate(Sample{Age: 10, Email: "a@b.c"}, rules, md)
+		Expect(err).To(BeNil())
+		router.Flush()
+		Expect(alerts["identity"]).To(HaveLen(2))
+	})
+
+	It("drops alerts instead of blocking validations on slow notifiers", func() {
+		release := make(chan struct{})
+		router = NewFailureRouter(map[string]Notifier{
+			"identity": NotifierFunc(func(FailureAlert) error { <-release; return nil }),
+		}, WithAlertThreshold(1, time.Minute), WithAlertQueueSize(1))
+		DeferCleanup(router.Close)
+		DeferCleanup(func() { close(release) })
+
+		v := NewValidator(WithFailureRouter(router))
+		for i := range 3 {
+			owned := []RuleEntry{{Rule: fmt.Sprintf("Age >= %d", 18+i), Enabled: true, Owner: "identity"}}
+			_, err := v.Validate(Sample{Age: 10}, owned, md)
+			Expect(err).To(BeNil())
+		}
+		Expect(router.Dropped()).To(BeNumerically(">=", 1))
+	})
+
+	It("delivers or drops every alert raised while closing", func() {
+		var delivered atomic.Int64
+		router = NewFailureRouter(map[string]Notifier{
+			"identity": NotifierFunc(func(FailureAlert) error { delivered.Add(1); return nil }),
+		}, WithAlertThreshold(1, time.Minute), WithAlertQueueSize(-1))
+
+		v := NewValidator(WithFailureRouter(router))
+		var wg sync.WaitGroup
+		for i := range 50 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				owned := []RuleEntry{{Rule: fmt.Sprintf("Age >= %d", 18+i), Enabled: true, Owner: "identity"}}
+				_, err := v.Validate(Sample{Age: 10}, owned, md)
+				Expect(err).To(BeNil())
+			}()
+		}
+		router.Close()
+		wg.Wait()
+		Expect(delivered.Load() + int64(router.Dropped())).To(BeEquivalentTo(50))
+	})
+
+	It("counts rules sharing an expression by ID", func() {
+		shared := []RuleEntry{
+			{ID: "adult", Rule: "Age >= 18", Enabled: true, Owner: "identity"},
+			{ID: "adult-again", Rule: "Age >= 18", Enabled: true, Owner: "identity"},
+		}
+		_, err := NewValidator(WithFailureRouter(router)).Validate(Sample{Age: 10}, shared, md)
+		Expect(err).To(BeNil())
+		router.Flush()
+		Expect(alerts).To(BeEmpty())
+	})
+
+	It("reports delivery errors without failing validations", func() {
+		var delivery error
+		router = NewFailureRouter(map[string]Notifier{
+			"identity": NotifierFunc(func(FailureAlert) error { return errors.New("unreachable") }),
+		}, WithAlertThreshold(1, time.Minute), WithAlertErrorHandler(func(_ FailureAlert, err error) { delivery = err }))
+
+		DeferCleanup(router.Close)
+
+		_, err := NewValidator(WithFailureRouter(router)).Validate(Sample{Age: 10}, rules[:1], md)
+		Expect(err).To(BeNil())
+		router.Flush()
+		Expect(delivery).To(MatchError("unreachable"))
+	})
+
+	It("posts alerts to webhooks", func() {
+		received := make(chan FailureAlert, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var alert FailureAlert
+			Expect(json.NewDecoder(r.Body).Decode(&alert)).To(Succeed())
+			received <- alert
+		}))
+		DeferCleanup(server.Close)
+
+		Expect(NewWebhookNotifier(server.URL).Notify(FailureAlert{Owner: "identity", Rule: "Age >= 18"})).To(Succeed())
+		Expect(<-received).To(Equal(FailureAlert{Owner: "identity", Rule: "Age >= 18"}))
+
+		missing := httptest.NewServer(http.NotFoundHandler())
+		DeferCleanup(missing.Close)
+		Expect(NewWebhookNotifier(missing.URL).Notify(FailureAlert{})).To(MatchError(ContainSubstring("404")))
+	})
+})
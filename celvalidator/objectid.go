@@ -0,0 +1,51 @@
+package celvalidator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// IDExtractor returns the business identifier of a validated object, e.g. its
+// order number, or "" when it has none
+type IDExtractor func(obj any) string
+
+// objectIDTag marks the field holding the identifier read by TagIDExtractor
+const objectIDTag = "id"
+
+// WithIDExtractor sets the ObjectID of the metadata of validations, and so of
+// their results, audit records and failure alerts, from the validated object.
+// Metadata already carrying an ObjectID is left untouched.
+func WithIDExtractor(extract IDExtractor) ValidatorOption {
+	return func(v *Validator) {
+		v.idExtractor = extract
+	}
+}
+
+// TagIDExtractor reads the identifier from the top-level field tagged
+// celvalidator:"id", formatted with fmt.Sprint
+func TagIDExtractor(obj any) string {
+	val := indirect(reflect.ValueOf(obj))
+	if val.Kind() != reflect.Struct {
+		return ""
+	}
+	typ := val.Type()
+	for i := range typ.NumField() {
+		if typ.Field(i).Tag.Get("celvalidator") != objectIDTag {
+			continue
+		}
+		field := indirect(val.Field(i))
+		if !field.IsValid() || !field.CanInterface() || field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+			return ""
+		}
+		return fmt.Sprint(field.Interface())
+	}
+	return ""
+}
+
+// identify returns metadata carrying the ObjectID of obj
+func (v *Validator) identify(obj any, metadata ValidationMetadata) ValidationMetadata {
+	if v.idExtractor != nil && metadata.ObjectID == "" {
+		metadata.ObjectID = v.idExtractor(obj)
+	}
+	return metadata
+}
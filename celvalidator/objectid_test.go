@@ -0,0 +1,47 @@
+package celvalidator
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Object identity", func() {
+	type Order struct {
+		Number string `celvalidator:"id"`
+		Amount int
+	}
+	rules := []RuleEntry{{Rule: "Amount > 0", Enabled: true}, {Rule: "Amount < 10", Enabled: true, Then: []RuleEntry{{Rule: "Amount > 1", Enabled: true}}}}
+	md := ValidationMetadata{StructName: "Order", Operation: "Create", RuleIndex: -1}
+
+	It("reads the identifier from the tagged field", func() {
+		Expect(TagIDExtractor(Order{Number: "A-1"})).To(Equal("A-1"))
+		Expect(TagIDExtractor(&Order{Number: "A-2"})).To(Equal("A-2"))
+		Expect(TagIDExtractor(Sample{})).To(BeEmpty())
+		Expect(TagIDExtractor(nil)).To(BeEmpty())
+	})
+
+	It("carries the identifier on results and audit records", func() {
+		buf := &bytes.Buffer{}
+		v := NewValidator(WithIDExtractor(TagIDExtractor), WithAuditSink(NewHashChainSink(buf)))
+		results, err := v.Validate(Order{Number: "A-1", Amount: 5}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(3))
+		for _, res := range results {
+			Expect(res.Metadata.ObjectID).To(Equal("A-1"))
+		}
+
+		var record AuditRecord
+		Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+		Expect(record.ObjectID).To(Equal("A-1"))
+	})
+
+	It("keeps the identifier set on the metadata", func() {
+		v := NewValidator(WithIDExtractor(func(obj any) string { return "extracted" }))
+		results, err := v.Validate(Order{Amount: 5}, rules, NewMetadataBuilder("Order", "Create").WithObjectID("given").Build())
+		Expect(err).To(BeNil())
+		Expect(results[0].Metadata.ObjectID).To(Equal("given"))
+	})
+})
@@ -0,0 +1,150 @@
+package celvalidator
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+// errFailFast stops evaluation at the first failed rule in fail-fast mode
+var errFailFast = errors.New("fail fast")
+
+// WithFailFast stops evaluation at the first rule that does not pass, returning
+// the results evaluated so far
+func WithFailFast() ValidatorOption {
+	return func(v *Validator) {
+		v.failFast = true
+	}
+}
+
+// WithRuleOptimizer records rule outcomes in o and, in fail-fast mode, runs
+// sibling rules most likely to fail first, cheapest first among equally likely
+// ones, to cut the latency of rejecting invalid objects. Then children still
+// only run after their parent passed.
+func WithRuleOptimizer(o *RuleOptimizer) ValidatorOption {
+	return func(v *Validator) {
+		v.optimizer = o
+	}
+}
+
+// RuleOptimizer keeps in-memory per-rule failure counters and cost estimates,
+// it is safe for concurrent use and meant to be shared by validators
+type RuleOptimizer struct {
+	mu    sync.Mutex
+	stats map[string]*ruleStats
+}
+
+type ruleStats struct {
+	evaluated uint64
+	failed    uint64
+	cost      uint64
+	// costRule is the expression cost was estimated for, as rules with an ID
+	// keep their stats when their expression changes
+	costRule string
+	hasCost  bool
+}
+
+// NewRuleOptimizer creates an optimizer without history
+func NewRuleOptimizer() *RuleOptimizer {
+	return &RuleOptimizer{stats: map[string]*ruleStats{}}
+}
+
+// optimizerKey keys the stats of a rule on structName, rule is the ruleKey of
+// the rule
+func optimizerKey(structName, rule string) string {
+	return structName + "\x00" + rule
+}
+
+// FailureRate returns the observed failure rate of entry on structName,
+// smoothed so rules without history rank between always and never failing.
+// Rules are identified by ID, or by expression for rules without one.
+func (o *RuleOptimizer) FailureRate(structName string, entry RuleEntry) float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.failureRate(o.stats[optimizerKey(structName, entry.key())])
+}
+
+func (o *RuleOptimizer) failureRate(s *ruleStats) float64 {
+	if s == nil {
+		return 0.5
+	}
+	return float64(s.failed+1) / float64(s.evaluated+2)
+}
+
+// record counts the outcomes of a validation
+func (o *RuleOptimizer) record(structName string, results []ValidationResult) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, r := range results {
+		if r.Outcome == OutcomeSampledOut {
+			continue
+		}
+		s := o.statsFor(optimizerKey(structName, ruleKey(r.ID, r.Rule)))
+		s.evaluated++
+		if !r.Passed {
+			s.failed++
+		}
+	}
+}
+
+// statsFor returns the stats of key, o.mu must be held
+func (o *RuleOptimizer) statsFor(key string) *ruleStats {
+	s, ok := o.stats[key]
+	if !ok {
+		s = &ruleStats{}
+		o.stats[key] = s
+	}
+	return s
+}
+
+// order returns the indexes of entries in evaluation order
+func (o *RuleOptimizer) order(env *cel.Env, structName string, entries []RuleEntry) []int {
+	type ranked struct {
+		index int
+		rate  float64
+		cost  uint64
+	}
+
+	o.mu.Lock()
+	ranks := make([]ranked, len(entries))
+	for i, entry := range entries {
+		s := o.statsFor(optimizerKey(structName, entry.key()))
+		if !s.hasCost || s.costRule != entry.Rule {
+			cost, err := estimateRuleCost(env, entry.Rule)
+			if err != nil {
+				cost = math.MaxUint64
+			}
+			s.cost, s.costRule, s.hasCost = cost, entry.Rule, true
+		}
+		ranks[i] = ranked{index: i, rate: o.failureRate(s), cost: s.cost}
+	}
+	o.mu.Unlock()
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].rate != ranks[j].rate {
+			return ranks[i].rate > ranks[j].rate
+		}
+		return ranks[i].cost < ranks[j].cost
+	})
+
+	order := make([]int, len(ranks))
+	for i, r := range ranks {
+		order[i] = r.index
+	}
+	return order
+}
+
+// evalOrder returns the indexes of entries in the order they are evaluated
+func (v *Validator) evalOrder(env *cel.Env, structName string, entries []RuleEntry) []int {
+	if v.failFast && v.optimizer != nil {
+		return v.optimizer.order(env, structName, entries)
+	}
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
@@ -0,0 +1,49 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fail-fast and adaptive ordering", func() {
+	rules := []RuleEntry{
+		{Rule: "Age > 0", Enabled: true},
+		{Rule: "Email.endsWith('.com')", Enabled: true},
+		{Rule: "Age < 150", Enabled: true},
+	}
+	metadata := ValidationMetadata{StructName: "Sample"}
+
+	It("stops at the first failed rule", func() {
+		results, err := NewValidator(WithFailFast()).Validate(Sample{Age: 30, Email: "a@b.org"}, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[1].Passed).To(BeFalse())
+	})
+
+	It("runs historically failing rules first", func() {
+		optimizer := NewRuleOptimizer()
+		v := NewValidator(WithFailFast(), WithRuleOptimizer(optimizer))
+		for i := 0; i < 5; i++ {
+			_, err := v.Validate(Sample{Age: 30, Email: "a@b.org"}, rules, metadata)
+			Expect(err).To(BeNil())
+		}
+		Expect(optimizer.FailureRate("Sample", RuleEntry{Rule: "Email.endsWith('.com')"})).To(BeNumerically(">", optimizer.FailureRate("Sample", RuleEntry{Rule: "Age > 0"})))
+
+		results, err := v.Validate(Sample{Age: 30, Email: "a@b.org"}, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Rule).To(Equal("Email.endsWith('.com')"))
+		Expect(results[0].Metadata.RuleIndex).To(Equal(1))
+	})
+
+	It("keeps rule order outside fail-fast mode", func() {
+		optimizer := NewRuleOptimizer()
+		v := NewValidator(WithRuleOptimizer(optimizer))
+		for i := 0; i < 2; i++ {
+			results, err := v.Validate(Sample{Age: 30, Email: "a@b.org"}, rules, metadata)
+			Expect(err).To(BeNil())
+			Expect(results).To(HaveLen(3))
+			Expect(results[0].Rule).To(Equal("Age > 0"))
+		}
+	})
+})
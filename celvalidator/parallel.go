@@ -0,0 +1,106 @@
+package celvalidator
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// WithConcurrency evaluates top-level rules across n workers. Results keep the
+// order of the rules and Then children still run after their parent. Rules
+// repeated across chains are reported once, where a sequential evaluation
+// would report them, and fail-fast validations stay sequential.
+func WithConcurrency(n int) ValidatorOption {
+	return func(v *Validator) {
+		v.concurrency = n
+	}
+}
+
+// evalRules evaluates the top-level rules of a validation
+func (e *evaluation) evalRules(entries []RuleEntry, metadata ValidationMetadata) error {
+	if e.v.concurrency <= 1 || e.v.failFast || len(entries) < 2 {
+		return e.eval(entries, metadata)
+	}
+	return e.evalParallel(entries, metadata)
+}
+
+// chainResult holds the results of a top-level rule and its Then children
+type chainResult struct {
+	results []ValidationResult
+	err     error
+}
+
+// evalParallel evaluates each top-level rule chain in a forked evaluation and
+// merges the results in rule order, stopping at the first failing chain like a
+// sequential evaluation would
+func (e *evaluation) evalParallel(entries []RuleEntry, metadata ValidationMetadata) error {
+	var indexes []int
+	for i, entry := range entries {
+		if entry.Enabled && !e.seen[entry.key()] {
+			indexes = append(indexes, i)
+		}
+	}
+
+	chains := make([]chainResult, len(indexes))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(e.v.concurrency, len(indexes)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range work {
+				i := indexes[n]
+				fork := e.fork()
+				err := fork.evalEntry(i, entries[i], metadata)
+				chains[n] = chainResult{results: fork.results, err: err}
+			}
+		}()
+	}
+	for n := range indexes {
+		work <- n
+	}
+	close(work)
+	wg.Wait()
+
+	for _, chain := range chains {
+		// an error raised by a rule dropped as already seen would not have
+		// been raised sequentially
+		if kept := e.merge(chain.results); chain.err != nil && kept {
+			return chain.err
+		}
+	}
+	return nil
+}
+
+// fork returns an evaluation sharing e's bindings with its own results and
+// rules seen
+func (e *evaluation) fork() *evaluation {
+	f := *e
+	f.results = []ValidationResult{}
+	f.seen = maps.Clone(e.seen)
+	return &f
+}
+
+// merge appends the results of a chain evaluated in a fork, dropping the
+// rules already seen in earlier chains along with their Then children, as a
+// sequential evaluation would have skipped them. It reports whether the last
+// result of the chain was kept.
+func (e *evaluation) merge(results []ValidationResult) bool {
+	var skipped []int
+	kept := true
+	for _, res := range results {
+		path := res.Metadata.IndexPath()
+		if skipped != nil && len(path) > len(skipped) && slices.Equal(path[:len(skipped)], skipped) {
+			continue
+		}
+		skipped = nil
+		key := ruleKey(res.ID, res.Rule)
+		if kept = !e.seen[key]; !kept {
+			skipped = path
+			continue
+		}
+		e.seen[key] = true
+		e.results = append(e.results, res)
+	}
+	return kept
+}
@@ -0,0 +1,89 @@
+package celvalidator
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Concurrent evaluation", func() {
+	md := ValidationMetadata{StructName: "Sample"}
+	obj := Sample{Age: 30, Email: "a@example.com"}
+
+	It("matches sequential results and order", func() {
+		var rules []RuleEntry
+		for i := range 50 {
+			rules = append(rules, RuleEntry{Rule: fmt.Sprintf("Age > %d", i*2), Enabled: true, Then: []RuleEntry{
+				{Rule: fmt.Sprintf("Email.size() > %d", i), Enabled: true},
+			}})
+		}
+		rules = append(rules, RuleEntry{Rule: "Age > 0", Enabled: true})
+
+		sequential, err := NewValidator().Validate(obj, rules, md)
+		Expect(err).To(BeNil())
+		parallel, err := NewValidator(WithConcurrency(8)).Validate(obj, rules, md)
+		Expect(err).To(BeNil())
+		Expect(parallel).To(Equal(sequential))
+	})
+
+	It("reports Then children shared across chains like a sequential evaluation", func() {
+		shared := RuleEntry{ID: "shared", Rule: "Email.size() > 3", Enabled: true, Then: []RuleEntry{
+			{Rule: "Age < 100", Enabled: true},
+		}}
+		rules := []RuleEntry{
+			{Rule: "Age > 1", Enabled: true, Then: []RuleEntry{shared, {Rule: "Age > 3", Enabled: true}}},
+			{Rule: "Age > 2", Enabled: true, Then: []RuleEntry{shared, {Rule: "Age > 1", Enabled: true}}},
+			{Rule: "Age > 3", Enabled: true},
+			shared,
+		}
+
+		sequential, err := NewValidator().Validate(obj, rules, md)
+		Expect(err).To(BeNil())
+		Expect(sequential).To(HaveLen(5))
+		for range 20 {
+			parallel, err := NewValidator(WithConcurrency(4)).Validate(obj, rules, md)
+			Expect(err).To(BeNil())
+			Expect(parallel).To(Equal(sequential))
+		}
+	})
+
+	It("evaluates rules across workers", func() {
+		var running, peak atomic.Int32
+		v := NewValidator(WithConcurrency(4), WithResolver("slow", 1, func(rc ResolverContext, args []any) (any, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return true, nil
+		}))
+
+		var rules []RuleEntry
+		for i := range 8 {
+			rules = append(rules, RuleEntry{Rule: fmt.Sprintf("slow(%d)", i), Enabled: true})
+		}
+		results, err := v.Validate(obj, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(8))
+		Expect(peak.Load()).To(BeNumerically(">", 1))
+	})
+
+	It("stops at the first failing chain without partial evaluation", func() {
+		rules := []RuleEntry{
+			{Rule: "Age > 1", Enabled: true},
+			{Rule: "Missing > 1", Enabled: true},
+			{Rule: "Age > 2", Enabled: true},
+		}
+		results, err := NewValidator(WithConcurrency(3)).Validate(obj, rules, md)
+		Expect(err).NotTo(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[1].Outcome).To(Equal(OutcomeError))
+	})
+})
@@ -0,0 +1,185 @@
+package celvalidator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	expr "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+// RulePlan is a rule compiled for a struct type with the environment and
+// program options of validations, for external tools such as debuggers
+type RulePlan struct {
+	Rule string
+	// Checked is the checked AST, with its source info and type and reference
+	// maps, e.g. marshalled with protojson for a web debugger
+	Checked *expr.CheckedExpr
+	// Variables maps the variables of the environment to their CEL type
+	Variables map[string]string
+	// Nodes lists the expressions of the rule, operands before their calls
+	Nodes []PlanNode
+	// EstimatedCost bounds the cost of the rule, CostLimit being the per-rule
+	// limit of the validator, zero without one
+	EstimatedCost PlanCost
+	CostLimit     uint64
+
+	v   *Validator
+	env *cel.Env
+	ast *cel.Ast
+}
+
+// PlanNode is an expression of a planned rule, identified as in Checked
+type PlanNode struct {
+	ID int64
+	// Kind is ident, select, call, literal, list, map, struct or comprehension
+	Kind string
+	// Name is the identifier, selected field, function or literal value
+	Name   string
+	Type   string
+	Line   int
+	Column int
+}
+
+// PlanCost is the estimated cost range of a rule
+type PlanCost struct {
+	Min uint64
+	Max uint64
+}
+
+// PlanRule compiles rule for objects of obj's type in the environment used to
+// validate obj, resolvers and extensions included
+func (v *Validator) PlanRule(obj any, rule string) (*RulePlan, error) {
+	env, _, err := v.buildEnv(obj)
+	if err != nil {
+		return nil, err
+	}
+	checked, iss := env.Compile(rule)
+	if iss != nil && iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	checkedExpr, err := cel.AstToCheckedExpr(checked)
+	if err != nil {
+		return nil, fmt.Errorf("converting %q: %w", rule, err)
+	}
+	cost, err := env.EstimateCost(checked, boundedSizeEstimator{})
+	if err != nil {
+		return nil, fmt.Errorf("estimating cost of %q: %w", rule, err)
+	}
+
+	p := &RulePlan{
+		Rule:          rule,
+		Checked:       checkedExpr,
+		Variables:     map[string]string{},
+		EstimatedCost: PlanCost{Min: cost.Min, Max: cost.Max},
+		CostLimit:     v.ruleCostLimit,
+		v:             v,
+		env:           env,
+		ast:           checked,
+	}
+	for _, decl := range env.Variables() {
+		p.Variables[decl.Name()] = decl.Type().String()
+	}
+	native := checked.NativeRep()
+	p.Nodes = planNodes(native, native.Expr(), nil)
+	return p, nil
+}
+
+// Env returns the environment the rule was compiled in
+func (p *RulePlan) Env() *cel.Env {
+	return p.env
+}
+
+func planNodes(a *ast.AST, e ast.Expr, nodes []PlanNode) []PlanNode {
+	node := PlanNode{ID: e.ID(), Type: a.GetType(e.ID()).String()}
+	if loc := a.SourceInfo().GetStartLocation(e.ID()); loc.Line() > 0 {
+		node.Line, node.Column = loc.Line(), loc.Column()
+	}
+
+	switch e.Kind() {
+	case ast.IdentKind:
+		node.Kind, node.Name = "ident", e.AsIdent()
+	case ast.SelectKind:
+		node.Kind, node.Name = "select", e.AsSelect().FieldName()
+		nodes = planNodes(a, e.AsSelect().Operand(), nodes)
+	case ast.CallKind:
+		call := e.AsCall()
+		node.Kind, node.Name = "call", call.FunctionName()
+		if call.IsMemberFunction() {
+			nodes = planNodes(a, call.Target(), nodes)
+		}
+		for _, arg := range call.Args() {
+			nodes = planNodes(a, arg, nodes)
+		}
+	case ast.LiteralKind:
+		node.Kind, node.Name = "literal", fmt.Sprint(e.AsLiteral().Value())
+	case ast.ListKind:
+		node.Kind = "list"
+		for _, elem := range e.AsList().Elements() {
+			nodes = planNodes(a, elem, nodes)
+		}
+	case ast.MapKind:
+		node.Kind = "map"
+		for _, entry := range e.AsMap().Entries() {
+			nodes = planNodes(a, entry.AsMapEntry().Key(), nodes)
+			nodes = planNodes(a, entry.AsMapEntry().Value(), nodes)
+		}
+	case ast.StructKind:
+		node.Kind, node.Name = "struct", e.AsStruct().TypeName()
+		for _, field := range e.AsStruct().Fields() {
+			nodes = planNodes(a, field.AsStructField().Value(), nodes)
+		}
+	case ast.ComprehensionKind:
+		comp := e.AsComprehension()
+		node.Kind, node.Name = "comprehension", comp.IterVar()
+		for _, child := range []ast.Expr{comp.IterRange(), comp.AccuInit(), comp.LoopCondition(), comp.LoopStep(), comp.Result()} {
+			nodes = planNodes(a, child, nodes)
+		}
+	}
+	return append(nodes, node)
+}
+
+// RuleTrace is the evaluation of a planned rule against an object
+type RuleTrace struct {
+	Result any
+	Error  error
+	Cost   uint64
+	// Values holds the value of each evaluated expression by node ID, short
+	// circuited expressions being absent as in validations
+	Values map[int64]any
+}
+
+// Trace evaluates the planned rule against obj with the program options and
+// resolvers of validations, then again to track the value of every evaluated
+// expression, as cel-go does not track costs along with values
+func (p *RulePlan) Trace(ctx context.Context, obj any) (*RuleTrace, error) {
+	vars := p.v.objectFields(obj)
+	p.v.addNativeVariable(vars, obj)
+	e := p.v.newEvaluation(ctx, p.env, vars, ValidationMetadata{StructName: getStructName(obj), RuleIndex: -1})
+
+	prg, err := p.env.Program(p.ast, e.prgOpts...)
+	if err != nil {
+		return nil, err
+	}
+	out, details, err := prg.ContextEval(ctx, e.vars)
+	trace := &RuleTrace{Error: p.v.costError(err), Cost: actualCost(details), Values: map[int64]any{}}
+	if out != nil && !types.IsError(out) {
+		trace.Result = out.Value()
+	}
+
+	tracked, err := p.env.Program(p.ast, trackedProgramOptions(e.prgOpts)...)
+	if err != nil {
+		return nil, err
+	}
+	if _, details, _ = tracked.ContextEval(ctx, e.vars); details != nil {
+		state := details.State()
+		for _, id := range state.IDs() {
+			if val, ok := state.Value(id); ok && val != nil {
+				trace.Values[id] = val.Value()
+			}
+		}
+	}
+	return trace, nil
+}
@@ -0,0 +1,55 @@
+package celvalidator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rule plans", func() {
+	It("exports the checked AST and nodes of a rule", func() {
+		v := NewValidator(WithCostLimit(500, 0))
+		plan, err := v.PlanRule(Sample{}, "Age >= 18 && Email.endsWith('@example.com')")
+		Expect(err).To(BeNil())
+		Expect(plan.Checked.GetExpr()).NotTo(BeNil())
+		Expect(plan.Checked.GetTypeMap()).NotTo(BeEmpty())
+		Expect(plan.Variables).To(HaveKeyWithValue("Age", "int"))
+		Expect(plan.Variables).To(HaveKeyWithValue("Email", "string"))
+		Expect(plan.CostLimit).To(Equal(uint64(500)))
+		Expect(plan.EstimatedCost.Max).To(BeNumerically(">=", plan.EstimatedCost.Min))
+		Expect(plan.Env()).NotTo(BeNil())
+
+		var kinds, names []string
+		for _, node := range plan.Nodes {
+			kinds = append(kinds, node.Kind)
+			names = append(names, node.Name)
+		}
+		Expect(kinds).To(Equal([]string{"ident", "literal", "call", "ident", "literal", "call", "call"}))
+		Expect(names).To(Equal([]string{"Age", "18", "_>=_", "Email", "@example.com", "endsWith", "_&&_"}))
+		root := plan.Nodes[len(plan.Nodes)-1]
+		Expect(root.ID).To(Equal(plan.Checked.GetExpr().GetId()))
+		Expect(root.Type).To(Equal("bool"))
+		Expect(plan.Nodes[3].Line).To(Equal(1))
+		Expect(plan.Nodes[3].Column).To(Equal(13))
+	})
+
+	It("reports rules failing to compile", func() {
+		_, err := NewValidator().PlanRule(Sample{}, "Missing > 1")
+		Expect(err).To(MatchError(ContainSubstring("undeclared reference")))
+	})
+
+	It("traces the values of evaluated expressions", func() {
+		plan, err := NewValidator().PlanRule(Sample{}, "Age >= 18 && Email != ''")
+		Expect(err).To(BeNil())
+
+		trace, err := plan.Trace(context.Background(), Sample{Age: 10, Email: "a@b.c"})
+		Expect(err).To(BeNil())
+		Expect(trace.Error).To(BeNil())
+		Expect(trace.Result).To(Equal(false))
+		Expect(trace.Cost).To(BeNumerically(">", 0))
+		Expect(trace.Values).To(HaveKeyWithValue(plan.Nodes[0].ID, int64(10)))
+		Expect(trace.Values).To(HaveKeyWithValue(plan.Nodes[2].ID, false))
+		Expect(trace.Values).NotTo(HaveKey(plan.Nodes[3].ID))
+	})
+})
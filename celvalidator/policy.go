@@ -0,0 +1,297 @@
+package celvalidator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"gopkg.in/yaml.v3"
+)
+
+// CELPolicy is a policy in the YAML format of the cel-policy tooling. Its
+// matches are evaluated in order and the first matching one produces the
+// output, so a policy reports the first violation where Validate reports all.
+type CELPolicy struct {
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description,omitempty"`
+	Rule        PolicyRule `yaml:"rule"`
+}
+
+// PolicyRule is the rule of a CELPolicy or of a nested match
+type PolicyRule struct {
+	ID          string           `yaml:"id,omitempty"`
+	Description string           `yaml:"description,omitempty"`
+	Variables   []PolicyVariable `yaml:"variables,omitempty"`
+	Match       []PolicyMatch    `yaml:"match,omitempty"`
+}
+
+// PolicyVariable is a named expression, referenced as variables.<name>
+type PolicyVariable struct {
+	Name       string `yaml:"name"`
+	Expression string `yaml:"expression"`
+}
+
+// PolicyMatch produces Output, or evaluates the nested Rule, when Condition holds
+type PolicyMatch struct {
+	Condition   string      `yaml:"condition,omitempty"`
+	Output      string      `yaml:"output,omitempty"`
+	Explanation string      `yaml:"explanation,omitempty"`
+	Rule        *PolicyRule `yaml:"rule,omitempty"`
+}
+
+// defaultPolicyOutput prefixes the rule as output of rules without message
+const defaultPolicyOutput = "rule failed: "
+
+// ExportCELPolicies converts a rule set to one policy per struct operation,
+// named <struct>.<operation>. Each enabled rule becomes a match on its
+// negation whose output is its message expression or quoted message, and Then
+// children a nested rule matched when their parent holds.
+func ExportCELPolicies(rules RuleSetMap) []CELPolicy {
+	var policies []CELPolicy
+	for _, structName := range sortedRuleSetKeys(rules) {
+		ops := make([]string, 0, len(rules[structName]))
+		for op := range rules[structName] {
+			ops = append(ops, op)
+		}
+		sort.Strings(ops)
+		for _, op := range ops {
+			policies = append(policies, CELPolicy{
+				Name: structName + "." + op,
+				Rule: PolicyRule{Match: exportMatches(rules[structName][op])},
+			})
+		}
+	}
+	return policies
+}
+
+func exportMatches(entries []RuleEntry) []PolicyMatch {
+	var matches []PolicyMatch
+	for _, entry := range entries {
+		if !entry.Enabled || entry.Rule == "" {
+			continue
+		}
+		message := entry.FailureMessage
+		if message == "" {
+			message = defaultPolicyOutput + entry.Rule
+		}
+		output := strconv.Quote(message)
+		if entry.MessageExpression != "" {
+			output = entry.MessageExpression
+		}
+		matches = append(matches, PolicyMatch{
+			Condition: "!(" + entry.Rule + ")",
+			Output:    output,
+		})
+		if children := exportMatches(entry.Then); len(children) > 0 {
+			matches = append(matches, PolicyMatch{Condition: entry.Rule, Rule: &PolicyRule{Match: children}})
+		}
+	}
+	return matches
+}
+
+// ExportCELPolicyYAML writes the policies of a rule set as a multi-document YAML stream
+func ExportCELPolicyYAML(rules RuleSetMap) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, policy := range ExportCELPolicies(rules) {
+		if err := enc.Encode(policy); err != nil {
+			return nil, fmt.Errorf("marshalling YAML: %w", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("marshalling YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportCELPolicy converts a policy to the rules of a struct operation, taken
+// from a <struct>.<operation> name and defaulting to Default. Matches with an
+// output become rules on their negated condition, with the output as message
+// when it is a string literal and as message expression otherwise, and nested
+// rules become Then children. Variables are inlined outside string literals.
+func ImportCELPolicy(policy CELPolicy) (structName, operation string, rules []RuleEntry, err error) {
+	if policy.Name == "" {
+		return "", "", nil, errors.New("policy has no name")
+	}
+	structName, operation, ok := strings.Cut(policy.Name, ".")
+	if !ok {
+		operation = "Default"
+	}
+	rules, err = importRule(policy.Rule, nil)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("policy %s: %w", policy.Name, err)
+	}
+	return structName, operation, rules, nil
+}
+
+func importRule(rule PolicyRule, variables []PolicyVariable) ([]RuleEntry, error) {
+	// Variables of a nested rule may reference those of its parents
+	variables = append(append([]PolicyVariable(nil), variables...), rule.Variables...)
+	inline := func(expr string) string {
+		for i := len(variables) - 1; i >= 0; i-- {
+			pattern := regexp.MustCompile(`\bvariables\.` + regexp.QuoteMeta(variables[i].Name) + `\b`)
+			expr = replaceOutsideStrings(expr, func(code string) string {
+				return pattern.ReplaceAllLiteralString(code, "("+variables[i].Expression+")")
+			})
+		}
+		return expr
+	}
+
+	var entries []RuleEntry
+	for i, match := range rule.Match {
+		condition := inline(match.Condition)
+		switch {
+		case match.Rule != nil:
+			children, err := importRule(*match.Rule, variables)
+			if err != nil {
+				return nil, err
+			}
+			if condition == "" {
+				entries = append(entries, children...)
+				continue
+			}
+			if n := len(entries); n > 0 && entries[n-1].Rule == condition && len(entries[n-1].Then) == 0 {
+				entries[n-1].Then = children
+				continue
+			}
+			entries = append(entries, RuleEntry{Rule: condition, Enabled: true, Then: children})
+		case match.Output != "":
+			if condition == "" {
+				return nil, fmt.Errorf("match %d: output without condition always fails", i)
+			}
+			entry := RuleEntry{Rule: negateCondition(condition), Enabled: true}
+			output := inline(match.Output)
+			if message, ok := outputMessage(output); !ok {
+				entry.MessageExpression = output
+			} else if message != defaultPolicyOutput+entry.Rule {
+				entry.FailureMessage = message
+			}
+			entries = append(entries, entry)
+		default:
+			return nil, fmt.Errorf("match %d: no output or rule", i)
+		}
+	}
+	return entries, nil
+}
+
+// negateCondition returns the negation of a condition, unwrapping !(x)
+func negateCondition(condition string) string {
+	if inner, ok := strings.CutPrefix(condition, "!("); ok && strings.HasSuffix(inner, ")") && closesLast(condition[1:]) {
+		return strings.TrimSuffix(inner, ")")
+	}
+	return "!(" + condition + ")"
+}
+
+// closesLast reports whether the parenthesis opening s closes at its last character
+func closesLast(s string) bool {
+	depth := 0
+	var quote rune
+	escaped := false
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				return i == len(s)-1
+			}
+		}
+	}
+	return false
+}
+
+// outputMessage returns the value of a string literal output, false when the
+// output is another expression
+func outputMessage(output string) (string, bool) {
+	env, err := cel.NewEnv()
+	if err != nil {
+		return "", false
+	}
+	parsed, iss := env.Parse(output)
+	if iss != nil && iss.Err() != nil {
+		return "", false
+	}
+	if e := parsed.NativeRep().Expr(); e.Kind() == ast.LiteralKind {
+		if s, ok := e.AsLiteral().(types.String); ok {
+			return string(s), true
+		}
+	}
+	return "", false
+}
+
+// replaceOutsideStrings applies replace to the spans of a CEL expression
+// outside its string literals
+func replaceOutsideStrings(expr string, replace func(string) string) string {
+	var out strings.Builder
+	start := 0
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if c != '"' && c != '\'' {
+			continue
+		}
+		out.WriteString(replace(expr[start:i]))
+		raw := i > 0 && (expr[i-1] == 'r' || expr[i-1] == 'R')
+		delim := expr[i : i+1]
+		if strings.HasPrefix(expr[i:], strings.Repeat(delim, 3)) {
+			delim = strings.Repeat(delim, 3)
+		}
+		end := len(expr)
+		for j := i + len(delim); j < len(expr); j++ {
+			if expr[j] == '\\' && !raw {
+				j++
+				continue
+			}
+			if strings.HasPrefix(expr[j:], delim) {
+				end = j + len(delim)
+				break
+			}
+		}
+		out.WriteString(expr[i:end])
+		start, i = end, end-1
+	}
+	out.WriteString(replace(expr[start:]))
+	return out.String()
+}
+
+// ImportCELPolicyYAML reads a multi-document YAML stream of policies into a rule set
+func ImportCELPolicyYAML(data []byte) (RuleSetMap, error) {
+	rules := RuleSetMap{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var policy CELPolicy
+		if err := dec.Decode(&policy); errors.Is(err, io.EOF) {
+			return rules, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("unmarshalling YAML: %w", err)
+		}
+
+		structName, op, entries, err := ImportCELPolicy(policy)
+		if err != nil {
+			return nil, err
+		}
+		if rules[structName] == nil {
+			rules[structName] = map[string][]RuleEntry{}
+		}
+		rules[structName][op] = append(rules[structName][op], entries...)
+	}
+}
@@ -0,0 +1,103 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+var _ = Describe("CEL policies", func() {
+	rules := RuleSetMap{
+		"User": {
+			"Default": {
+				{Rule: "Age >= 18", Enabled: true, FailureMessage: "must be an adult", Then: []RuleEntry{
+					{Rule: "Name.startsWith('(')", Enabled: true},
+				}},
+				{Rule: "Email != ''", Enabled: false},
+			},
+			"Create": {
+				{Rule: "(Age > 1) || (Age < 0)", Enabled: true, FailureMessage: `say "hi"`},
+			},
+		},
+	}
+
+	It("exports one policy per struct operation", func() {
+		policies := ExportCELPolicies(rules)
+		Expect(policies).To(HaveLen(2))
+		Expect(policies[0].Name).To(Equal("User.Create"))
+		Expect(policies[1]).To(Equal(CELPolicy{
+			Name: "User.Default",
+			Rule: PolicyRule{Match: []PolicyMatch{
+				{Condition: "!(Age >= 18)", Output: `"must be an adult"`},
+				{Condition: "Age >= 18", Rule: &PolicyRule{Match: []PolicyMatch{
+					{Condition: "!(Name.startsWith('('))", Output: `"rule failed: Name.startsWith('(')"`},
+				}}},
+			}},
+		}))
+	})
+
+	It("round-trips enabled rules through YAML", func() {
+		data, err := ExportCELPolicyYAML(rules)
+		Expect(err).To(BeNil())
+		imported, err := ImportCELPolicyYAML(data)
+		Expect(err).To(BeNil())
+		Expect(imported).To(Equal(RuleSetMap{
+			"User": {
+				"Default": {rules["User"]["Default"][0]},
+				"Create":  rules["User"]["Create"],
+			},
+		}))
+	})
+
+	It("imports policies written upstream", func() {
+		var policy CELPolicy
+		Expect(yaml.Unmarshal([]byte(`
+name: Order
+rule:
+  variables:
+    - name: total
+      expression: Amount * Quantity
+  match:
+    - condition: variables.total > 1000
+      output: "'order over limit'"
+    - condition: Amount > 0
+      rule:
+        match:
+          - condition: Currency != 'USD'
+            output: "'unsupported currency ' + Currency"
+`), &policy)).To(Succeed())
+
+		structName, op, entries, err := ImportCELPolicy(policy)
+		Expect(err).To(BeNil())
+		Expect(structName).To(Equal("Order"))
+		Expect(op).To(Equal("Default"))
+		Expect(entries).To(Equal([]RuleEntry{
+			{Rule: "!((Amount * Quantity) > 1000)", Enabled: true, FailureMessage: "order over limit"},
+			{Rule: "Amount > 0", Enabled: true, Then: []RuleEntry{
+				{Rule: "!(Currency != 'USD')", Enabled: true, MessageExpression: "'unsupported currency ' + Currency"},
+			}},
+		}))
+	})
+
+	It("leaves variables named in string literals", func() {
+		policy := CELPolicy{Name: "Order", Rule: PolicyRule{
+			Variables: []PolicyVariable{{Name: "x", Expression: "Amount * 2"}},
+			Match: []PolicyMatch{
+				{Condition: `variables.x > 10 && Note != "variables.x"`, Output: `'variables.x is ' + string(variables.x)`},
+				{Condition: `Note == '''it's variables.x'''`, Output: `'bad note'`},
+			},
+		}}
+
+		_, _, entries, err := ImportCELPolicy(policy)
+		Expect(err).To(BeNil())
+		Expect(entries).To(Equal([]RuleEntry{
+			{Rule: `!((Amount * 2) > 10 && Note != "variables.x")`, Enabled: true, MessageExpression: `'variables.x is ' + string((Amount * 2))`},
+			{Rule: `!(Note == '''it's variables.x''')`, Enabled: true, FailureMessage: "bad note"},
+		}))
+	})
+
+	It("rejects matches without output or rule", func() {
+		_, _, _, err := ImportCELPolicy(CELPolicy{Name: "User", Rule: PolicyRule{Match: []PolicyMatch{{Condition: "true"}}}})
+		Expect(err).To(MatchError(ContainSubstring("no output or rule")))
+	})
+})
@@ -0,0 +1,91 @@
+package celvalidator
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/overloads"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/parser"
+)
+
+// isScalarPointer reports whether typ points to a predeclared scalar cel-go
+// dereferences, declared with the nullable type of the scalar, e.g. *string
+func isScalarPointer(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Ptr || typ.Elem().PkgPath() != "" {
+		return false
+	}
+	switch typ.Elem().Kind() {
+	case reflect.Bool, reflect.String, reflect.Int, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// nullPointerAdapter adapts nil scalar pointers to null, wrapping the type
+// adapter of the environment. Scalar pointer fields are kept as pointers so
+// nil and set ones declare the same nullable type.
+func nullPointerAdapter() cel.EnvOption {
+	return func(e *cel.Env) (*cel.Env, error) {
+		return cel.CustomTypeAdapter(pointerAdapter{e.CELTypeAdapter()})(e)
+	}
+}
+
+type pointerAdapter struct {
+	types.Adapter
+}
+
+func (a pointerAdapter) NativeToValue(value any) ref.Val {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() && isScalarPointer(v.Type()) {
+		return types.NullValue
+	}
+	return a.Adapter.NativeToValue(value)
+}
+
+// presenceMacro replaces has() so it tests the declared variables: has(Nick)
+// and has(Address.City) hold when the variable is not null, has(Address) when
+// variables under it are declared, and has(Address.City) on a declared
+// Address is guarded against a null Address. Other arguments are standard
+// presence tests.
+func presenceMacro(declared map[string]bool) cel.EnvOption {
+	return cel.Macros(cel.GlobalMacro(operators.Has, 1, func(eh cel.MacroExprFactory, target ast.Expr, args []ast.Expr) (ast.Expr, *cel.Error) {
+		// type(e) != null_type type checks whatever the type of e
+		notNull := func(e ast.Expr) ast.Expr {
+			return eh.NewCall(operators.NotEquals, eh.NewCall(overloads.TypeConvertType, eh.Copy(e)), eh.NewIdent("null_type"))
+		}
+		path, ok := fieldPath(args[0])
+		if !ok {
+			return parser.MakeHas(eh, target, args)
+		}
+		if declared[path] || declared == nil && args[0].Kind() == ast.IdentKind {
+			return notNull(args[0]), nil
+		}
+		for prefix := args[0]; prefix.Kind() == ast.SelectKind; {
+			prefix = prefix.AsSelect().Operand()
+			if name, _ := fieldPath(prefix); declared[name] {
+				presence, err := parser.MakeHas(eh, target, args)
+				if err != nil {
+					return nil, err
+				}
+				return eh.NewCall(operators.LogicalAnd, notNull(prefix), presence), nil
+			}
+		}
+		for name := range declared {
+			if strings.HasPrefix(name, path+".") {
+				return eh.NewLiteral(types.True), nil
+			}
+		}
+		return parser.MakeHas(eh, target, args)
+	}))
+}
+
+// parsePresenceMacro lets rules parsed without declarations test the
+// presence of variables, e.g. has(Nick)
+func parsePresenceMacro() cel.EnvOption {
+	return presenceMacro(nil)
+}
@@ -0,0 +1,56 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pointer fields", func() {
+	type Profile struct {
+		Name    string
+		Nick    *string
+		Age     *int
+		Score   *float64
+		Address *Address
+	}
+	md := ValidationMetadata{StructName: "Profile", Operation: "Create"}
+
+	passed := func(v *Validator, obj any, rule string) bool {
+		results, err := v.Validate(obj, []RuleEntry{{Rule: rule, Enabled: true}}, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Error).To(BeNil(), rule)
+		return results[0].Passed
+	}
+
+	It("dereferences set pointers and treats nil ones as unset", func() {
+		nick, age, score := "ann", 30, 1.5
+		set := Profile{Nick: &nick, Age: &age, Score: &score, Address: &Address{City: "Toronto"}}
+		v := NewValidator()
+
+		for _, rule := range []string{
+			"has(Nick) && Nick == 'ann'", "has(Age) && Age >= 18", "Score > 1.0",
+			"has(Address) && has(Address.City) && Address.City == 'Toronto'",
+		} {
+			Expect(passed(v, set, rule)).To(BeTrue(), rule)
+		}
+		for _, rule := range []string{"!has(Nick)", "Nick == null", "!has(Age) || Age >= 18", "!has(Score)"} {
+			Expect(passed(v, Profile{}, rule)).To(BeTrue(), rule)
+		}
+	})
+
+	It("declares the same types for nil and set pointers", func() {
+		nick := "ann"
+		v := NewValidator()
+		rule := "!has(Nick) || size(Nick) > 2"
+		Expect(passed(v, Profile{}, rule)).To(BeTrue())
+		Expect(passed(v, Profile{Nick: &nick}, rule)).To(BeTrue())
+		Expect(passed(v, Profile{}, rule)).To(BeTrue())
+	})
+
+	It("checks the presence of nested pointers in maps", func() {
+		v := NewValidator(WithNestedFields())
+		Expect(passed(v, Profile{Address: &Address{City: "Toronto"}}, "has(Address.City) && Address.City == 'Toronto'")).To(BeTrue())
+		Expect(passed(v, Profile{}, "!has(Address.City)")).To(BeTrue())
+	})
+})
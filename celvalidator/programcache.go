@@ -0,0 +1,140 @@
+package celvalidator
+
+import (
+	"container/list"
+	"sync"
+)
+
+// defaultProgramCacheSize bounds the programs cached by a validator by default
+const defaultProgramCacheSize = 1024
+
+// programCache is an LRU cache of compiled rules keyed by the environment they
+// were compiled in, i.e. struct type and field shape, and by rule expression.
+// Identical rules across operations and repeated validations compile once.
+type programCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[programKey]*list.Element
+	order    *list.List
+	hits     uint64
+	misses   uint64
+}
+
+type programKey struct {
+	env  envKey
+	rule string
+}
+
+type programCacheEntry struct {
+	key      programKey
+	compiled *compiledRule
+}
+
+// ProgramCacheStats reports the usage of a validator's program cache
+type ProgramCacheStats struct {
+	Size     int
+	Capacity int
+	Hits     uint64
+	Misses   uint64
+}
+
+func newProgramCache(capacity int) *programCache {
+	return &programCache{
+		capacity: capacity,
+		entries:  map[programKey]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// WithProgramCacheSize sets how many compiled rules the validator keeps, 0
+// disabling the cache
+func WithProgramCacheSize(size int) ValidatorOption {
+	return func(v *Validator) {
+		if size <= 0 {
+			v.programs = nil
+			return
+		}
+		v.programs = newProgramCache(size)
+	}
+}
+
+// Stats returns the program cache statistics of the validator
+func (v *Validator) Stats() ProgramCacheStats {
+	if v.programs == nil {
+		return ProgramCacheStats{}
+	}
+	return v.programs.stats()
+}
+
+func (c *programCache) get(key programKey) (*compiledRule, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(el)
+	return el.Value.(*programCacheEntry).compiled, true
+}
+
+func (c *programCache) put(key programKey, compiled *compiledRule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*programCacheEntry).compiled = compiled
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&programCacheEntry{key: key, compiled: compiled})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*programCacheEntry).key)
+	}
+}
+
+func (c *programCache) stats() ProgramCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ProgramCacheStats{
+		Size:     c.order.Len(),
+		Capacity: c.capacity,
+		Hits:     c.hits,
+		Misses:   c.misses,
+	}
+}
+
+// cachedCompileRule compiles a rule through the program cache. Only rules of
+// cached environments are cached, and programs only without resolvers, as
+// those are bound per validation.
+func (e *evaluation) cachedCompileRule(rule string) *compiledRule {
+	v := e.v
+	if v.programs == nil || v.envs == nil {
+		return compileRule(e.env, rule, e.vars)
+	}
+	envKey, ok := v.envs.keyOf(e.env)
+	if !ok {
+		return compileRule(e.env, rule, e.vars)
+	}
+
+	key := programKey{env: envKey, rule: rule}
+	if c, ok := v.programs.get(key); ok {
+		return c
+	}
+	c := compileRule(e.env, rule, e.vars)
+	if (c.iss == nil || c.iss.Err() == nil) && len(v.resolvers) == 0 {
+		prg, err := e.env.Program(c.ast, v.programOptions()...)
+		if err != nil {
+			// Program errors are reported on evaluation, planning again
+			return c
+		}
+		c.prg = prg
+		if v.checkedArith {
+			c.tracked, _ = e.env.Program(c.ast, trackedProgramOptions(v.programOptions())...)
+		}
+	}
+	v.programs.put(key, c)
+	return c
+}
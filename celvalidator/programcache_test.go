@@ -0,0 +1,59 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Program cache", func() {
+	md := ValidationMetadata{StructName: "Sample"}
+
+	It("compiles identical rules once across calls and operations", func() {
+		v := NewValidator()
+		create := []RuleEntry{{Rule: "Age >= 18", Enabled: true}, {Rule: "Email != ''", Enabled: true}}
+		update := []RuleEntry{{Rule: "Age >= 18", Enabled: true}}
+
+		for _, rules := range [][]RuleEntry{create, update, create} {
+			results, err := v.Validate(Sample{Age: 20, Email: "a@example.com"}, rules, md)
+			Expect(err).To(BeNil())
+			for _, r := range results {
+				Expect(r.Passed).To(BeTrue())
+			}
+		}
+
+		stats := v.Stats()
+		Expect(stats.Size).To(Equal(2))
+		Expect(stats.Misses).To(Equal(uint64(2)))
+		Expect(stats.Hits).To(Equal(uint64(3)))
+	})
+
+	It("evicts the least recently used rules", func() {
+		v := NewValidator(WithProgramCacheSize(2))
+		for _, rule := range []string{"Age > 1", "Age > 2", "Age > 1", "Age > 3", "Age > 1", "Age > 2"} {
+			_, err := v.Validate(Sample{Age: 5}, []RuleEntry{{Rule: rule, Enabled: true}}, md)
+			Expect(err).To(BeNil())
+		}
+		stats := v.Stats()
+		Expect(stats.Size).To(Equal(2))
+		Expect(stats.Capacity).To(Equal(2))
+		Expect(stats.Hits).To(Equal(uint64(2)))
+		Expect(stats.Misses).To(Equal(uint64(4)))
+	})
+
+	It("keeps reporting compile errors of cached rules", func() {
+		v := NewValidator(WithPartialEval())
+		for range 2 {
+			results, err := v.Validate(Sample{}, []RuleEntry{{Rule: "Missing > 1", Enabled: true}}, md)
+			Expect(err).To(BeNil())
+			Expect(results[0].Outcome).To(Equal(OutcomeError))
+		}
+		Expect(v.Stats().Hits).To(Equal(uint64(1)))
+	})
+
+	It("can be disabled", func() {
+		v := NewValidator(WithProgramCacheSize(0))
+		_, err := v.Validate(Sample{}, []RuleEntry{{Rule: "Age == 0", Enabled: true}}, md)
+		Expect(err).To(BeNil())
+		Expect(v.Stats()).To(Equal(ProgramCacheStats{}))
+	})
+})
@@ -0,0 +1,130 @@
+package celvalidator
+
+import (
+	"maps"
+	"slices"
+	"sync/atomic"
+)
+
+// RuleSetSnapshot is an immutable view of a rule set. A validation started
+// against a snapshot completes against it even if the provider is reloaded.
+type RuleSetSnapshot struct {
+	version           uint64
+	rules             RuleSetMap
+	defaultOperations map[string]string
+	versions          map[string]VersionMapping
+}
+
+// Version returns the provider generation of the snapshot
+func (s *RuleSetSnapshot) Version() uint64 {
+	return s.version
+}
+
+// RulesFor retrieves rules for a struct + operation from the snapshot, the
+// rules of the logical struct for a struct version
+func (s *RuleSetSnapshot) RulesFor(obj any, operation string) []RuleEntry {
+	if m, ok := s.versions[getStructName(obj)]; ok {
+		return GetRulesForStruct(m.Logical, operation, s.rules)
+	}
+	return GetRulesFor(obj, operation, s.rules)
+}
+
+// Metadata creates the validation metadata for a struct + operation from the snapshot
+func (s *RuleSetSnapshot) Metadata(obj any, operation string) ValidationMetadata {
+	return NewValidationMetadata(obj, operation, s.rules, WithDefaultOperations(s.defaultOperations), WithVersions(s.versions))
+}
+
+// Versions returns the struct version mappings of the snapshot
+func (s *RuleSetSnapshot) Versions() map[string]VersionMapping {
+	return s.versions
+}
+
+// RuleProvider holds the current rule set and swaps it atomically on reload
+type RuleProvider struct {
+	current atomic.Pointer[RuleSetSnapshot]
+}
+
+// NewRuleProvider creates a provider serving a copy of rules
+func NewRuleProvider(rules RuleSetMap) *RuleProvider {
+	p := &RuleProvider{}
+	p.Swap(&RuleFile{Rules: rules})
+	return p
+}
+
+// Snapshot returns the current rule set snapshot
+func (p *RuleProvider) Snapshot() *RuleSetSnapshot {
+	return p.current.Load()
+}
+
+// Swap replaces the current rule set with a copy of file and returns the new snapshot
+func (p *RuleProvider) Swap(file *RuleFile) *RuleSetSnapshot {
+	for {
+		prev := p.current.Load()
+		next := &RuleSetSnapshot{
+			rules:             copyRuleSetMap(file.Rules),
+			defaultOperations: make(map[string]string, len(file.DefaultOperations)),
+		}
+		for k, v := range file.DefaultOperations {
+			next.defaultOperations[k] = v
+		}
+		if len(file.Versions) > 0 {
+			next.versions = make(map[string]VersionMapping, len(file.Versions))
+			for k, v := range file.Versions {
+				next.versions[k] = v
+			}
+		}
+		if prev != nil {
+			next.version = prev.version + 1
+		}
+		if p.current.CompareAndSwap(prev, next) {
+			return next
+		}
+	}
+}
+
+// Reload loads a rule file and swaps it in, keeping the current snapshot on error
+func (p *RuleProvider) Reload(path string, opts ...LoadOption) (*RuleSetSnapshot, error) {
+	file, err := LoadRuleFileFromYAML(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return p.Swap(file), nil
+}
+
+// ValidateSnapshot validates obj for an operation using only the given snapshot
+func (v *Validator) ValidateSnapshot(obj any, operation string, snapshot *RuleSetSnapshot) ([]ValidationResult, error) {
+	return v.Validate(obj, snapshot.RulesFor(obj, operation), snapshot.Metadata(obj, operation))
+}
+
+// copyRuleSetMap deep copies a rule set so later changes to the source cannot leak in
+func copyRuleSetMap(rules RuleSetMap) RuleSetMap {
+	cp := make(RuleSetMap, len(rules))
+	for structName, operations := range rules {
+		cp[structName] = make(map[string][]RuleEntry, len(operations))
+		for op, entries := range operations {
+			cp[structName][op] = copyRuleEntries(entries)
+		}
+	}
+	return cp
+}
+
+func copyRuleEntries(entries []RuleEntry) []RuleEntry {
+	if entries == nil {
+		return nil
+	}
+	cp := make([]RuleEntry, len(entries))
+	for i, e := range entries {
+		cp[i] = e
+		cp[i].Then = copyRuleEntries(e.Then)
+		cp[i].LintWaivers = slices.Clone(e.LintWaivers)
+		cp[i].Annotations = maps.Clone(e.Annotations)
+		cp[i].Examples = RuleExamples{Pass: slices.Clone(e.Examples.Pass), Fail: slices.Clone(e.Examples.Fail)}
+		if e.Messages != nil {
+			cp[i].Messages = make(map[string]string, len(e.Messages))
+			for locale, msg := range e.Messages {
+				cp[i].Messages[locale] = msg
+			}
+		}
+	}
+	return cp
+}
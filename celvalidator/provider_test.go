@@ -0,0 +1,71 @@
+package celvalidator
+
+import (
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RuleProvider", func() {
+	// versionedRules builds a rule set whose every rule carries the generation in its message
+	versionedRules := func(gen int) RuleSetMap {
+		msg := fmt.Sprintf("v%d", gen)
+		return RuleSetMap{
+			"Sample": {
+				"Default": {{Rule: "Age < 0", Enabled: true, FailureMessage: msg}},
+				"Create": {
+					{Rule: "Email == ''", Enabled: true, FailureMessage: msg},
+					{Rule: "Active == false", Enabled: true, FailureMessage: msg},
+				},
+			},
+		}
+	}
+
+	It("isolates snapshots from the source map and later swaps", func() {
+		rules := versionedRules(0)
+		p := NewRuleProvider(rules)
+		snap := p.Snapshot()
+
+		rules["Sample"]["Create"][0].FailureMessage = "mutated"
+		p.Swap(&RuleFile{Rules: versionedRules(1)})
+
+		Expect(p.Snapshot().Version()).To(Equal(snap.Version() + 1))
+		for _, r := range snap.RulesFor(Sample{}, "Create") {
+			Expect(r.FailureMessage).To(Equal("v0"))
+		}
+	})
+
+	It("never mixes generations during concurrent reloads", func() {
+		p := NewRuleProvider(versionedRules(0))
+		v := NewValidator(WithPartialEval())
+		obj := Sample{Active: true, Age: 21, Email: "test@example.com"}
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for gen := 1; gen <= 200; gen++ {
+				p.Swap(&RuleFile{Rules: versionedRules(gen)})
+			}
+		}()
+
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for range 100 {
+					results, err := v.ValidateSnapshot(obj, "Create", p.Snapshot())
+					Expect(err).To(BeNil())
+					Expect(results).To(HaveLen(3))
+					for _, r := range results {
+						Expect(r.Message).To(Equal(results[0].Message))
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	})
+})
@@ -0,0 +1,92 @@
+package celvalidator
+
+import "slices"
+
+// ResultQuery selects results from a report. Empty criteria match everything;
+// non-empty criteria must all match, and any value of a criterion may match.
+type ResultQuery struct {
+	Outcomes   []Outcome
+	RuleIDs    []string
+	Severities []Severity
+	// Fields matches results of rules referencing a field, with the same path
+	// matching as SelectRulesByFieldMask
+	Fields []string
+	// Offset and Limit page through the matching results, a zero Limit means no limit
+	Offset int
+	Limit  int
+}
+
+// ResultPage is one page of the results matching a query
+type ResultPage struct {
+	Results ValidationReport
+	// Total counts all matching results, across pages
+	Total int
+	// NextOffset is the offset of the next page, or -1 on the last page
+	NextOffset int
+}
+
+// Filter returns the results matching the query criteria, ignoring paging
+func (r ValidationReport) Filter(q ResultQuery) ValidationReport {
+	var matched ValidationReport
+	for _, res := range r {
+		if q.matches(res) {
+			matched = append(matched, res)
+		}
+	}
+	return matched
+}
+
+// Query returns the page of results matching the query
+func (r ValidationReport) Query(q ResultQuery) ResultPage {
+	matched := r.Filter(q)
+	page := ResultPage{Total: len(matched), NextOffset: -1}
+
+	start := min(max(q.Offset, 0), len(matched))
+	end := len(matched)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+		page.NextOffset = end
+	}
+	page.Results = matched[start:end]
+	return page
+}
+
+func (q ResultQuery) matches(res ValidationResult) bool {
+	if len(q.Outcomes) > 0 && !slices.Contains(q.Outcomes, resultOutcome(res)) {
+		return false
+	}
+	if len(q.RuleIDs) > 0 && !slices.Contains(q.RuleIDs, res.ID) {
+		return false
+	}
+	if len(q.Severities) > 0 && !slices.Contains(q.Severities, resultSeverity(res)) {
+		return false
+	}
+	if len(q.Fields) > 0 {
+		vars, err := RuleVariables(res.Rule)
+		if err != nil || !variablesMatchMask(vars, q.Fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// resultOutcome derives the outcome of results built without one, e.g. decoded from older reports
+func resultOutcome(res ValidationResult) Outcome {
+	switch {
+	case res.Outcome != "":
+		return res.Outcome
+	case res.Error != nil:
+		return OutcomeError
+	case res.Passed:
+		return OutcomePassed
+	default:
+		return OutcomeFailed
+	}
+}
+
+func resultSeverity(res ValidationResult) Severity {
+	if res.Severity == "" {
+		return SeverityError
+	}
+	return res.Severity
+}
@@ -0,0 +1,54 @@
+package celvalidator
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Result queries", func() {
+	report := ValidationReport{
+		{ID: "active", Rule: "Active", Passed: true, Outcome: OutcomePassed},
+		{ID: "adult", Rule: "Age >= 18", Outcome: OutcomeFailed, Severity: SeverityWarning},
+		{ID: "email", Rule: "Email.endsWith('@example.com')", Outcome: OutcomeFailed},
+		{Rule: "Details.team == 'a'", Error: errors.New("no such key")},
+		{Rule: "Age >", Outcome: OutcomeError},
+	}
+
+	It("filters by outcome, rule ID, severity and field", func() {
+		Expect(report.Filter(ResultQuery{Outcomes: []Outcome{OutcomeFailed}})).To(HaveLen(2))
+		Expect(report.Filter(ResultQuery{Outcomes: []Outcome{OutcomeError}})).To(HaveLen(2))
+		Expect(report.Filter(ResultQuery{RuleIDs: []string{"email", "active"}})).To(HaveLen(2))
+		Expect(report.Filter(ResultQuery{Severities: []Severity{SeverityError}})).To(HaveLen(4))
+
+		byField := report.Filter(ResultQuery{Fields: []string{"Age", "Details.team"}})
+		Expect(byField).To(HaveLen(2))
+		Expect(byField[0].ID).To(Equal("adult"))
+
+		combined := report.Filter(ResultQuery{Outcomes: []Outcome{OutcomeFailed}, Severities: []Severity{SeverityError}})
+		Expect(combined).To(HaveLen(1))
+		Expect(combined[0].ID).To(Equal("email"))
+	})
+
+	It("paginates matching results", func() {
+		page := report.Query(ResultQuery{Limit: 2})
+		Expect(page.Total).To(Equal(5))
+		Expect(page.Results).To(HaveLen(2))
+		Expect(page.NextOffset).To(Equal(2))
+
+		page = report.Query(ResultQuery{Offset: 4, Limit: 2})
+		Expect(page.Results).To(HaveLen(1))
+		Expect(page.NextOffset).To(Equal(-1))
+
+		page = report.Query(ResultQuery{Offset: 10})
+		Expect(page.Results).To(BeEmpty())
+		Expect(page.Total).To(Equal(5))
+	})
+
+	It("carries rule IDs on results", func() {
+		results, err := NewValidator().Validate(Sample{Age: 20}, []RuleEntry{{ID: "adult", Rule: "Age >= 18", Enabled: true}}, ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+		Expect(ValidationReport(results).Filter(ResultQuery{RuleIDs: []string{"adult"}})).To(HaveLen(1))
+	})
+})
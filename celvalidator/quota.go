@@ -0,0 +1,159 @@
+package celvalidator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrQuotaExceeded is matched by every QuotaError
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaLimit names the quota that rejected a rule set
+type QuotaLimit string
+
+const (
+	QuotaMaxRules            QuotaLimit = "max_rules"
+	QuotaMaxExpressionLength QuotaLimit = "max_expression_length"
+	QuotaMaxEstimatedCost    QuotaLimit = "max_estimated_cost"
+)
+
+// RuleQuota limits the rules a tenant can register. Zero values are unlimited.
+type RuleQuota struct {
+	MaxRules            int
+	MaxExpressionLength int
+	MaxEstimatedCost    uint64
+}
+
+// QuotaError reports which quota a tenant rule set exceeded
+type QuotaError struct {
+	Tenant string
+	Limit  QuotaLimit
+	Rule   string
+	Actual uint64
+	Max    uint64
+}
+
+func (e *QuotaError) Error() string {
+	if e.Rule != "" {
+		return fmt.Sprintf("tenant %s: rule %q exceeds %s (%d > %d)", e.Tenant, e.Rule, e.Limit, e.Actual, e.Max)
+	}
+	return fmt.Sprintf("tenant %s: rule set exceeds %s (%d > %d)", e.Tenant, e.Limit, e.Actual, e.Max)
+}
+
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// QuotaEnforcer checks tenant-authored rules against per-tenant quotas
+type QuotaEnforcer struct {
+	defaultQuota RuleQuota
+	quotas       map[string]RuleQuota
+}
+
+// NewQuotaEnforcer creates an enforcer applying defaultQuota to tenants without
+// a specific quota
+func NewQuotaEnforcer(defaultQuota RuleQuota, quotas map[string]RuleQuota) *QuotaEnforcer {
+	return &QuotaEnforcer{defaultQuota: defaultQuota, quotas: quotas}
+}
+
+// QuotaFor returns the quota applied to a tenant
+func (q *QuotaEnforcer) QuotaFor(tenant string) RuleQuota {
+	if quota, ok := q.quotas[tenant]; ok {
+		return quota
+	}
+	return q.defaultQuota
+}
+
+// Check verifies a tenant's rules for obj, including Then children, and
+// returns a *QuotaError for the first exceeded quota. Message and suggestion
+// expressions are held to the length and cost quotas of rules.
+func (q *QuotaEnforcer) Check(tenant string, obj any, rules []RuleEntry) error {
+	quota := q.QuotaFor(tenant)
+
+	all := flattenRuleTree(rules)
+	if quota.MaxRules > 0 && len(all) > quota.MaxRules {
+		return &QuotaError{Tenant: tenant, Limit: QuotaMaxRules, Actual: uint64(len(all)), Max: uint64(quota.MaxRules)}
+	}
+
+	for _, r := range all {
+		for _, expr := range r.expressions() {
+			if quota.MaxExpressionLength > 0 && len(expr) > quota.MaxExpressionLength {
+				return &QuotaError{Tenant: tenant, Limit: QuotaMaxExpressionLength, Rule: expr, Actual: uint64(len(expr)), Max: uint64(quota.MaxExpressionLength)}
+			}
+		}
+	}
+
+	if quota.MaxEstimatedCost == 0 {
+		return nil
+	}
+
+	env, err := newEnvFromFields(flattenStruct(obj, FieldNamesGo))
+	if err != nil {
+		return err
+	}
+	for _, r := range all {
+		for _, expr := range r.expressions() {
+			cost, err := estimateRuleCost(env, expr)
+			if err != nil {
+				return err
+			}
+			if cost > quota.MaxEstimatedCost {
+				return &QuotaError{Tenant: tenant, Limit: QuotaMaxEstimatedCost, Rule: expr, Actual: cost, Max: quota.MaxEstimatedCost}
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckRuleSetMap verifies every struct/operation rule list of a tenant rule
+// set, objs providing an instance per struct name used for cost estimation
+func (q *QuotaEnforcer) CheckRuleSetMap(tenant string, rules RuleSetMap, objs ...any) error {
+	byName := make(map[string]any, len(objs))
+	for _, obj := range objs {
+		byName[getStructName(obj)] = obj
+	}
+
+	total := 0
+	for _, operations := range rules {
+		for _, entries := range operations {
+			total += len(flattenRuleTree(entries))
+		}
+	}
+	quota := q.QuotaFor(tenant)
+	if quota.MaxRules > 0 && total > quota.MaxRules {
+		return &QuotaError{Tenant: tenant, Limit: QuotaMaxRules, Actual: uint64(total), Max: uint64(quota.MaxRules)}
+	}
+
+	for structName, operations := range rules {
+		obj, ok := byName[structName]
+		if !ok && quota.MaxEstimatedCost > 0 {
+			return fmt.Errorf("no object provided for struct %s", structName)
+		}
+		for _, entries := range operations {
+			// MaxRules is enforced over the whole rule set above
+			perList := NewQuotaEnforcer(RuleQuota{
+				MaxExpressionLength: quota.MaxExpressionLength,
+				MaxEstimatedCost:    quota.MaxEstimatedCost,
+			}, nil)
+			if err := perList.Check(tenant, obj, entries); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// flattenRuleTree returns the rules and all their Then children, depth first
+func flattenRuleTree(rules []RuleEntry) []RuleEntry {
+	var all []RuleEntry
+	for _, r := range rules {
+		if r.Override != "" {
+			continue
+		}
+		all = append(all, r)
+		all = append(all, flattenRuleTree(r.Then)...)
+	}
+	return all
+}
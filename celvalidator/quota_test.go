@@ -0,0 +1,56 @@
+package celvalidator
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QuotaEnforcer", func() {
+	rules := []RuleEntry{
+		{Rule: "Age > 18", Enabled: true, Then: []RuleEntry{{Rule: "Email != ''", Enabled: true}}},
+	}
+
+	It("accepts rules within quota", func() {
+		q := NewQuotaEnforcer(RuleQuota{MaxRules: 2, MaxExpressionLength: 20, MaxEstimatedCost: 1000}, nil)
+		Expect(q.Check("acme", Sample{}, rules)).To(Succeed())
+	})
+
+	It("counts Then children against MaxRules", func() {
+		q := NewQuotaEnforcer(RuleQuota{}, map[string]RuleQuota{"acme": {MaxRules: 1}})
+
+		err := q.Check("acme", Sample{}, rules)
+		Expect(err).To(MatchError(ErrQuotaExceeded))
+
+		Expect(err).To(BeAssignableToTypeOf(&QuotaError{}))
+		Expect(err.(*QuotaError).Limit).To(Equal(QuotaMaxRules))
+		Expect(q.Check("other", Sample{}, rules)).To(Succeed())
+	})
+
+	It("rejects long and expensive expressions", func() {
+		q := NewQuotaEnforcer(RuleQuota{MaxExpressionLength: 5}, nil)
+		err := q.Check("acme", Sample{}, rules)
+		Expect(err).To(MatchError(ErrQuotaExceeded))
+		Expect(err.(*QuotaError).Limit).To(Equal(QuotaMaxExpressionLength))
+
+		expensive := []RuleEntry{{Rule: "Email.contains('a') && " + strings.Repeat("Email.contains('b') && ", 20) + "true", Enabled: true}}
+		q = NewQuotaEnforcer(RuleQuota{MaxEstimatedCost: 50}, nil)
+		err = q.Check("acme", Sample{}, expensive)
+		Expect(err).To(MatchError(ErrQuotaExceeded))
+		Expect(err.(*QuotaError).Limit).To(Equal(QuotaMaxEstimatedCost))
+	})
+
+	It("holds message and suggestion expressions to the quotas", func() {
+		long := []RuleEntry{{Rule: "true", Enabled: true, MessageExpression: "'too long'"}}
+		err := NewQuotaEnforcer(RuleQuota{MaxExpressionLength: 5}, nil).Check("acme", Sample{}, long)
+		Expect(err).To(MatchError(ErrQuotaExceeded))
+		Expect(err.(*QuotaError).Rule).To(Equal("'too long'"))
+
+		expensive := []RuleEntry{{Rule: "true", Enabled: true,
+			SuggestionExpression: "Email.contains('a') && " + strings.Repeat("Email.contains('b') && ", 20) + "true"}}
+		err = NewQuotaEnforcer(RuleQuota{MaxEstimatedCost: 50}, nil).Check("acme", Sample{}, expensive)
+		Expect(err).To(MatchError(ErrQuotaExceeded))
+		Expect(err.(*QuotaError).Limit).To(Equal(QuotaMaxEstimatedCost))
+	})
+})
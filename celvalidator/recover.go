@@ -0,0 +1,92 @@
+package celvalidator
+
+import (
+	"fmt"
+	runtimedebug "runtime/debug"
+)
+
+// WithRecover turns panics during a validation, e.g. in a resolver or while
+// reading the object's fields, into an error result instead of crashing the caller
+func WithRecover() ValidatorOption {
+	return func(v *Validator) {
+		v.recover = true
+	}
+}
+
+// PanicError is the error of a result whose evaluation panicked, carrying the
+// stack of the panicking goroutine. The stack is left out of Error, so
+// messages sent to remote callers, e.g. by the rule service, do not leak it.
+type PanicError struct {
+	// Rule is the expression of the panicking rule, empty for panics outside
+	// of rule evaluation and in resolvers
+	Rule  string
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	if e.Rule != "" {
+		return fmt.Sprintf("rule %q: panic: %v", e.Rule, e.Value)
+	}
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value when it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+func newPanicError(r any) *PanicError {
+	return &PanicError{Value: r, Stack: runtimedebug.Stack()}
+}
+
+// recovering calls fn, returning a panic as a *PanicError when enabled.
+// Functions called by CEL must recover themselves for the stack to be kept, as
+// cel-go reports their panics as plain errors.
+func recovering(enabled bool, fn func() (any, error)) (out any, err error) {
+	if enabled {
+		defer func() {
+			if r := recover(); r != nil {
+				out, err = nil, newPanicError(r)
+			}
+		}()
+	}
+	return fn()
+}
+
+// recoverEntry is deferred by evalEntry, reporting a panic of the rule as its result
+func (e *evaluation) recoverEntry(i int, entry RuleEntry, metadata ValidationMetadata, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	panicErr := newPanicError(r)
+	panicErr.Rule = entry.Rule
+	result := newResult(entry, metadata, i)
+	result.Error = panicErr
+	result.Outcome = e.v.outcome(false, panicErr)
+	e.results = append(e.results, result)
+	*err = nil
+	if e.v.failFast {
+		*err = errFailFast
+	}
+}
+
+// recoverValidation is deferred by validations, reporting a panic outside of
+// rule evaluation as a single error result
+func (v *Validator) recoverValidation(metadata ValidationMetadata, results *[]ValidationResult, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	panicErr := newPanicError(r)
+	*results = []ValidationResult{{
+		Passed:   false,
+		Error:    panicErr,
+		Severity: SeverityError,
+		Outcome:  OutcomeError,
+		Metadata: metadata,
+	}}
+	*err = panicErr
+}
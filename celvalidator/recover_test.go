@@ -0,0 +1,86 @@
+package celvalidator
+
+import (
+	"errors"
+
+	"github.com/google/cel-go/common/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// panickyBool panics when its value is read
+type panickyBool struct {
+	types.Bool
+}
+
+func (panickyBool) Value() any {
+	panic("reading value")
+}
+
+type panickySink struct{}
+
+func (panickySink) Record(AuditRecord) error {
+	panic(errors.New("sink down"))
+}
+
+var _ = Describe("Panic recovery", func() {
+	md := ValidationMetadata{StructName: "Sample"}
+	panicking := WithResolver("boom", 1, func(rc ResolverContext, args []any) (any, error) {
+		var m map[string]int
+		m["x"] = 1
+		return true, nil
+	})
+
+	It("reports resolver panics with their stack", func() {
+		rules := []RuleEntry{
+			{Rule: "boom(Age)", Enabled: true},
+			{Rule: "Age == 0", Enabled: true},
+		}
+		results, err := NewValidator(panicking, WithRecover()).Validate(Sample{}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+
+		var panicErr *PanicError
+		Expect(errors.As(results[0].Error, &panicErr)).To(BeTrue())
+		Expect(panicErr.Error()).To(ContainSubstring("assignment to entry in nil map"))
+		Expect(string(panicErr.Stack)).To(ContainSubstring("recover_test.go"))
+		Expect(panicErr.Error()).NotTo(ContainSubstring("goroutine"))
+		Expect(results[0].Outcome).To(Equal(OutcomeError))
+		Expect(results[1].Passed).To(BeTrue())
+	})
+
+	It("reports panics while evaluating a rule as its result", func() {
+		flag := WithVariableResolver("ext", VariableResolverFunc(func(rc ResolverContext, name string) (any, error) {
+			return panickyBool{types.True}, nil
+		}))
+		rules := []RuleEntry{
+			{Rule: "ext.flag", Enabled: true},
+			{Rule: "Age == 0", Enabled: true},
+		}
+		results, err := NewValidator(flag, WithRecover()).Validate(Sample{}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Rule).To(Equal("ext.flag"))
+		Expect(results[0].Error).To(MatchError(`rule "ext.flag": panic: reading value`))
+		var panicErr *PanicError
+		Expect(errors.As(results[0].Error, &panicErr)).To(BeTrue())
+		Expect(string(panicErr.Stack)).To(ContainSubstring("goroutine"))
+		Expect(results[1].Passed).To(BeTrue())
+	})
+
+	It("reports panics outside of rules as a single result", func() {
+		rules := []RuleEntry{{Rule: "Age == 0", Enabled: true}}
+		results, err := NewValidator(WithAuditSink(panickySink{}), WithRecover()).Validate(Sample{}, rules, md)
+		Expect(err).To(MatchError(ContainSubstring("sink down")))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Outcome).To(Equal(OutcomeError))
+		Expect(results[0].Metadata.StructName).To(Equal("Sample"))
+	})
+
+	It("keeps panicking without the option", func() {
+		rules := []RuleEntry{{Rule: "Age == 0", Enabled: true}}
+		Expect(func() {
+			_, _ = NewValidator(WithAuditSink(panickySink{})).Validate(Sample{}, rules, md)
+		}).To(Panic())
+	})
+})
@@ -0,0 +1,383 @@
+package celvalidator
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+)
+
+// Remediation operators, the comparison a field must satisfy
+const (
+	RemediateEqual        = "=="
+	RemediateNotEqual     = "!="
+	RemediateLess         = "<"
+	RemediateLessEqual    = "<="
+	RemediateGreater      = ">"
+	RemediateGreaterEqual = ">="
+	RemediateIn           = "in"
+)
+
+// RemediationDirective is a machine-readable fix of a failed rule: Field must
+// satisfy Operator against Target for the rule to pass
+type RemediationDirective struct {
+	ID       string `json:"id,omitempty"`
+	Rule     string `json:"rule"`
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Target   any    `json:"target"`
+}
+
+// Value returns the value to set the field to, false when the operator has no
+// single satisfying value, e.g. for != and <
+func (d RemediationDirective) Value() (any, bool) {
+	switch d.Operator {
+	case RemediateEqual, RemediateLessEqual, RemediateGreaterEqual:
+		return d.Target, true
+	case RemediateIn:
+		if list, ok := d.Target.([]any); ok && len(list) > 0 {
+			return list[0], true
+		}
+	}
+	return nil, false
+}
+
+// remediationOperators maps CEL comparison functions to remediation operators
+var remediationOperators = map[string]string{
+	operators.Equals:        RemediateEqual,
+	operators.NotEquals:     RemediateNotEqual,
+	operators.Less:          RemediateLess,
+	operators.LessEquals:    RemediateLessEqual,
+	operators.Greater:       RemediateGreater,
+	operators.GreaterEquals: RemediateGreaterEqual,
+	operators.In:            RemediateIn,
+}
+
+// flippedOperators is the operator holding with the operands swapped
+var flippedOperators = map[string]string{
+	RemediateLess:         RemediateGreater,
+	RemediateLessEqual:    RemediateGreaterEqual,
+	RemediateGreater:      RemediateLess,
+	RemediateGreaterEqual: RemediateLessEqual,
+}
+
+// Remediate returns the remediation directives of the failed rules in results.
+// Only simple rules are remediated: comparisons of a field with a literal, and
+// boolean fields, optionally negated, joined by &&. Clauses already holding for
+// obj yield no directive, and neither do rules of other shapes or rules that
+// failed with an error. Fields are named like the variables of v.
+func (v *Validator) Remediate(obj any, results []ValidationResult) []RemediationDirective {
+	fields := flattenStruct(obj, v.fieldNaming)
+	var directives []RemediationDirective
+	for _, result := range results {
+		if result.Passed || result.Error != nil {
+			continue
+		}
+		clauses, ok := ruleClauses(result.Rule)
+		if !ok {
+			continue
+		}
+		directives = appendRemediations(directives, result, clauses, fields)
+	}
+	return directives
+}
+
+// appendRemediations appends the clauses of a rule not holding for fields,
+// none when a clause names a field the object does not have
+func appendRemediations(directives []RemediationDirective, result ValidationResult, clauses []RemediationDirective, fields map[string]any) []RemediationDirective {
+	var failed []RemediationDirective
+	for _, clause := range clauses {
+		value, ok := fields[clause.Field]
+		if !ok {
+			return directives
+		}
+		if !clauseHolds(clause, value) {
+			clause.ID, clause.Rule = result.ID, result.Rule
+			failed = append(failed, clause)
+		}
+	}
+	return append(directives, failed...)
+}
+
+// ruleClauses parses a simple rule into one directive per clause
+func ruleClauses(rule string) ([]RemediationDirective, bool) {
+	env, err := cel.NewEnv(cel.ClearMacros())
+	if err != nil {
+		return nil, false
+	}
+	parsed, iss := env.Parse(rule)
+	if iss != nil && iss.Err() != nil {
+		return nil, false
+	}
+	return appendClauses(nil, parsed.NativeRep().Expr())
+}
+
+func appendClauses(clauses []RemediationDirective, e ast.Expr) ([]RemediationDirective, bool) {
+	if field, ok := fieldPath(e); ok {
+		return append(clauses, RemediationDirective{Field: field, Operator: RemediateEqual, Target: true}), true
+	}
+	if e.Kind() != ast.CallKind {
+		return nil, false
+	}
+
+	call := e.AsCall()
+	args := call.Args()
+	switch fn := call.FunctionName(); {
+	case fn == operators.LogicalAnd:
+		clauses, ok := appendClauses(clauses, args[0])
+		if !ok {
+			return nil, false
+		}
+		return appendClauses(clauses, args[1])
+	case fn == operators.LogicalNot:
+		if field, ok := fieldPath(args[0]); ok {
+			return append(clauses, RemediationDirective{Field: field, Operator: RemediateEqual, Target: false}), true
+		}
+	case remediationOperators[fn] != "" && len(args) == 2:
+		op := remediationOperators[fn]
+		if field, ok := fieldPath(args[0]); ok {
+			if target, ok := literalValue(args[1]); ok {
+				return append(clauses, RemediationDirective{Field: field, Operator: op, Target: target}), true
+			}
+		}
+		if op == RemediateIn {
+			return nil, false
+		}
+		if field, ok := fieldPath(args[1]); ok {
+			if target, ok := literalValue(args[0]); ok {
+				if flipped, ok := flippedOperators[op]; ok {
+					op = flipped
+				}
+				return append(clauses, RemediationDirective{Field: field, Operator: op, Target: target}), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// fieldPath returns the variable name of an identifier or select chain, e.g. Address.City
+func fieldPath(e ast.Expr) (string, bool) {
+	switch e.Kind() {
+	case ast.IdentKind:
+		return e.AsIdent(), true
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		if sel.IsTestOnly() {
+			return "", false
+		}
+		operand, ok := fieldPath(sel.Operand())
+		if !ok {
+			return "", false
+		}
+		return operand + "." + sel.FieldName(), true
+	}
+	return "", false
+}
+
+// literalValue returns the value of a scalar literal, or of a list of them
+func literalValue(e ast.Expr) (any, bool) {
+	switch e.Kind() {
+	case ast.LiteralKind:
+		switch v := e.AsLiteral().Value().(type) {
+		case bool, int64, uint64, float64, string:
+			return v, true
+		}
+	case ast.ListKind:
+		elems := e.AsList().Elements()
+		list := make([]any, len(elems))
+		for i, elem := range elems {
+			v, ok := literalValue(elem)
+			if !ok || elem.Kind() != ast.LiteralKind {
+				return nil, false
+			}
+			list[i] = v
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// clauseHolds reports whether a field value satisfies a clause, false when
+// the values cannot be compared
+func clauseHolds(d RemediationDirective, value any) bool {
+	if d.Operator == RemediateIn {
+		list, _ := d.Target.([]any)
+		for _, target := range list {
+			if cmp, ok := compareValues(value, target); ok && cmp == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	cmp, ok := compareValues(value, d.Target)
+	if !ok {
+		return false
+	}
+	switch d.Operator {
+	case RemediateEqual:
+		return cmp == 0
+	case RemediateNotEqual:
+		return cmp != 0
+	case RemediateLess:
+		return cmp < 0
+	case RemediateLessEqual:
+		return cmp <= 0
+	case RemediateGreater:
+		return cmp > 0
+	case RemediateGreaterEqual:
+		return cmp >= 0
+	}
+	return false
+}
+
+// compareValues compares numbers, strings and booleans, booleans only for equality
+func compareValues(a, b any) (int, bool) {
+	if x, ok := numberValue(a); ok {
+		if y, ok := numberValue(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+		return 0, false
+	}
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok && x == y {
+			return 0, true
+		} else if ok {
+			return 1, true
+		}
+	}
+	return 0, false
+}
+
+func numberValue(v any) (float64, bool) {
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	}
+	return 0, false
+}
+
+// ApplyRemediations sets the fields of the struct obj points to for the
+// directives allow accepts, returning the applied directives. Directives
+// without a single satisfying value are left to the caller, and values the
+// field cannot hold exactly are rejected.
+func (v *Validator) ApplyRemediations(obj any, directives []RemediationDirective, allow func(RemediationDirective) bool) ([]RemediationDirective, error) {
+	root := reflect.ValueOf(obj)
+	if root.Kind() != reflect.Ptr || root.IsNil() {
+		return nil, fmt.Errorf("remediating %T: not a pointer to a struct", obj)
+	}
+
+	var applied []RemediationDirective
+	for _, d := range directives {
+		value, ok := d.Value()
+		if !ok || (allow != nil && !allow(d)) {
+			continue
+		}
+		field, err := settableField(root, d.Field, v.fieldNaming)
+		if err != nil {
+			return applied, err
+		}
+		converted, err := convertRemediation(value, field.Type())
+		if err != nil {
+			return applied, fmt.Errorf("remediating %s: %w", d.Field, err)
+		}
+		field.Set(converted)
+		applied = append(applied, d)
+	}
+	return applied, nil
+}
+
+// settableField returns the field at a dotted path of variable names, following
+// non-nil pointers
+func settableField(root reflect.Value, path string, naming FieldNaming) (reflect.Value, error) {
+	val := root
+	for _, name := range strings.Split(path, ".") {
+		val = indirect(val)
+		if val.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("remediating %s: %s is not a struct field", path, name)
+		}
+		val = namedField(val, name, naming)
+		if !val.IsValid() || !val.CanSet() {
+			return reflect.Value{}, fmt.Errorf("remediating %s: no settable field %s", path, name)
+		}
+	}
+	return val, nil
+}
+
+// namedField returns the exported field of a struct value declared as name,
+// the zero Value when there is none
+func namedField(val reflect.Value, name string, naming FieldNaming) reflect.Value {
+	typ := val.Type()
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if fieldName, ok := naming.fieldName(field); ok && field.IsExported() && fieldName == name {
+			return val.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// convertRemediation converts a literal to a field type of the same kind
+// family, rejecting numbers the field cannot hold exactly
+func convertRemediation(value any, typ reflect.Type) (reflect.Value, error) {
+	val := reflect.ValueOf(value)
+	_, isNumber := numberValue(value)
+	_, fieldNumber := numberValue(reflect.Zero(typ).Interface())
+	if (isNumber != fieldNumber || (!isNumber && val.Kind() != typ.Kind())) || !val.Type().ConvertibleTo(typ) {
+		return reflect.Value{}, fmt.Errorf("cannot set %T to %v", value, typ)
+	}
+	if isNumber && !exactNumber(val, typ) {
+		return reflect.Value{}, fmt.Errorf("cannot set %v to %v without losing precision", value, typ)
+	}
+	return val.Convert(typ), nil
+}
+
+// exactNumber reports whether the number val converts to the numeric type typ
+// without truncation or overflow
+func exactNumber(val reflect.Value, typ reflect.Type) bool {
+	zero := reflect.Zero(typ)
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch val.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return val.Uint() <= math.MaxInt64 && !zero.OverflowInt(int64(val.Uint()))
+		case reflect.Float32, reflect.Float64:
+			f := val.Float()
+			return f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !zero.OverflowInt(int64(f))
+		}
+		return !zero.OverflowInt(val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return val.Int() >= 0 && !zero.OverflowUint(uint64(val.Int()))
+		case reflect.Float32, reflect.Float64:
+			f := val.Float()
+			return f == math.Trunc(f) && f >= 0 && f < math.MaxUint64 && !zero.OverflowUint(uint64(f))
+		}
+		return !zero.OverflowUint(val.Uint())
+	case reflect.Float32:
+		f, _ := numberValue(val.Interface())
+		return !zero.OverflowFloat(f)
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Remediation", func() {
+	md := ValidationMetadata{StructName: "User"}
+
+	validate := func(user User, rules ...RuleEntry) []ValidationResult {
+		results, err := NewValidator(WithPartialEval()).Validate(user, rules, md)
+		Expect(err).To(BeNil())
+		return results
+	}
+
+	It("emits directives for the failing clauses of simple rules", func() {
+		user := User{Age: 16, Address: Address{City: "Oslo"}}
+		results := validate(user,
+			RuleEntry{ID: "active", Rule: "IsActive", Enabled: true},
+			RuleEntry{ID: "adult", Rule: "18 <= Age && Address.City == 'Oslo'", Enabled: true},
+			RuleEntry{ID: "country", Rule: "Address.Country in ['NO', 'SE']", Enabled: true},
+			RuleEntry{Rule: "Name != ''", Enabled: true},
+		)
+		Expect(NewValidator().Remediate(user, results)).To(Equal([]RemediationDirective{
+			{ID: "active", Rule: "IsActive", Field: "IsActive", Operator: RemediateEqual, Target: true},
+			{ID: "adult", Rule: "18 <= Age && Address.City == 'Oslo'", Field: "Age", Operator: RemediateGreaterEqual, Target: int64(18)},
+			{ID: "country", Rule: "Address.Country in ['NO', 'SE']", Field: "Address.Country", Operator: RemediateIn, Target: []any{"NO", "SE"}},
+			{Rule: "Name != ''", Field: "Name", Operator: RemediateNotEqual, Target: ""},
+		}))
+	})
+
+	It("skips rules that are not simple", func() {
+		user := User{}
+		results := validate(user,
+			RuleEntry{Rule: "IsActive || Age > 18", Enabled: true},
+			RuleEntry{Rule: "Age > 18 && size(Name) > 2", Enabled: true},
+			RuleEntry{Rule: "!IsActive && Missing == 1", Enabled: true},
+		)
+		Expect(results).To(HaveLen(3))
+		Expect(NewValidator().Remediate(user, results)).To(BeEmpty())
+	})
+
+	It("applies the directives the policy allows", func() {
+		user := User{Name: "Bob", Age: 16}
+		results := validate(user,
+			RuleEntry{Rule: "IsActive && Age >= 18", Enabled: true},
+			RuleEntry{Rule: "Address.Zip > 1000", Enabled: true},
+		)
+		directives := NewValidator().Remediate(user, results)
+		Expect(directives).To(HaveLen(3))
+
+		applied, err := NewValidator().ApplyRemediations(&user, directives, func(d RemediationDirective) bool {
+			return d.Field != "Age"
+		})
+		Expect(err).To(BeNil())
+		Expect(applied).To(HaveLen(1))
+		Expect(user.IsActive).To(BeTrue())
+		Expect(user.Age).To(Equal(16))
+
+		applied, err = NewValidator().ApplyRemediations(&user, directives, nil)
+		Expect(err).To(BeNil())
+		Expect(applied).To(HaveLen(2))
+		Expect(user.Age).To(Equal(18))
+		Expect(validate(user, RuleEntry{Rule: "IsActive && Age >= 18", Enabled: true})[0].Passed).To(BeTrue())
+	})
+
+	It("rejects values of another type than the field", func() {
+		user := User{}
+		_, err := NewValidator().ApplyRemediations(&user, []RemediationDirective{{Field: "Name", Operator: RemediateEqual, Target: int64(1)}}, nil)
+		Expect(err).To(MatchError(ContainSubstring("cannot set int64 to string")))
+		_, err = NewValidator().ApplyRemediations(user, nil, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects numbers the field cannot hold exactly", func() {
+		user := User{}
+		_, err := NewValidator().ApplyRemediations(&user, []RemediationDirective{{Field: "Age", Operator: RemediateGreaterEqual, Target: 17.5}}, nil)
+		Expect(err).To(MatchError(ContainSubstring("cannot set 17.5 to int without losing precision")))
+		Expect(user.Age).To(Equal(0))
+
+		applied, err := NewValidator().ApplyRemediations(&user, []RemediationDirective{{Field: "Age", Operator: RemediateGreaterEqual, Target: 18.0}}, nil)
+		Expect(err).To(BeNil())
+		Expect(applied).To(HaveLen(1))
+		Expect(user.Age).To(Equal(18))
+	})
+
+	It("names fields like the variables of the validator", func() {
+		type Account struct {
+			Owner string `json:"owner"`
+			Limit int    `json:"limit"`
+		}
+		account := Account{Limit: 10}
+		v := NewValidator(WithPartialEval(), WithFieldNaming(FieldNamesJSON))
+		results, err := v.Validate(account, []RuleEntry{{Rule: "limit >= 100 && owner == 'ops'", Enabled: true}}, ValidationMetadata{StructName: "Account"})
+		Expect(err).To(BeNil())
+
+		directives := v.Remediate(account, results)
+		Expect(directives).To(HaveLen(2))
+		Expect(directives[0].Field).To(Equal("limit"))
+		Expect(directives[1].Field).To(Equal("owner"))
+
+		_, err = v.ApplyRemediations(&account, directives, nil)
+		Expect(err).To(BeNil())
+		Expect(account).To(Equal(Account{Owner: "ops", Limit: 100}))
+	})
+})
@@ -0,0 +1,152 @@
+package celvalidator
+
+import (
+	"sort"
+	"strings"
+)
+
+// ValidationReport is the full set of results produced by a single Validate call
+type ValidationReport []ValidationResult
+
+// ResultNode is a result with the results of the Then rules evaluated under it
+type ResultNode struct {
+	Result   ValidationResult
+	Children []*ResultNode
+}
+
+// Tree nests the results of Then rules under their parent result, mirroring
+// the rule tree so conditional logic can be rendered hierarchically. The report
+// must be in evaluation order, as returned by Validate.
+func (r ValidationReport) Tree() []*ResultNode {
+	base := -1
+	for _, res := range r {
+		if depth := chainDepth(res.Metadata.ChainPath); base < 0 || depth < base {
+			base = depth
+		}
+	}
+
+	var roots []*ResultNode
+	// ancestors holds the last node seen at each depth
+	var ancestors []*ResultNode
+	for _, res := range r {
+		node := &ResultNode{Result: res}
+		depth := chainDepth(res.Metadata.ChainPath) - base
+		if depth > len(ancestors) {
+			depth = len(ancestors)
+		}
+		ancestors = ancestors[:depth]
+		if depth == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := ancestors[depth-1]
+			parent.Children = append(parent.Children, node)
+		}
+		ancestors = append(ancestors, node)
+	}
+	return roots
+}
+
+// chainDepth counts the then steps of a chain path
+func chainDepth(chainPath string) int {
+	depth := 0
+	for _, step := range strings.Split(chainPath, " > ") {
+		if step == "then" {
+			depth++
+		}
+	}
+	return depth
+}
+
+// ResultChange pairs the previous and current outcome of the same rule
+type ResultChange struct {
+	Before ValidationResult
+	After  ValidationResult
+}
+
+// ReportDiff describes how a re-validation differs from a previous one
+type ReportDiff struct {
+	Added   []ValidationResult
+	Removed []ValidationResult
+	Changed []ResultChange
+}
+
+// Empty reports whether the two compared reports were equivalent
+func (d ReportDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// EqualReports compares two reports ignoring result ordering
+func EqualReports(a, b ValidationReport) bool {
+	return DiffReports(a, b).Empty()
+}
+
+// DiffReports compares two reports ignoring result ordering and returns the
+// results that were added, removed or whose outcome changed from a to b
+func DiffReports(a, b ValidationReport) ReportDiff {
+	var diff ReportDiff
+
+	before := indexResults(a)
+	after := indexResults(b)
+
+	for _, key := range sortedKeys(before) {
+		prev := before[key]
+		next, ok := after[key]
+		if !ok {
+			diff.Removed = append(diff.Removed, prev)
+			continue
+		}
+		if !sameOutcome(prev, next) {
+			diff.Changed = append(diff.Changed, ResultChange{Before: prev, After: next})
+		}
+	}
+
+	for _, key := range sortedKeys(after) {
+		if _, ok := before[key]; !ok {
+			diff.Added = append(diff.Added, after[key])
+		}
+	}
+
+	return diff
+}
+
+// resultKey identifies a rule evaluation independently of its position in the
+// report, by rule ID when set so changing the expression of a rule is a change
+// of its result rather than a removal and an addition
+func resultKey(r ValidationResult) string {
+	return r.Metadata.StructName + "\x00" +
+		r.Metadata.Operation + "\x00" +
+		ruleKey(r.Metadata.ParentRuleID, r.Metadata.ParentRule) + "\x00" +
+		ruleKey(r.ID, r.Rule)
+}
+
+func indexResults(report ValidationReport) map[string]ValidationResult {
+	index := make(map[string]ValidationResult, len(report))
+	for _, r := range report {
+		index[resultKey(r)] = r
+	}
+	return index
+}
+
+func sortedKeys(index map[string]ValidationResult) []string {
+	keys := make([]string, 0, len(index))
+	for k := range index {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sameOutcome compares the observable outcome of two results, errors by message
+func sameOutcome(a, b ValidationResult) bool {
+	return a.Passed == b.Passed &&
+		a.Message == b.Message &&
+		errorString(a.Error) == errorString(b.Error) &&
+		a.Metadata.ChainPath == b.Metadata.ChainPath
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
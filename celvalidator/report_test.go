@@ -0,0 +1,135 @@
+package celvalidator
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Report comparison", func() {
+	var a, b ValidationReport
+
+	BeforeEach(func() {
+		a = ValidationReport{
+			{Rule: "Age > 18", Passed: true, Metadata: ValidationMetadata{StructName: "User", Operation: "Create"}},
+			{Rule: "Email != ''", Passed: false, Message: "email required", Metadata: ValidationMetadata{StructName: "User", Operation: "Create"}},
+		}
+		b = ValidationReport{a[1], a[0]}
+	})
+
+	It("treats reordered reports as equal", func() {
+		Expect(EqualReports(a, b)).To(BeTrue())
+	})
+
+	It("reports changed outcomes", func() {
+		b[0].Passed = true
+		b[0].Message = ""
+
+		diff := DiffReports(a, b)
+		Expect(diff.Empty()).To(BeFalse())
+		Expect(diff.Changed).To(HaveLen(1))
+		Expect(diff.Changed[0].Before.Rule).To(Equal("Email != ''"))
+		Expect(diff.Changed[0].After.Passed).To(BeTrue())
+	})
+
+	It("compares errors by message", func() {
+		a[0].Error = errors.New("boom")
+		b[1].Error = errors.New("boom")
+		Expect(EqualReports(a, b)).To(BeTrue())
+	})
+
+	It("reports added and removed results", func() {
+		b = ValidationReport{a[0], {Rule: "IsActive", Passed: true, Metadata: ValidationMetadata{StructName: "User", Operation: "Create"}}}
+
+		diff := DiffReports(a, b)
+		Expect(diff.Removed).To(ConsistOf(HaveField("Rule", "Email != ''")))
+		Expect(diff.Added).To(ConsistOf(HaveField("Rule", "IsActive")))
+		Expect(diff.Changed).To(BeEmpty())
+	})
+
+	It("matches results by rule ID across expression changes", func() {
+		a[1].ID = "email"
+		b = ValidationReport{a[0], a[1]}
+		b[1].Rule = "Email.size() > 0"
+		b[1].Passed = true
+		b[1].Message = ""
+
+		diff := DiffReports(a, b)
+		Expect(diff.Added).To(BeEmpty())
+		Expect(diff.Removed).To(BeEmpty())
+		Expect(diff.Changed).To(HaveLen(1))
+		Expect(diff.Changed[0].After.Rule).To(Equal("Email.size() > 0"))
+	})
+})
+
+var _ = Describe("Decision policies", func() {
+	report := ValidationReport{
+		{Rule: "a", Passed: true, Weight: 5},
+		{Rule: "b", Passed: true},
+		{Rule: "c", Passed: false, Weight: 2},
+	}
+
+	It("requires every rule with AllMustPass", func() {
+		decision := report.Decide(AllMustPass())
+		Expect(decision.Valid).To(BeFalse())
+		Expect(decision.Passed).To(Equal(2))
+		Expect(decision.Failed).To(Equal(1))
+	})
+
+	It("accepts a majority with MajorityPass", func() {
+		Expect(report.Decide(MajorityPass()).Valid).To(BeTrue())
+	})
+
+	It("weighs rules with WeightedScore", func() {
+		decision := report.Decide(WeightedScore(0.75))
+		Expect(decision.Score).To(BeNumerically("~", 0.75))
+		Expect(decision.Valid).To(BeTrue())
+		Expect(report.Decide(WeightedScore(0.8)).Valid).To(BeFalse())
+	})
+})
+
+var _ = Describe("Risk scoring", func() {
+	It("adds the weight of failed rules with a per-rule breakdown", func() {
+		report := ValidationReport{
+			{Rule: "a", Passed: true, Weight: 5},
+			{Rule: "b", Passed: false, Weight: 3},
+			{Rule: "c", Passed: true, Error: errors.New("boom")},
+		}
+
+		score := report.RiskScore()
+		Expect(score.Score).To(Equal(4.0))
+		Expect(score.MaxScore).To(Equal(9.0))
+		Expect(score.Normalized()).To(BeNumerically("~", 4.0/9.0))
+		Expect(score.Contributions).To(HaveLen(3))
+		Expect(score.Contributions[0].Contribution).To(Equal(0.0))
+		Expect(score.Contributions[1].Contribution).To(Equal(3.0))
+		Expect(score.Contributions[2].Contribution).To(Equal(1.0))
+	})
+})
+
+var _ = Describe("Report tree", func() {
+	It("nests Then results under their parent", func() {
+		rules := []RuleEntry{
+			{Rule: "Age > 18", Enabled: true, Then: []RuleEntry{
+				{Rule: "Email != ''", Enabled: true, Then: []RuleEntry{
+					{Rule: "Email.endsWith('.com')", Enabled: true},
+				}},
+				{Rule: "Active", Enabled: true},
+			}},
+			{Rule: "Age < 100", Enabled: true},
+		}
+
+		results, err := NewValidator().Validate(Sample{Age: 30, Email: "a@b.com", Active: true}, rules, ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+
+		tree := ValidationReport(results).Tree()
+		Expect(tree).To(HaveLen(2))
+		Expect(tree[0].Result.Rule).To(Equal("Age > 18"))
+		Expect(tree[0].Children).To(HaveLen(2))
+		Expect(tree[0].Children[0].Children).To(ConsistOf(HaveField("Result.Rule", "Email.endsWith('.com')")))
+		Expect(tree[0].Children[1].Result.Rule).To(Equal("Active"))
+		Expect(tree[1].Result.Rule).To(Equal("Age < 100"))
+		Expect(tree[1].Children).To(BeEmpty())
+	})
+})
@@ -0,0 +1,39 @@
+package celvalidator
+
+import (
+	"context"
+	"reflect"
+)
+
+type requestVarsKey struct{}
+
+// WithRequestVars attaches variables describing the request, e.g. requestor,
+// tenantID or clientIP, to validations run with ctx. They are declared for the
+// rules alongside the fields of the object, which shadow them, and shadow
+// globals. Variables already attached to ctx are kept unless redefined.
+// Rule sets compiled with Compile, ahead of any request, do not declare them.
+func WithRequestVars(ctx context.Context, vars map[string]any) context.Context {
+	merged := make(map[string]any, len(vars))
+	for name, val := range RequestVarsFrom(ctx) {
+		merged[name] = val
+	}
+	for name, val := range vars {
+		merged[name] = val
+	}
+	return context.WithValue(ctx, requestVarsKey{}, merged)
+}
+
+// RequestVarsFrom returns the request variables attached with WithRequestVars
+func RequestVarsFrom(ctx context.Context) map[string]any {
+	vars, _ := ctx.Value(requestVarsKey{}).(map[string]any)
+	return vars
+}
+
+// addRequestVars adds to fields the request variables of ctx they do not shadow
+func (v *Validator) addRequestVars(ctx context.Context, fields map[string]any) {
+	for name, val := range RequestVarsFrom(ctx) {
+		if _, ok := fields[name]; !ok {
+			fields[name] = celValue(reflect.ValueOf(val), FieldNamesGo)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package celvalidator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Request variables", func() {
+	md := ValidationMetadata{StructName: "Sample", Operation: "Create"}
+
+	It("declares the request variables of the context", func() {
+		v := NewValidator(WithGlobals(map[string]any{"tenantID": "global"}))
+		ctx := WithRequestVars(context.Background(), map[string]any{"requestor": "a@example.com", "roles": []string{"admin"}})
+		ctx = WithRequestVars(ctx, map[string]any{"tenantID": "acme", "Age": 1000})
+		rules := []RuleEntry{
+			{Rule: "Email == requestor", Enabled: true},
+			{Rule: "'admin' in roles && tenantID == 'acme'", Enabled: true},
+			{Rule: "Age < 100", Enabled: true},
+		}
+
+		results, err := v.ValidateContext(ctx, Sample{Age: 30, Email: "a@example.com"}, rules, md)
+		Expect(err).To(BeNil())
+		for _, res := range results {
+			Expect(res.Error).To(BeNil())
+			Expect(res.Passed).To(BeTrue(), res.Rule)
+		}
+
+		results, err = v.ValidateContext(ctx, Sample{Age: 30, Email: "b@example.com"}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeFalse())
+	})
+
+	It("does not declare request variables without them", func() {
+		results, err := NewValidator(WithPartialEval()).Validate(Sample{}, []RuleEntry{{Rule: "Email == requestor", Enabled: true}}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Error).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,235 @@
+package celvalidator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/functions"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// ResolverContext is passed to resolvers so lookups triggered by rules respect
+// the validation deadline and can authorize the caller
+type ResolverContext struct {
+	context.Context
+	Caller   string
+	Metadata ValidationMetadata
+}
+
+// ResolverFunc resolves a value for a rule from an external system, args being
+// the native values of the CEL arguments
+type ResolverFunc func(rc ResolverContext, args []any) (any, error)
+
+type resolver struct {
+	name  string
+	arity int
+	fn    ResolverFunc
+}
+
+func (r resolver) overloadID() string {
+	return r.name + "_resolver"
+}
+
+// WithResolver registers a CEL function name taking arity dyn arguments and
+// returning dyn, implemented by fn. Within a validation fn is called once per
+// distinct arguments, concurrent rules waiting for the first call.
+func WithResolver(name string, arity int, fn ResolverFunc) ValidatorOption {
+	return func(v *Validator) {
+		v.resolvers = append(v.resolvers, resolver{name: name, arity: arity, fn: fn})
+	}
+}
+
+type callerKey struct{}
+
+// WithCaller attaches the caller identity passed to resolvers
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFrom returns the caller identity attached with WithCaller
+func CallerFrom(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// resolverDeclarations declares the registered resolvers in the environment,
+// their implementations are bound per validation by resolverBindings
+func (v *Validator) resolverDeclarations() []cel.EnvOption {
+	opts := make([]cel.EnvOption, 0, len(v.resolvers))
+	for _, r := range v.resolvers {
+		args := make([]*cel.Type, r.arity)
+		for i := range args {
+			args[i] = cel.DynType
+		}
+		opts = append(opts, cel.Function(r.name, cel.Overload(r.overloadID(), args, cel.DynType)))
+	}
+	return opts
+}
+
+// resolverBindings binds the registered resolvers to the validation context
+func (v *Validator) resolverBindings(ctx context.Context, metadata ValidationMetadata) cel.ProgramOption {
+	rc := ResolverContext{Context: ctx, Caller: CallerFrom(ctx), Metadata: metadata}
+	// Calls with the same arguments are resolved once per validation
+	calls := newMemo()
+
+	overloads := make([]*functions.Overload, 0, len(v.resolvers))
+	for _, r := range v.resolvers {
+		call := func(args ...ref.Val) ref.Val {
+			native := make([]any, len(args))
+			for i, arg := range args {
+				native[i] = arg.Value()
+			}
+			return calls.do(fmt.Sprintf("%s%#v", r.name, native), func() ref.Val {
+				if err := rc.Err(); err != nil {
+					return types.WrapErr(err)
+				}
+				out, err := recovering(v.recover, func() (any, error) { return r.fn(rc, native) })
+				if err != nil {
+					return types.WrapErr(err)
+				}
+				return types.DefaultTypeAdapter.NativeToValue(out)
+			})
+		}
+
+		overload := &functions.Overload{Operator: r.overloadID()}
+		switch r.arity {
+		case 1:
+			overload.Unary = func(arg ref.Val) ref.Val { return call(arg) }
+		case 2:
+			overload.Binary = func(lhs, rhs ref.Val) ref.Val { return call(lhs, rhs) }
+		default:
+			overload.Function = call
+		}
+		overloads = append(overloads, overload)
+	}
+	return cel.Functions(overloads...)
+}
+
+// VariableResolver resolves external facts referenced by rules as prefix.name,
+// e.g. ext.account_balance. A nil value reports the fact as absent.
+type VariableResolver interface {
+	ResolveVariable(rc ResolverContext, name string) (any, error)
+}
+
+// VariableResolverFunc adapts a function to a VariableResolver
+type VariableResolverFunc func(rc ResolverContext, name string) (any, error)
+
+// ResolveVariable calls f
+func (f VariableResolverFunc) ResolveVariable(rc ResolverContext, name string) (any, error) {
+	return f(rc, name)
+}
+
+type variableResolver struct {
+	prefix   string
+	resolver VariableResolver
+}
+
+// WithVariableResolver declares prefix as a map variable whose entries are
+// resolved on first use by r and cached for the rest of the validation
+func WithVariableResolver(prefix string, r VariableResolver) ValidatorOption {
+	return func(v *Validator) {
+		v.variableResolvers = append(v.variableResolvers, variableResolver{prefix: prefix, resolver: r})
+	}
+}
+
+// variableResolverDeclarations declares the prefixes of the variable resolvers
+func (v *Validator) variableResolverDeclarations() []cel.EnvOption {
+	opts := make([]cel.EnvOption, 0, len(v.variableResolvers))
+	for _, r := range v.variableResolvers {
+		opts = append(opts, cel.Variable(r.prefix, cel.MapType(cel.StringType, cel.DynType)))
+	}
+	return opts
+}
+
+// withLazyVariables returns vars extended with a lazy lookup per variable
+// resolver prefix, vars itself is left untouched
+func (v *Validator) withLazyVariables(ctx context.Context, vars map[string]any, metadata ValidationMetadata) map[string]any {
+	if len(v.variableResolvers) == 0 {
+		return vars
+	}
+
+	extended := make(map[string]any, len(vars)+len(v.variableResolvers))
+	for name, val := range vars {
+		extended[name] = val
+	}
+	rc := ResolverContext{Context: ctx, Caller: CallerFrom(ctx), Metadata: metadata}
+	for _, r := range v.variableResolvers {
+		extended[r.prefix] = &lazyVariables{resolver: r.resolver, rc: rc, recover: v.recover, memo: newMemo()}
+	}
+	return extended
+}
+
+// lazyVariables is the CEL value of a variable resolver prefix, resolving
+// selected names on demand
+type lazyVariables struct {
+	resolver VariableResolver
+	rc       ResolverContext
+	recover  bool
+	memo     *memo
+}
+
+// lookup resolves name once, returning nil when the resolver reports it absent
+func (l *lazyVariables) lookup(key ref.Val) ref.Val {
+	name, ok := key.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(key)
+	}
+
+	return l.memo.do(string(name), func() ref.Val {
+		if err := l.rc.Err(); err != nil {
+			return types.WrapErr(err)
+		}
+		out, err := recovering(l.recover, func() (any, error) { return l.resolver.ResolveVariable(l.rc, string(name)) })
+		if err != nil {
+			return types.WrapErr(err)
+		}
+		if out == nil {
+			return nil
+		}
+		return types.DefaultTypeAdapter.NativeToValue(out)
+	})
+}
+
+// Get implements traits.Indexer
+func (l *lazyVariables) Get(key ref.Val) ref.Val {
+	val := l.lookup(key)
+	if val == nil {
+		return types.NewErr("no such key: %v", key)
+	}
+	return val
+}
+
+// IsSet implements traits.FieldTester so has() works on resolved names
+func (l *lazyVariables) IsSet(key ref.Val) ref.Val {
+	val := l.lookup(key)
+	if types.IsError(val) {
+		return val
+	}
+	return types.Bool(val != nil)
+}
+
+func (l *lazyVariables) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	return nil, fmt.Errorf("lazy variables cannot be converted to %v", typeDesc)
+}
+
+func (l *lazyVariables) ConvertToType(typeVal ref.Type) ref.Val {
+	if typeVal == types.TypeType {
+		return types.MapType
+	}
+	return types.NewErr("type conversion error from map to %v", typeVal)
+}
+
+func (l *lazyVariables) Equal(other ref.Val) ref.Val {
+	return types.Bool(l == other)
+}
+
+func (l *lazyVariables) Type() ref.Type {
+	return types.MapType
+}
+
+func (l *lazyVariables) Value() any {
+	return l
+}
@@ -0,0 +1,103 @@
+package celvalidator
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resolvers", func() {
+	obj := Sample{Email: "test@example.com"}
+
+	It("passes the validation context and caller to resolvers", func() {
+		var seen ResolverContext
+		v := NewValidator(WithResolver("isBlocked", 1, func(rc ResolverContext, args []any) (any, error) {
+			seen = rc
+			return args[0] == "blocked@example.com", nil
+		}))
+
+		ctx, cancel := context.WithTimeout(WithCaller(context.Background(), "svc-a"), time.Minute)
+		defer cancel()
+
+		results, err := v.ValidateContext(ctx, obj, []RuleEntry{{Rule: "!isBlocked(Email)", Enabled: true}}, ValidationMetadata{StructName: "Sample", Operation: "Create"})
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(seen.Caller).To(Equal("svc-a"))
+		Expect(seen.Metadata.Operation).To(Equal("Create"))
+		_, hasDeadline := seen.Deadline()
+		Expect(hasDeadline).To(BeTrue())
+	})
+
+	It("reports resolver errors and expired contexts on the result", func() {
+		v := NewValidator(WithResolver("lookup", 2, func(rc ResolverContext, args []any) (any, error) {
+			return nil, errors.New("db unavailable")
+		}))
+		rules := []RuleEntry{{Rule: "lookup(Email, 'x') == true", Enabled: true}}
+
+		results, err := v.Validate(obj, rules, ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+		Expect(results[0].Error).To(MatchError(ContainSubstring("db unavailable")))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		v = NewValidator(WithResolver("lookup", 2, func(rc ResolverContext, args []any) (any, error) {
+			cancel()
+			return nil, rc.Err()
+		}))
+		results, err = v.ValidateContext(ctx, obj, append(rules, RuleEntry{Rule: "Age == 0", Enabled: true}), ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Error).To(MatchError(context.Canceled))
+	})
+})
+
+var _ = Describe("Variable resolvers", func() {
+	obj := Sample{Email: "test@example.com", Age: 30}
+
+	It("resolves prefixed names lazily and once per validation", func() {
+		calls := map[string]int{}
+		v := NewValidator(WithVariableResolver("ext", VariableResolverFunc(func(rc ResolverContext, name string) (any, error) {
+			calls[name]++
+			switch name {
+			case "account_balance":
+				return 100, nil
+			case "unused":
+				Fail("unused names must not be resolved")
+			}
+			return nil, nil
+		})))
+
+		rules := []RuleEntry{
+			{Rule: "ext.account_balance > 50", Enabled: true},
+			{Rule: "ext.account_balance < Age * 10", Enabled: true},
+			{Rule: "!has(ext.frozen)", Enabled: true},
+			{Rule: "Age < 18 && ext.unused", Enabled: true},
+		}
+		results, err := v.Validate(obj, rules, ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(4))
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[1].Passed).To(BeTrue())
+		Expect(results[2].Passed).To(BeTrue())
+		Expect(calls).To(Equal(map[string]int{"account_balance": 1, "frozen": 1}))
+	})
+
+	It("reports resolver errors and absent names on the result", func() {
+		v := NewValidator(WithPartialEval(), WithVariableResolver("ext", VariableResolverFunc(func(rc ResolverContext, name string) (any, error) {
+			if name == "broken" {
+				return nil, errors.New("backend down")
+			}
+			return nil, nil
+		})))
+
+		results, err := v.Validate(obj, []RuleEntry{
+			{Rule: "ext.broken == 1", Enabled: true},
+			{Rule: "ext.missing == 1", Enabled: true},
+		}, ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+		Expect(results[0].Error).To(MatchError(ContainSubstring("backend down")))
+		Expect(results[1].Error).To(MatchError(ContainSubstring("no such key")))
+	})
+})
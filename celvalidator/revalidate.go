@@ -0,0 +1,91 @@
+package celvalidator
+
+import (
+	"context"
+	"sort"
+)
+
+// Revalidate re-runs the rules affected by a change of obj and merges their
+// results into previous, the report of validating obj before the change with
+// the same rules and metadata. A top-level rule is re-run, Then children
+// included, when its rule tree references a path of changedFields, matched as
+// by SelectRulesByFieldMask. The results of the other rules are kept, so
+// interactive editors validating on every keystroke only pay for the rules the
+// edit touched. The merged report is in rule order.
+func (v *Validator) Revalidate(
+	obj any,
+	previous ValidationReport,
+	changedFields []string,
+	rules []RuleEntry,
+	metadata ValidationMetadata,
+) (ValidationReport, error) {
+	return v.RevalidateContext(context.Background(), obj, previous, changedFields, rules, metadata)
+}
+
+// RevalidateContext is Revalidate with a context, like ValidateContext
+func (v *Validator) RevalidateContext(
+	ctx context.Context,
+	obj any,
+	previous ValidationReport,
+	changedFields []string,
+	rules []RuleEntry,
+	metadata ValidationMetadata,
+) (ValidationReport, error) {
+	// Rules left out are disabled rather than removed to keep their indexes
+	affected := make([]RuleEntry, len(rules))
+	rerun := map[int]bool{}
+	for i, entry := range rules {
+		affected[i] = entry
+		if entry.Enabled && chainMatchesMask(entry, changedFields) {
+			rerun[i] = true
+		} else {
+			affected[i].Enabled = false
+		}
+	}
+	if len(rerun) == 0 {
+		return previous, nil
+	}
+
+	results, err := v.ValidateContext(ctx, obj, affected, metadata)
+	return mergeRevalidation(previous, results, rerun, len(metadata.ParentIndexes)), err
+}
+
+// chainMatchesMask reports whether a rule or any of its Then children
+// references a path of mask
+func chainMatchesMask(entry RuleEntry, mask []string) bool {
+	if ruleMatchesMask(entry.Rule, mask) {
+		return true
+	}
+	for _, child := range entry.Then {
+		if chainMatchesMask(child, mask) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRevalidation replaces the results of the re-run top-level rules of
+// previous with results, depth being the depth of the top-level rules in the
+// rule tree
+func mergeRevalidation(previous, results []ValidationResult, rerun map[int]bool, depth int) ValidationReport {
+	merged := make(ValidationReport, 0, len(previous)+len(results))
+	for _, res := range previous {
+		if !rerun[topLevelIndex(res.Metadata, depth)] {
+			merged = append(merged, res)
+		}
+	}
+	merged = append(merged, results...)
+	sort.SliceStable(merged, func(i, j int) bool {
+		return topLevelIndex(merged[i].Metadata, depth) < topLevelIndex(merged[j].Metadata, depth)
+	})
+	return merged
+}
+
+// topLevelIndex returns the index of the top-level rule a result belongs to
+func topLevelIndex(m ValidationMetadata, depth int) int {
+	path := m.IndexPath()
+	if depth >= len(path) {
+		return -1
+	}
+	return path[depth]
+}
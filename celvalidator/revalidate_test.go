@@ -0,0 +1,61 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Revalidate", func() {
+	md := ValidationMetadata{StructName: "Sample", Operation: "Create", RuleIndex: -1}
+	rules := []RuleEntry{
+		{Rule: "Age >= 18", Enabled: true, Then: []RuleEntry{{Rule: "Email != ''", Enabled: true}}},
+		{Rule: "Active", Enabled: true},
+		{Rule: "Age < 100", Enabled: true},
+	}
+
+	It("re-runs the rules referencing changed fields and keeps the others", func() {
+		v := NewValidator()
+		obj := Sample{Age: 30, Email: "a@example.com"}
+		previous, err := v.Validate(obj, rules, md)
+		Expect(err).To(BeNil())
+		Expect(previous).To(HaveLen(4))
+
+		obj.Email = ""
+		report, err := v.Revalidate(obj, previous, []string{"Email"}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(report).To(HaveLen(4))
+		Expect(report[0].Rule).To(Equal("Age >= 18"))
+		Expect(report[1].Rule).To(Equal("Email != ''"))
+		Expect(report[1].Passed).To(BeFalse())
+		Expect(report[2]).To(Equal(previous[2]))
+		Expect(report[3]).To(Equal(previous[3]))
+
+		full, err := v.Validate(obj, rules, md)
+		Expect(err).To(BeNil())
+		Expect(EqualReports(report, full)).To(BeTrue())
+	})
+
+	It("drops Then results no longer evaluated", func() {
+		v := NewValidator()
+		obj := Sample{Age: 30, Email: "a@example.com", Active: true}
+		previous, err := v.Validate(obj, rules, md)
+		Expect(err).To(BeNil())
+
+		obj.Age = 10
+		report, err := v.Revalidate(obj, previous, []string{"Age"}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(report).To(HaveLen(3))
+		Expect(report[0].Passed).To(BeFalse())
+		Expect(report[1].Rule).To(Equal("Active"))
+		Expect(report[2].Rule).To(Equal("Age < 100"))
+	})
+
+	It("returns the previous report when no rule is affected", func() {
+		v := NewValidator()
+		previous, err := v.Validate(Sample{}, rules, md)
+		Expect(err).To(BeNil())
+		report, err := v.Revalidate(Sample{}, previous, []string{"Details"}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(report).To(Equal(ValidationReport(previous)))
+	})
+})
@@ -0,0 +1,162 @@
+package celvalidator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// RuleChangeKind classifies a semantic change of a rule between two rule sets
+type RuleChangeKind string
+
+const (
+	RuleAdded             RuleChangeKind = "added"
+	RuleRemoved           RuleChangeKind = "removed"
+	RuleEnabled           RuleChangeKind = "enabled"
+	RuleDisabled          RuleChangeKind = "disabled"
+	RuleExpressionChanged RuleChangeKind = "expression_changed"
+	RuleMessageChanged    RuleChangeKind = "message_changed"
+)
+
+// RuleChange is a semantic change of a rule. Before is zero for added rules
+// and After for removed rules.
+type RuleChange struct {
+	Kind       RuleChangeKind
+	StructName string
+	Operation  string
+	// Parents labels the rules the changed rule is a Then child of, outermost first
+	Parents []string
+	Before  RuleEntry
+	After   RuleEntry
+}
+
+// String formats the change on one line, + for added rules, - for removed
+// rules and ~ for changed rules, e.g. ~ User.Create adult: expression "Age >= 18" -> "Age >= 21"
+func (c RuleChange) String() string {
+	entry := c.After
+	if c.Kind == RuleRemoved {
+		entry = c.Before
+	}
+	location := c.StructName + "." + c.Operation
+	for _, parent := range c.Parents {
+		location += " > " + parent
+	}
+	label := ruleLabel(entry)
+
+	switch c.Kind {
+	case RuleAdded:
+		return fmt.Sprintf("+ %s %s", location, label)
+	case RuleRemoved:
+		return fmt.Sprintf("- %s %s", location, label)
+	case RuleExpressionChanged:
+		return fmt.Sprintf("~ %s %s: expression %q -> %q", location, label, c.Before.Rule, c.After.Rule)
+	case RuleMessageChanged:
+		if c.Before.FailureMessage == c.After.FailureMessage {
+			return fmt.Sprintf("~ %s %s: messages changed", location, label)
+		}
+		return fmt.Sprintf("~ %s %s: message %q -> %q", location, label, c.Before.FailureMessage, c.After.FailureMessage)
+	default:
+		return fmt.Sprintf("~ %s %s: %s", location, label, c.Kind)
+	}
+}
+
+// ruleLabel names a rule by ID, or by its expression for rules without one
+func ruleLabel(entry RuleEntry) string {
+	if entry.ID != "" {
+		return entry.ID
+	}
+	return fmt.Sprintf("%q", entry.Rule)
+}
+
+// DiffRuleSets returns the semantic changes from rule set a to b: rules added,
+// removed, enabled, disabled, or whose expression or messages changed. Rules
+// are matched within their struct, operation and parent rule by ID, or by
+// expression for rules without an ID, so changing the expression of a rule
+// without an ID is a removal and an addition. Changes are ordered by struct
+// and operation, then by position in b, removed rules last.
+func DiffRuleSets(a, b RuleSetMap) []RuleChange {
+	var changes []RuleChange
+	structs := slices.Sorted(maps.Keys(a))
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			structs = append(structs, name)
+		}
+	}
+	slices.Sort(structs)
+
+	for _, structName := range structs {
+		operations := slices.Sorted(maps.Keys(a[structName]))
+		for op := range b[structName] {
+			if _, ok := a[structName][op]; !ok {
+				operations = append(operations, op)
+			}
+		}
+		slices.Sort(operations)
+
+		for _, op := range operations {
+			changes = diffRuleEntries(changes, RuleChange{StructName: structName, Operation: op},
+				a[structName][op], b[structName][op])
+		}
+	}
+	return changes
+}
+
+// diffRuleEntries appends to changes the changes from entries a to b, at
+// carrying the struct, operation and parents of the entries
+func diffRuleEntries(changes []RuleChange, at RuleChange, a, b []RuleEntry) []RuleChange {
+	before := make(map[string]RuleEntry, len(a))
+	for _, entry := range a {
+		before[entry.key()] = entry
+	}
+	matched := make(map[string]bool, len(b))
+
+	for _, next := range b {
+		prev, ok := before[next.key()]
+		if !ok {
+			changes = append(changes, at.with(RuleAdded, RuleEntry{}, next))
+			continue
+		}
+		matched[next.key()] = true
+
+		if prev.Rule != next.Rule {
+			changes = append(changes, at.with(RuleExpressionChanged, prev, next))
+		}
+		if prev.Enabled != next.Enabled {
+			kind := RuleDisabled
+			if next.Enabled {
+				kind = RuleEnabled
+			}
+			changes = append(changes, at.with(kind, prev, next))
+		}
+		if !sameMessages(prev, next) {
+			changes = append(changes, at.with(RuleMessageChanged, prev, next))
+		}
+
+		child := at
+		child.Parents = append(slices.Clip(at.Parents), ruleLabel(next))
+		changes = diffRuleEntries(changes, child, prev.Then, next.Then)
+	}
+
+	for _, prev := range a {
+		if !matched[prev.key()] {
+			changes = append(changes, at.with(RuleRemoved, prev, RuleEntry{}))
+		}
+	}
+	return changes
+}
+
+// with returns the change of kind from before to after at the location of c
+func (c RuleChange) with(kind RuleChangeKind, before, after RuleEntry) RuleChange {
+	c.Kind = kind
+	c.Before = before
+	c.After = after
+	return c
+}
+
+// sameMessages compares the messages reported by two rules
+func sameMessages(a, b RuleEntry) bool {
+	return a.FailureMessage == b.FailureMessage &&
+		a.SuccessMessage == b.SuccessMessage &&
+		a.MessageExpression == b.MessageExpression &&
+		maps.Equal(a.Messages, b.Messages)
+}
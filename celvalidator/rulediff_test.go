@@ -0,0 +1,61 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffRuleSets", func() {
+	before := RuleSetMap{
+		"User": {
+			"Default": {
+				{ID: "adult", Rule: "Age >= 18", Enabled: true, FailureMessage: "too young", Then: []RuleEntry{
+					{Rule: "Name != ''", Enabled: true},
+				}},
+				{Rule: "Email != ''", Enabled: false},
+			},
+			"Delete": {{Rule: "!Locked", Enabled: true}},
+		},
+	}
+
+	It("reports no changes for equal rule sets", func() {
+		Expect(DiffRuleSets(before, copyRuleSetMap(before))).To(BeEmpty())
+	})
+
+	It("reports semantic changes in order", func() {
+		after := copyRuleSetMap(before)
+		after["User"]["Default"][0].FailureMessage = "must be an adult"
+		after["User"]["Default"][0].Then[0].Rule = "Name.size() > 1"
+		after["User"]["Default"][1].Enabled = true
+		delete(after["User"], "Delete")
+		after["Order"] = map[string][]RuleEntry{"Default": {{ID: "total", Rule: "Total > 0", Enabled: true}}}
+
+		changes := DiffRuleSets(before, after)
+		kinds := make([]RuleChangeKind, len(changes))
+		lines := make([]string, len(changes))
+		for i, change := range changes {
+			kinds[i] = change.Kind
+			lines[i] = change.String()
+		}
+		Expect(kinds).To(Equal([]RuleChangeKind{
+			RuleAdded, RuleMessageChanged, RuleAdded, RuleRemoved, RuleEnabled, RuleRemoved,
+		}))
+		Expect(lines).To(Equal([]string{
+			`+ Order.Default total`,
+			`~ User.Default adult: message "too young" -> "must be an adult"`,
+			`+ User.Default > adult "Name.size() > 1"`,
+			`- User.Default > adult "Name != ''"`,
+			`~ User.Default "Email != ''": enabled`,
+			`- User.Delete "!Locked"`,
+		}))
+		Expect(changes[2].Parents).To(Equal([]string{"adult"}))
+	})
+
+	It("reports changes of localized messages", func() {
+		after := copyRuleSetMap(before)
+		after["User"]["Default"][0].Messages = map[string]string{"fr": "trop jeune"}
+		changes := DiffRuleSets(before, after)
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].String()).To(Equal("~ User.Default adult: messages changed"))
+	})
+})
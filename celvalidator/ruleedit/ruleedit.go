@@ -0,0 +1,400 @@
+// Package ruleedit edits rule files in place, keeping comments, key order and
+// formatting. Edits are applied to the file text where possible so a written
+// file differs from the original only on the edited lines.
+package ruleedit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gdbranco/celvalidator"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrRuleNotFound is returned when no rule matches a lookup
+var ErrRuleNotFound = errors.New("rule not found")
+
+// Document is a rule file open for editing
+type Document struct {
+	src  []byte
+	root yaml.Node
+}
+
+// Parse parses rule file content for editing
+func Parse(data []byte) (*Document, error) {
+	d := &Document{src: append([]byte(nil), data...)}
+	if err := d.parse(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Load reads a rule file for editing
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading rule file: %w", err)
+	}
+	return Parse(data)
+}
+
+func (d *Document) parse() error {
+	d.root = yaml.Node{}
+	if err := yaml.Unmarshal(d.src, &d.root); err != nil {
+		return fmt.Errorf("unmarshalling YAML: %w", err)
+	}
+	return nil
+}
+
+// Bytes returns the edited file content
+func (d *Document) Bytes() []byte {
+	return append([]byte(nil), d.src...)
+}
+
+// Save writes the edited file content to path
+func (d *Document) Save(path string) error {
+	return os.WriteFile(path, d.src, 0o644)
+}
+
+// RuleFile parses the edited content like celvalidator.ParseRuleFileYAML
+func (d *Document) RuleFile(opts ...celvalidator.LoadOption) (*celvalidator.RuleFile, error) {
+	return celvalidator.ParseRuleFileYAML(d.src, opts...)
+}
+
+// Rules returns the top-level rules of a struct operation
+func (d *Document) Rules(structName, operation string) []*Rule {
+	seq := d.sequence(structName, operation)
+	if seq == nil {
+		return nil
+	}
+	rules := make([]*Rule, len(seq.Content))
+	for i := range seq.Content {
+		rules[i] = &Rule{doc: d, structName: structName, operation: operation, path: []int{i}}
+	}
+	return rules
+}
+
+// Find returns the rule of a struct operation with the given ID, searching Then children
+func (d *Document) Find(structName, operation, id string) (*Rule, error) {
+	if r := find(d.Rules(structName, operation), id); r != nil {
+		return r, nil
+	}
+	return nil, fmt.Errorf("%w: %s.%s %s", ErrRuleNotFound, structName, operation, id)
+}
+
+func find(rules []*Rule, id string) *Rule {
+	for _, r := range rules {
+		if r.ID() == id {
+			return r
+		}
+		if child := find(r.Then(), id); child != nil {
+			return child
+		}
+	}
+	return nil
+}
+
+// sequence returns the rule sequence node of a struct operation
+func (d *Document) sequence(structName, operation string) *yaml.Node {
+	if len(d.root.Content) == 0 {
+		return nil
+	}
+	structNode := mappingValue(d.root.Content[0], structName)
+	if structNode == nil {
+		return nil
+	}
+	seq := mappingValue(structNode, operation)
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return seq
+}
+
+// Rule is a rule entry of a document. It is addressed by position, so it stays
+// valid across edits that do not add or remove rules.
+type Rule struct {
+	doc        *Document
+	structName string
+	operation  string
+	// path holds the index of the rule in its sequence, then of each Then child
+	path []int
+}
+
+// node returns the mapping node of the rule in the current document
+func (r *Rule) node() *yaml.Node {
+	seq := r.doc.sequence(r.structName, r.operation)
+	var node *yaml.Node
+	for i, idx := range r.path {
+		if i > 0 {
+			seq = mappingValue(node, "then")
+		}
+		if seq == nil || seq.Kind != yaml.SequenceNode || idx >= len(seq.Content) {
+			return nil
+		}
+		node = seq.Content[idx]
+	}
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	return node
+}
+
+func (r *Rule) scalar(key string) string {
+	node := r.node()
+	if node == nil {
+		return ""
+	}
+	if v := mappingValue(node, key); v != nil {
+		return v.Value
+	}
+	return ""
+}
+
+// ID returns the id of the rule
+func (r *Rule) ID() string {
+	return r.scalar("id")
+}
+
+// Expression returns the CEL expression of the rule
+func (r *Rule) Expression() string {
+	return r.scalar("rule")
+}
+
+// Enabled reports whether the rule is enabled
+func (r *Rule) Enabled() bool {
+	enabled, _ := strconv.ParseBool(r.scalar("enabled"))
+	return enabled
+}
+
+// Message returns the failure message of the rule
+func (r *Rule) Message() string {
+	return r.scalar("message")
+}
+
+// Then returns the Then children of the rule
+func (r *Rule) Then() []*Rule {
+	node := r.node()
+	if node == nil {
+		return nil
+	}
+	seq := mappingValue(node, "then")
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return nil
+	}
+	children := make([]*Rule, len(seq.Content))
+	for i := range seq.Content {
+		path := append(append([]int(nil), r.path...), i)
+		children[i] = &Rule{doc: r.doc, structName: r.structName, operation: r.operation, path: path}
+	}
+	return children
+}
+
+// SetEnabled enables or disables the rule
+func (r *Rule) SetEnabled(enabled bool) error {
+	return r.set("enabled", strconv.FormatBool(enabled), "!!bool")
+}
+
+// SetMessage sets the failure message of the rule
+func (r *Rule) SetMessage(message string) error {
+	return r.set("message", message, "!!str")
+}
+
+// SetSeverity sets the severity of the rule
+func (r *Rule) SetSeverity(severity celvalidator.Severity) error {
+	return r.set("severity", string(severity), "!!str")
+}
+
+// set sets a scalar key of the rule, editing the text in place when the layout
+// allows it and re-encoding the document otherwise
+func (r *Rule) set(key, value, tag string) error {
+	node := r.node()
+	if node == nil {
+		return fmt.Errorf("%w: %s.%s %v", ErrRuleNotFound, r.structName, r.operation, r.path)
+	}
+
+	d := r.doc
+	if v := mappingValue(node, key); v != nil {
+		if v.Kind != yaml.ScalarNode {
+			return fmt.Errorf("%s is not a scalar", key)
+		}
+		if v.Value == value {
+			return nil
+		}
+		if node.Style&yaml.FlowStyle == 0 {
+			if start, end, ok := d.scalarSpan(v); ok {
+				if text, ok := renderScalar(value, tag, v.Style); ok {
+					return d.splice(start, end, text)
+				}
+			}
+		}
+		v.Value, v.Tag, v.Style = value, tag, 0
+		return d.reencode()
+	}
+
+	if node.Style&yaml.FlowStyle == 0 && len(node.Content) >= 2 {
+		first, firstValue := node.Content[0], node.Content[1]
+		if _, end, ok := d.scalarSpan(firstValue); ok {
+			if text, ok := renderScalar(value, tag, 0); ok {
+				pos := d.lineEnd(end)
+				indent := bytes.Repeat([]byte(" "), first.Column-1)
+				line := append(append([]byte(d.newline()), indent...), key+": "+text...)
+				return d.splice(pos, pos, string(line))
+			}
+		}
+	}
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value},
+	)
+	return d.reencode()
+}
+
+// splice replaces src[start:end] with text and re-parses the document
+func (d *Document) splice(start, end int, text string) error {
+	src := make([]byte, 0, len(d.src)-(end-start)+len(text))
+	src = append(src, d.src[:start]...)
+	src = append(src, text...)
+	src = append(src, d.src[end:]...)
+
+	prev := d.src
+	d.src = src
+	if err := d.parse(); err != nil {
+		d.src = prev
+		_ = d.parse()
+		return err
+	}
+	return nil
+}
+
+// reencode rewrites the document from its node tree, the fallback for edits
+// that cannot be made in place
+func (d *Document) reencode() error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&d.root); err != nil {
+		return fmt.Errorf("marshalling YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("marshalling YAML: %w", err)
+	}
+	d.src = buf.Bytes()
+	return d.parse()
+}
+
+// scalarSpan returns the byte span of a single-line scalar in src
+func (d *Document) scalarSpan(node *yaml.Node) (int, int, bool) {
+	if node.Kind != yaml.ScalarNode || node.Line < 1 {
+		return 0, 0, false
+	}
+	lineStart := 0
+	for line := 1; line < node.Line; line++ {
+		i := bytes.IndexByte(d.src[lineStart:], '\n')
+		if i < 0 {
+			return 0, 0, false
+		}
+		lineStart += i + 1
+	}
+	lineEnd := d.lineEnd(lineStart)
+	line := d.src[lineStart:lineEnd]
+
+	// Columns count characters
+	start := 0
+	for col := 1; col < node.Column && start < len(line); col++ {
+		_, size := utf8.DecodeRune(line[start:])
+		start += size
+	}
+
+	var end int
+	switch node.Style {
+	case yaml.DoubleQuotedStyle:
+		end = quotedEnd(line, start, '"')
+	case yaml.SingleQuotedStyle:
+		end = quotedEnd(line, start, '\'')
+	case 0, yaml.TaggedStyle:
+		end = len(line)
+		if i := bytes.Index(line[start:], []byte(" #")); i >= 0 {
+			end = start + i
+		}
+		end = start + len(bytes.TrimRight(line[start:end], " \t\r"))
+		if string(line[start:end]) != node.Value {
+			return 0, 0, false
+		}
+	default:
+		return 0, 0, false
+	}
+	if end < 0 {
+		return 0, 0, false
+	}
+	return lineStart + start, lineStart + end, true
+}
+
+// quotedEnd returns the offset after the closing quote of a scalar starting at start
+func quotedEnd(line []byte, start int, quote byte) int {
+	for i := start + 1; i < len(line); i++ {
+		switch {
+		case quote == '"' && line[i] == '\\':
+			i++
+		case line[i] == quote && quote == '\'' && i+1 < len(line) && line[i+1] == '\'':
+			i++
+		case line[i] == quote:
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// lineEnd returns the offset of the line break ending the line containing pos
+func (d *Document) lineEnd(pos int) int {
+	i := bytes.IndexByte(d.src[pos:], '\n')
+	if i < 0 {
+		return len(d.src)
+	}
+	end := pos + i
+	if end > 0 && d.src[end-1] == '\r' {
+		end--
+	}
+	return end
+}
+
+func (d *Document) newline() string {
+	if bytes.Contains(d.src, []byte("\r\n")) {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// renderScalar renders value as a single-line YAML scalar, keeping the quoting
+// style of the value it replaces
+func renderScalar(value, tag string, style yaml.Style) (string, bool) {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+	if tag == "!!str" && (style == yaml.DoubleQuotedStyle || style == yaml.SingleQuotedStyle) {
+		node.Style = style
+	}
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return "", false
+	}
+	text := string(bytes.TrimSuffix(out, []byte("\n")))
+	if bytes.ContainsAny([]byte(text), "\n\r") {
+		return "", false
+	}
+	return text, true
+}
+
+// mappingValue returns the value node of key in a YAML mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
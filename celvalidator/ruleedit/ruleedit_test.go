@@ -0,0 +1,133 @@
+package ruleedit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdbranco/celvalidator"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRuleEdit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RuleEdit Suite")
+}
+
+const rulesYAML = `# Rules owned by the accounts team
+User:
+  default_operation: Create
+  Default:
+    # adults only
+    - rule: "Age >= 18"
+      id: adult
+      enabled: true   # reviewed 2024
+      message: 'must be an adult'
+      then:
+        - id: email
+          rule: "Email != ''"
+          enabled: false
+  Create:
+    - rule: "Name != ''"
+      enabled: true
+`
+
+var _ = Describe("Document", func() {
+	var doc *Document
+
+	BeforeEach(func() {
+		var err error
+		doc, err = Parse([]byte(rulesYAML))
+		Expect(err).To(BeNil())
+	})
+
+	It("reads rules and Then children", func() {
+		rules := doc.Rules("User", "Default")
+		Expect(rules).To(HaveLen(1))
+		Expect(rules[0].Expression()).To(Equal("Age >= 18"))
+		Expect(rules[0].Enabled()).To(BeTrue())
+		Expect(rules[0].Message()).To(Equal("must be an adult"))
+
+		email, err := doc.Find("User", "Default", "email")
+		Expect(err).To(BeNil())
+		Expect(email.Expression()).To(Equal("Email != ''"))
+		Expect(email.Enabled()).To(BeFalse())
+
+		_, err = doc.Find("User", "Default", "missing")
+		Expect(err).To(MatchError(ErrRuleNotFound))
+	})
+
+	It("edits values in place, keeping comments and quoting", func() {
+		adult, err := doc.Find("User", "Default", "adult")
+		Expect(err).To(BeNil())
+		Expect(adult.SetEnabled(false)).To(Succeed())
+		Expect(adult.SetMessage("must be 18 or older")).To(Succeed())
+
+		email, err := doc.Find("User", "Default", "email")
+		Expect(err).To(BeNil())
+		Expect(email.SetEnabled(true)).To(Succeed())
+
+		expected := `# Rules owned by the accounts team
+User:
+  default_operation: Create
+  Default:
+    # adults only
+    - rule: "Age >= 18"
+      id: adult
+      enabled: false   # reviewed 2024
+      message: 'must be 18 or older'
+      then:
+        - id: email
+          rule: "Email != ''"
+          enabled: true
+  Create:
+    - rule: "Name != ''"
+      enabled: true
+`
+		Expect(string(doc.Bytes())).To(Equal(expected))
+	})
+
+	It("adds missing keys next to the first key of the rule", func() {
+		rule := doc.Rules("User", "Create")[0]
+		Expect(rule.SetMessage("name is required")).To(Succeed())
+		Expect(rule.SetSeverity(celvalidator.SeverityWarning)).To(Succeed())
+
+		Expect(string(doc.Bytes())).To(ContainSubstring(`  Create:
+    - rule: "Name != ''"
+      severity: warning
+      message: name is required
+      enabled: true
+`))
+
+		file, err := doc.RuleFile()
+		Expect(err).To(BeNil())
+		entry := file.Rules["User"]["Create"][0]
+		Expect(entry.FailureMessage).To(Equal("name is required"))
+		Expect(entry.Severity).To(Equal(celvalidator.SeverityWarning))
+	})
+
+	It("re-encodes rules it cannot edit in place", func() {
+		doc, err := Parse([]byte("User:\n  Default:\n    - {rule: \"Age > 1\", enabled: true}\n"))
+		Expect(err).To(BeNil())
+		Expect(doc.Rules("User", "Default")[0].SetEnabled(false)).To(Succeed())
+
+		file, err := doc.RuleFile()
+		Expect(err).To(BeNil())
+		Expect(file.Rules["User"]["Default"][0].Enabled).To(BeFalse())
+	})
+
+	It("saves the edited file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "rules.yaml")
+		Expect(os.WriteFile(path, []byte(rulesYAML), 0o644)).To(Succeed())
+
+		doc, err := Load(path)
+		Expect(err).To(BeNil())
+		Expect(doc.Rules("User", "Create")[0].SetEnabled(false)).To(Succeed())
+		Expect(doc.Save(path)).To(Succeed())
+
+		rules, err := celvalidator.LoadRuleSetMapFromYAML(path)
+		Expect(err).To(BeNil())
+		Expect(rules["User"]["Create"][0].Enabled).To(BeFalse())
+	})
+})
@@ -4,23 +4,202 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// defaultOperationKey is the reserved per-struct key naming the operation used
+// when none is given
+const defaultOperationKey = "default_operation"
+
+// RuleFile is the parsed content of a rule file
+type RuleFile struct {
+	Rules             RuleSetMap
+	DefaultOperations map[string]string
+	// Versions maps struct versions to the struct whose rules they are validated with
+	Versions map[string]VersionMapping
+	// Validator is the validator: block of the file, nil when absent
+	Validator *ValidatorConfig
+	// Deprecations lists the deprecated rules of the file, to warn about
+	Deprecations []RuleDeprecation
+}
+
+// LoadOption configures how rule files are loaded
+type LoadOption func(*loadConfig)
+
+type loadConfig struct {
+	provenance bool
+	file       string
+	version    string
+	now        time.Time
+	// strictScalars rejects coerced boolean and numeric values, see WithStrictScalars
+	strictScalars bool
+}
+
+// WithProvenance records on every loaded rule its source file, YAML path, line
+// and the given version (e.g. commit), which is then reported on its results
+func WithProvenance(version string) LoadOption {
+	return func(c *loadConfig) {
+		c.provenance = true
+		c.version = version
+	}
+}
+
+// WithSourceFile sets the file name recorded in rule sources
+func WithSourceFile(name string) LoadOption {
+	return func(c *loadConfig) {
+		c.file = name
+	}
+}
+
 // LoadRuleSetMapFromYAML loads the nested rule set YAML
-func LoadRuleSetMapFromYAML(path string) (RuleSetMap, error) {
+func LoadRuleSetMapFromYAML(path string, opts ...LoadOption) (RuleSetMap, error) {
+	file, err := LoadRuleFileFromYAML(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return file.Rules, nil
+}
+
+// LoadRuleFileFromYAML loads the nested rule set YAML along with per-struct settings
+func LoadRuleFileFromYAML(path string, opts ...LoadOption) (*RuleFile, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading rule file: %w", err)
 	}
+	return ParseRuleFileYAML(data, append([]LoadOption{WithSourceFile(path)}, opts...)...)
+}
 
-	var rules RuleSetMap
-	if err := yaml.Unmarshal(data, &rules); err != nil {
+// ParseRuleFileYAML parses rule file content. Deprecated rules are listed in
+// Deprecations, and rules past their sunset date fail with ErrRuleSunset.
+func ParseRuleFileYAML(data []byte, opts ...LoadOption) (*RuleFile, error) {
+	cfg := &loadConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("unmarshalling YAML: %w", err)
 	}
 
-	return rules, nil
+	file := &RuleFile{
+		Rules:             RuleSetMap{},
+		DefaultOperations: map[string]string{},
+		Versions:          map[string]VersionMapping{},
+	}
+	for structName, structNode := range raw {
+		if structName == validatorConfigKey {
+			file.Validator = &ValidatorConfig{}
+			if cfg.strictScalars {
+				if err := checkScalars(&structNode, reflect.TypeOf(file.Validator), structName); err != nil {
+					return nil, err
+				}
+			}
+			if err := structNode.Decode(file.Validator); err != nil {
+				return nil, fmt.Errorf("unmarshalling %s: %w", structName, err)
+			}
+			continue
+		}
+
+		var entries map[string]yaml.Node
+		if err := structNode.Decode(&entries); err != nil {
+			return nil, fmt.Errorf("unmarshalling %s: %w", structName, err)
+		}
+		file.Rules[structName] = map[string][]RuleEntry{}
+		for key, node := range entries {
+			if key == defaultOperationKey {
+				var op string
+				if err := node.Decode(&op); err != nil {
+					return nil, fmt.Errorf("unmarshalling %s.%s: %w", structName, key, err)
+				}
+				file.DefaultOperations[structName] = op
+				continue
+			}
+			if key == versionsKey {
+				var versions map[string]map[string]string
+				if err := node.Decode(&versions); err != nil {
+					return nil, fmt.Errorf("unmarshalling %s.%s: %w", structName, key, err)
+				}
+				for version, fields := range versions {
+					file.Versions[version] = VersionMapping{Logical: structName, Fields: fields}
+				}
+				continue
+			}
+
+			var rules []RuleEntry
+			if cfg.strictScalars {
+				if err := checkScalars(&node, reflect.TypeOf(rules), structName+"."+key); err != nil {
+					return nil, err
+				}
+			}
+			if err := node.Decode(&rules); err != nil {
+				return nil, fmt.Errorf("unmarshalling %s.%s: %w", structName, key, err)
+			}
+			if cfg.provenance {
+				setRuleSources(rules, &node, structName+"."+key, cfg)
+			}
+			file.Rules[structName][key] = rules
+		}
+	}
+
+	now := cfg.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	deprecations, err := ruleDeprecations(file.Rules, now)
+	if err != nil {
+		return nil, err
+	}
+	file.Deprecations = deprecations
+	return file, nil
+}
+
+// UnmarshalYAML decodes a rule entry, recording whether an override sets
+// enabled so overrides of only the messages keep the child enabled
+func (r *RuleEntry) UnmarshalYAML(node *yaml.Node) error {
+	type plain RuleEntry
+	if err := node.Decode((*plain)(r)); err != nil {
+		return err
+	}
+	r.keepEnabled = r.Override != "" && mappingValue(node, "enabled") == nil
+	return nil
+}
+
+// setRuleSources records the file, YAML path and line of every rule decoded from seq
+func setRuleSources(rules []RuleEntry, seq *yaml.Node, path string, cfg *loadConfig) {
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return
+	}
+
+	for i := range rules {
+		if i >= len(seq.Content) {
+			return
+		}
+		node := seq.Content[i]
+		rulePath := fmt.Sprintf("%s[%d]", path, i)
+		rules[i].Source = RuleSource{
+			File:    cfg.file,
+			Path:    rulePath,
+			Line:    node.Line,
+			Version: cfg.version,
+		}
+		setRuleSources(rules[i].Then, mappingValue(node, "then"), rulePath+".then", cfg)
+	}
+}
+
+// mappingValue returns the value node of key in a YAML mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
 }
 
 // StructName returns the type name of a struct (without pointer or package prefix)
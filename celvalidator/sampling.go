@@ -0,0 +1,30 @@
+package celvalidator
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+)
+
+// sampledOut reports whether the advisory rule entry is left out of this
+// evaluation by its SampleRate. Objects with an ID are sampled by a hash of
+// the ID and the rule, so an object keeps running the same rules; others are
+// sampled at random. Rules of error severity are never sampled out.
+func (e *evaluation) sampledOut(entry RuleEntry, metadata ValidationMetadata) bool {
+	if entry.SampleRate <= 0 || entry.SampleRate >= 1 || entry.severity() == SeverityError {
+		return false
+	}
+	return sampleFraction(metadata.ObjectID, entry.key()) >= entry.SampleRate
+}
+
+// sampleFraction returns a fraction in [0, 1), derived from objectID and the
+// rule key when objectID is set
+func sampleFraction(objectID, key string) float64 {
+	if objectID == "" {
+		return rand.Float64()
+	}
+	h := fnv.New64a()
+	h.Write([]byte(objectID))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return float64(h.Sum64()>>11) / (1 << 53)
+}
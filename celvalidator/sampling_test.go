@@ -0,0 +1,73 @@
+package celvalidator
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rule sampling", func() {
+	md := ValidationMetadata{StructName: "Sample", Operation: "Default", RuleIndex: -1}
+	advisory := RuleEntry{ID: "expensive", Rule: "Age < 0", Enabled: true, Severity: SeverityWarning, SampleRate: 0.5}
+
+	It("samples advisory rules deterministically by object ID", func() {
+		sampled := 0
+		for n := 0; n < 200; n++ {
+			metadata := MetadataBuilderFrom(md).WithObjectID(fmt.Sprintf("obj-%d", n)).Build()
+			first, err := NewValidator().Validate(Sample{Age: 30}, []RuleEntry{advisory}, metadata)
+			Expect(err).To(BeNil())
+			again, err := NewValidator().Validate(Sample{Age: 30}, []RuleEntry{advisory}, metadata)
+			Expect(err).To(BeNil())
+			Expect(again[0].Outcome).To(Equal(first[0].Outcome))
+
+			if first[0].Outcome == OutcomeSampledOut {
+				Expect(first[0].Passed).To(BeTrue())
+			} else {
+				sampled++
+				Expect(first[0].Outcome).To(Equal(OutcomeFailed))
+			}
+		}
+		Expect(sampled).To(BeNumerically("~", 100, 30))
+	})
+
+	It("always runs blocking rules and rules without a sample rate", func() {
+		blocking := advisory
+		blocking.Severity = ""
+		unsampled := advisory
+		unsampled.ID = "cheap"
+		unsampled.SampleRate = 0
+		for n := 0; n < 20; n++ {
+			results, err := NewValidator().Validate(Sample{Age: 30}, []RuleEntry{blocking, unsampled}, md)
+			Expect(err).To(BeNil())
+			Expect(results[0].Outcome).To(Equal(OutcomeFailed))
+			Expect(results[1].Outcome).To(Equal(OutcomeFailed))
+		}
+	})
+
+	It("leaves sampled out rules out of decisions and headers", func() {
+		rare := advisory
+		rare.SampleRate = 1e-12
+		report := ValidationReport{
+			{Rule: "Age > 0", Passed: true, Outcome: OutcomePassed},
+		}
+		results, err := NewValidator().Validate(Sample{Age: 30}, []RuleEntry{rare}, md)
+		Expect(err).To(BeNil())
+		Expect(results[0].Outcome).To(Equal(OutcomeSampledOut))
+		report = append(report, results...)
+
+		decision := report.Decide(AllMustPass())
+		Expect(decision.Valid).To(BeTrue())
+		Expect(decision.Passed).To(Equal(1))
+
+		risk := append(report, ValidationResult{Rule: "Email != ''", Outcome: OutcomeFailed}).RiskScore()
+		Expect(risk.MaxScore).To(Equal(2.0))
+		Expect(risk.Normalized()).To(Equal(0.5))
+		Expect(risk.Contributions).To(HaveLen(2))
+
+		h := http.Header{}
+		SetSummaryHeaders(h, report)
+		Expect(h.Get(HeaderFailedCount)).To(Equal("0"))
+	})
+})
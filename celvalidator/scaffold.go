@@ -0,0 +1,115 @@
+package celvalidator
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// ScaffoldField is a field listed in a starter rule file, Kind being one of
+// string, int, uint, double, bool, list, map or other
+type ScaffoldField struct {
+	Name string
+	Kind string
+}
+
+// ScaffoldFields lists the fields of obj's struct type as exposed to rules,
+// nested structs being flattened to dotted names
+func ScaffoldFields(obj any) []ScaffoldField {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	return scaffoldStruct("", t, map[reflect.Type]bool{})
+}
+
+func scaffoldStruct(prefix string, t reflect.Type, visiting map[reflect.Type]bool) []ScaffoldField {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var fields []ScaffoldField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		name := prefix + field.Name
+		if ft.Kind() == reflect.Struct && ft != ratType && !isMoneyType(ft) && !visiting[ft] {
+			fields = append(fields, scaffoldStruct(name+".", ft, visiting)...)
+			continue
+		}
+		fields = append(fields, ScaffoldField{Name: name, Kind: scaffoldKind(ft.Kind())})
+	}
+	return fields
+}
+
+func scaffoldKind(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "double"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map:
+		return "map"
+	}
+	return "other"
+}
+
+// exampleRule returns a commented-out example rule for a field kind
+func exampleRule(f ScaffoldField) (rule, message string) {
+	switch f.Kind {
+	case "string":
+		return fmt.Sprintf("%s != ''", f.Name), fmt.Sprintf("%s is required", f.Name)
+	case "int", "double":
+		return fmt.Sprintf("%s > 0", f.Name), fmt.Sprintf("%s must be positive", f.Name)
+	case "uint":
+		return fmt.Sprintf("%s > 0u", f.Name), fmt.Sprintf("%s must be positive", f.Name)
+	case "bool":
+		return f.Name, fmt.Sprintf("%s must be set", f.Name)
+	case "list", "map":
+		return fmt.Sprintf("size(%s) > 0", f.Name), fmt.Sprintf("%s must not be empty", f.Name)
+	}
+	return "", ""
+}
+
+// WriteScaffold writes a starter rule file for typeName listing every field
+// with commented-out example rules, the file loads as is without any rule
+func WriteScaffold(w io.Writer, typeName string, fields []ScaffoldField) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf("# Starter rules for %s, uncomment and adapt the examples\n", typeName)
+	printf("%s:\n", typeName)
+	printf("  Default:\n")
+	for _, f := range fields {
+		printf("    # %s (%s)\n", f.Name, f.Kind)
+		rule, message := exampleRule(f)
+		if rule == "" {
+			continue
+		}
+		printf("    # - rule: %q\n", rule)
+		printf("    #   enabled: true\n")
+		printf("    #   message: %q\n", message)
+	}
+	return err
+}
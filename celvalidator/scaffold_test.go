@@ -0,0 +1,47 @@
+package celvalidator
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scaffold", func() {
+	type node struct {
+		Next *node
+	}
+	type account struct {
+		Name    string
+		Age     int
+		Balance Money
+		Tags    []string
+		Address *Address
+		Node    node
+		hidden  string
+	}
+
+	It("lists fields as exposed to rules", func() {
+		Expect(ScaffoldFields(&account{})).To(Equal([]ScaffoldField{
+			{Name: "Name", Kind: "string"},
+			{Name: "Age", Kind: "int"},
+			{Name: "Balance", Kind: "other"},
+			{Name: "Tags", Kind: "list"},
+			{Name: "Address.City", Kind: "string"},
+			{Name: "Address.Country", Kind: "string"},
+			{Name: "Address.Zip", Kind: "int"},
+			{Name: "Node.Next", Kind: "other"},
+		}))
+	})
+
+	It("writes a loadable starter rule file", func() {
+		var buf bytes.Buffer
+		Expect(WriteScaffold(&buf, "Account", ScaffoldFields(account{}))).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring(`# - rule: "Name != ''"`))
+		Expect(buf.String()).To(ContainSubstring(`# - rule: "size(Tags) > 0"`))
+
+		file, err := ParseRuleFileYAML(buf.Bytes())
+		Expect(err).To(BeNil())
+		Expect(file.Rules).To(HaveKey("Account"))
+	})
+})
@@ -0,0 +1,155 @@
+package celvalidator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrAmbiguousScalar is returned when loading with WithStrictScalars a boolean
+// or numeric field whose YAML value is only coerced to its type
+var ErrAmbiguousScalar = errors.New("ambiguous scalar")
+
+// WithStrictScalars rejects with ErrAmbiguousScalar boolean fields not written
+// true or false, e.g. enabled: yes, on or "true", and numeric fields written as
+// strings, so rule files behave the same whatever YAML version a tool assumes
+func WithStrictScalars() LoadOption {
+	return func(c *loadConfig) {
+		c.strictScalars = true
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// checkScalars checks the boolean and numeric scalars of node, decoded into a
+// value of typ, are written as such
+func checkScalars(node *yaml.Node, typ reflect.Type, path string) error {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return checkScalar(node, typ, path)
+
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			var valueType reflect.Type
+			switch typ.Kind() {
+			case reflect.Struct:
+				field, ok := yamlField(typ, key)
+				if !ok {
+					continue
+				}
+				valueType = field.Type
+			case reflect.Map:
+				valueType = typ.Elem()
+			default:
+				return nil
+			}
+			if err := checkScalars(node.Content[i+1], valueType, path+"."+key); err != nil {
+				return err
+			}
+		}
+
+	case yaml.SequenceNode:
+		if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+			return nil
+		}
+		for i, el := range node.Content {
+			if err := checkScalars(el, typ.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkScalar(node *yaml.Node, typ reflect.Type, path string) error {
+	tag := node.ShortTag()
+	if tag == "!!null" || typ == durationType {
+		return nil
+	}
+	var expected string
+	switch typ.Kind() {
+	case reflect.Bool:
+		if tag == "!!bool" && (node.Value == "true" || node.Value == "false") {
+			return nil
+		}
+		expected = "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if tag == "!!int" {
+			return nil
+		}
+		expected = "an unquoted integer"
+	case reflect.Float32, reflect.Float64:
+		if tag == "!!int" || tag == "!!float" {
+			return nil
+		}
+		expected = "an unquoted number"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%w: %s at line %d is %q, expected %s", ErrAmbiguousScalar, path, node.Line, node.Value, expected)
+}
+
+// yamlField returns the field of a struct type decoded from key
+func yamlField(typ reflect.Type, key string) (reflect.StructField, bool) {
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if name == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// WhitespaceFinding is whitespace of a rule file that editors may silently
+// change or hide
+type WhitespaceFinding struct {
+	Line    int
+	Message string
+}
+
+func (f WhitespaceFinding) String() string {
+	return f.Message
+}
+
+// CheckWhitespace reports the lines of a rule file with trailing whitespace,
+// tabs or non-ASCII whitespace such as non-breaking spaces
+func CheckWhitespace(data []byte) []WhitespaceFinding {
+	var findings []WhitespaceFinding
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		switch trimmed := strings.TrimRight(text, " \t"); {
+		case trimmed != text:
+			findings = append(findings, WhitespaceFinding{Line: line, Message: "trailing whitespace"})
+		case strings.Contains(text, "\t"):
+			findings = append(findings, WhitespaceFinding{Line: line, Message: "tab character"})
+		}
+		for _, r := range text {
+			if r > unicode.MaxASCII && unicode.IsSpace(r) {
+				findings = append(findings, WhitespaceFinding{Line: line, Message: fmt.Sprintf("non-ASCII whitespace %U", r)})
+				break
+			}
+		}
+	}
+	return findings
+}
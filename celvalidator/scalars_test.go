@@ -0,0 +1,46 @@
+package celvalidator
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Strict scalars", func() {
+	DescribeTable("rejects coerced values",
+		func(data, message string) {
+			_, err := ParseRuleFileYAML([]byte(data), WithStrictScalars())
+			Expect(err).To(MatchError(ErrAmbiguousScalar))
+			Expect(err).To(MatchError(ContainSubstring(message)))
+
+			_, err = ParseRuleFileYAML([]byte(data))
+			Expect(errors.Is(err, ErrAmbiguousScalar)).To(BeFalse())
+		},
+		Entry("yes for a boolean", "Sample:\n  Create:\n    - rule: Active\n      enabled: yes\n",
+			`Sample.Create[0].enabled at line 4 is "yes", expected true or false`),
+		Entry("a quoted boolean in a Then child", "Sample:\n  Create:\n    - rule: Active\n      enabled: true\n      then:\n        - rule: Active\n          enabled: \"true\"\n",
+			`Sample.Create[0].then[0].enabled at line 7 is "true", expected true or false`),
+		Entry("a quoted number", "Sample:\n  Create:\n    - rule: Active\n      enabled: true\n      weight: \"2\"\n",
+			`Sample.Create[0].weight at line 5 is "2", expected an unquoted number`),
+		Entry("a boolean of the validator block", "validator:\n  fail_fast: on\n",
+			`validator.fail_fast at line 2 is "on", expected true or false`),
+	)
+
+	It("accepts plain values", func() {
+		file, err := ParseRuleFileYAML([]byte("validator:\n  rule_timeout: 100ms\n  rule_cost_limit: 1000\nSample:\n  Create:\n    - rule: Active\n      enabled: true\n      weight: 1.5\n      examples:\n        pass: [{Active: yes}]\n"), WithStrictScalars())
+		Expect(err).To(BeNil())
+		Expect(file.Rules["Sample"]["Create"][0].Enabled).To(BeTrue())
+	})
+})
+
+var _ = Describe("CheckWhitespace", func() {
+	It("reports trailing whitespace, tabs and non-ASCII whitespace", func() {
+		data := "Sample:\r\n  Create:\n    - rule: Active\n      enabled: true\t\n      message: \"a\tb\"\n      id: a b\n"
+		Expect(CheckWhitespace([]byte(data))).To(Equal([]WhitespaceFinding{
+			{Line: 4, Message: "trailing whitespace"},
+			{Line: 5, Message: "tab character"},
+			{Line: 6, Message: "non-ASCII whitespace U+00A0"},
+		}))
+	})
+})
@@ -0,0 +1,213 @@
+package celvalidator
+
+import (
+	"fmt"
+	"regexp/syntax"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+)
+
+// FindingKind classifies a security scanner finding
+type FindingKind string
+
+const (
+	FindingParseError          FindingKind = "parse_error"
+	FindingNestedComprehension FindingKind = "nested_comprehension"
+	FindingRegexBacktracking   FindingKind = "regex_backtracking"
+	FindingStringConcatenation FindingKind = "string_concatenation"
+)
+
+// ScanFinding is a potentially dangerous pattern found in a rule
+type ScanFinding struct {
+	Rule    string
+	Kind    FindingKind
+	Message string
+}
+
+// ScanOption configures the rule security scanner
+type ScanOption func(*scanConfig)
+
+type scanConfig struct {
+	maxComprehensionDepth   int
+	maxStringConcatenations int
+}
+
+// WithMaxComprehensionDepth sets how many comprehensions (all, exists, map,
+// filter...) may be nested before being flagged, defaults to 1
+func WithMaxComprehensionDepth(depth int) ScanOption {
+	return func(c *scanConfig) {
+		c.maxComprehensionDepth = depth
+	}
+}
+
+// WithMaxStringConcatenations sets how many chained string concatenations are
+// allowed before being flagged, defaults to 10
+func WithMaxStringConcatenations(n int) ScanOption {
+	return func(c *scanConfig) {
+		c.maxStringConcatenations = n
+	}
+}
+
+// ScanRules statically scans rules, including Then children and their message
+// and suggestion expressions, for dangerous patterns before they are accepted
+// into a rule store
+func ScanRules(rules []RuleEntry, opts ...ScanOption) []ScanFinding {
+	var findings []ScanFinding
+	for _, r := range flattenRuleTree(rules) {
+		for _, expr := range r.expressions() {
+			findings = append(findings, ScanRule(expr, opts...)...)
+		}
+	}
+	return findings
+}
+
+// ScanRule statically scans a rule expression for unbounded nested
+// comprehensions, regexes with nested quantifiers and excessive string
+// concatenation. Only parsing is needed, so rules are scanned without their type.
+func ScanRule(rule string, opts ...ScanOption) []ScanFinding {
+	cfg := &scanConfig{maxComprehensionDepth: 1, maxStringConcatenations: 10}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	env, err := cel.NewEnv(parsePresenceMacro())
+	if err != nil {
+		return []ScanFinding{{Rule: rule, Kind: FindingParseError, Message: err.Error()}}
+	}
+	parsed, iss := env.Parse(rule)
+	if iss != nil && iss.Err() != nil {
+		return []ScanFinding{{Rule: rule, Kind: FindingParseError, Message: iss.Err().Error()}}
+	}
+
+	s := &scanner{rule: rule, cfg: cfg}
+	s.walk(parsed.NativeRep().Expr(), 0, false)
+	return s.findings
+}
+
+type scanner struct {
+	rule     string
+	cfg      *scanConfig
+	findings []ScanFinding
+}
+
+func (s *scanner) report(kind FindingKind, format string, args ...any) {
+	s.findings = append(s.findings, ScanFinding{Rule: s.rule, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+// walk visits e, depth being the number of enclosing comprehensions and
+// inConcat whether e is an operand of an already counted concatenation
+func (s *scanner) walk(e ast.Expr, depth int, inConcat bool) {
+	switch e.Kind() {
+	case ast.ComprehensionKind:
+		depth++
+		if depth == s.cfg.maxComprehensionDepth+1 {
+			s.report(FindingNestedComprehension, "comprehensions nested %d deep, cost grows with the product of list sizes", depth)
+		}
+		c := e.AsComprehension()
+		s.walk(c.IterRange(), depth-1, false)
+		s.walk(c.AccuInit(), depth, false)
+		s.walk(c.LoopCondition(), depth, false)
+		s.walk(c.LoopStep(), depth, false)
+		s.walk(c.Result(), depth, false)
+		return
+
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.FunctionName() == "matches" {
+			s.checkRegex(call)
+		}
+		if call.FunctionName() == operators.Add && !inConcat {
+			if n, hasString := countConcatenations(e); hasString && n > s.cfg.maxStringConcatenations {
+				s.report(FindingStringConcatenation, "%d chained string concatenations", n)
+			}
+		}
+		if call.IsMemberFunction() {
+			s.walk(call.Target(), depth, false)
+		}
+		for _, arg := range call.Args() {
+			s.walk(arg, depth, call.FunctionName() == operators.Add)
+		}
+
+	case ast.SelectKind:
+		s.walk(e.AsSelect().Operand(), depth, false)
+
+	case ast.ListKind:
+		for _, el := range e.AsList().Elements() {
+			s.walk(el, depth, false)
+		}
+
+	case ast.MapKind:
+		for _, entry := range e.AsMap().Entries() {
+			s.walk(entry.AsMapEntry().Key(), depth, false)
+			s.walk(entry.AsMapEntry().Value(), depth, false)
+		}
+
+	case ast.StructKind:
+		for _, field := range e.AsStruct().Fields() {
+			s.walk(field.AsStructField().Value(), depth, false)
+		}
+	}
+}
+
+// checkRegex flags literal patterns with a quantified sub-expression that is
+// itself quantified, e.g. (a+)+. cel-go evaluates regexes with RE2 in linear
+// time, but such patterns are costly and dangerous on backtracking engines.
+func (s *scanner) checkRegex(call ast.CallExpr) {
+	args := call.Args()
+	if len(args) == 0 || args[len(args)-1].Kind() != ast.LiteralKind {
+		return
+	}
+	pattern, ok := args[len(args)-1].AsLiteral().(types.String)
+	if !ok {
+		return
+	}
+
+	re, err := syntax.Parse(string(pattern), syntax.Perl)
+	if err != nil {
+		s.report(FindingRegexBacktracking, "invalid regex %q: %v", string(pattern), err)
+		return
+	}
+	if hasNestedQuantifier(re, false) {
+		s.report(FindingRegexBacktracking, "regex %q nests quantifiers", string(pattern))
+	}
+}
+
+func hasNestedQuantifier(re *syntax.Regexp, quantified bool) bool {
+	isQuantifier := re.Op == syntax.OpStar || re.Op == syntax.OpPlus ||
+		(re.Op == syntax.OpRepeat && (re.Max == -1 || re.Max > 1))
+	if isQuantifier && quantified {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasNestedQuantifier(sub, quantified || isQuantifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// countConcatenations counts the additions of a chain of _+_ calls and whether
+// any operand is a string literal
+func countConcatenations(e ast.Expr) (int, bool) {
+	switch e.Kind() {
+	case ast.LiteralKind:
+		_, ok := e.AsLiteral().(types.String)
+		return 0, ok
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.FunctionName() != operators.Add {
+			return 0, false
+		}
+		count, hasString := 1, false
+		for _, arg := range call.Args() {
+			n, str := countConcatenations(arg)
+			count += n
+			hasString = hasString || str
+		}
+		return count, hasString
+	}
+	return 0, false
+}
@@ -0,0 +1,48 @@
+package celvalidator
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rule security scanner", func() {
+	It("accepts simple rules", func() {
+		Expect(ScanRule("Items.all(i, i.Price > 0) && Email.matches('^[a-z]+@x\\\\.com$')")).To(BeEmpty())
+	})
+
+	It("flags nested comprehensions", func() {
+		findings := ScanRule("A.all(a, B.exists(b, a == b))")
+		Expect(findings).To(ConsistOf(HaveField("Kind", FindingNestedComprehension)))
+		Expect(ScanRule("A.all(a, B.exists(b, a == b))", WithMaxComprehensionDepth(2))).To(BeEmpty())
+	})
+
+	It("flags regexes with nested quantifiers", func() {
+		findings := ScanRule("Name.matches('^(a+)+$')")
+		Expect(findings).To(ConsistOf(HaveField("Kind", FindingRegexBacktracking)))
+	})
+
+	It("flags excessive string concatenation", func() {
+		rule := "Name" + strings.Repeat(" + 'x'", 12) + " != ''"
+		Expect(ScanRule(rule)).To(ConsistOf(HaveField("Kind", FindingStringConcatenation)))
+		Expect(ScanRule(rule, WithMaxStringConcatenations(20))).To(BeEmpty())
+		Expect(ScanRule("A" + strings.Repeat(" + 1", 12) + " > 0")).To(BeEmpty())
+	})
+
+	It("scans Then children and reports parse errors", func() {
+		rules := []RuleEntry{{Rule: "Age > 0", Then: []RuleEntry{{Rule: "Age >"}}}}
+		Expect(ScanRules(rules)).To(ConsistOf(HaveField("Kind", FindingParseError)))
+	})
+
+	It("scans message and suggestion expressions", func() {
+		rules := []RuleEntry{{Rule: "Age > 0",
+			MessageExpression:    "[1, 2].exists(a, [3, 4].exists(b, a < b)) ? 'x' : 'y'",
+			SuggestionExpression: "Email.matches('(a+)+$')",
+		}}
+		Expect(ScanRules(rules)).To(ConsistOf(
+			HaveField("Kind", FindingNestedComprehension),
+			HaveField("Kind", FindingRegexBacktracking),
+		))
+	})
+})
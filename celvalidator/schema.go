@@ -0,0 +1,96 @@
+package celvalidator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+)
+
+// InferEnvFromJSON builds a CEL environment from a representative JSON object,
+// so rules can be written and checked before the Go type exists. Nested objects
+// are flattened the same way as nested structs ("Address.City") and are also
+// declared whole as maps, so Locations['hq'].City works too.
+func InferEnvFromJSON(sample []byte) (*cel.Env, error) {
+	fields, err := flattenJSON(sample)
+	if err != nil {
+		return nil, err
+	}
+	return newEnvFromFields(fields)
+}
+
+// flattenJSON decodes a JSON object into flattened fields with Go values
+// matching what flattenStruct would produce
+func flattenJSON(sample []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(sample))
+	dec.UseNumber()
+
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding JSON sample: %w", err)
+	}
+
+	result := make(map[string]any)
+	flattenJSONObject("", doc, result)
+	return result, nil
+}
+
+func flattenJSONObject(prefix string, obj map[string]any, result map[string]any) {
+	for k, v := range obj {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+
+		// nested objects are also kept whole, so map-valued fields such as
+		// Locations['hq'].City resolve the same way they do for Go structs
+		result[name] = jsonValue(v)
+		if val, ok := v.(map[string]any); ok {
+			flattenJSONObject(name, val, result)
+		}
+	}
+}
+
+// jsonValue converts a decoded JSON or YAML value recursively, turning numbers
+// into int64 or float64 the way celValue does for Go fields
+func jsonValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = jsonValue(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = jsonValue(item)
+		}
+		return out
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case int:
+		return int64(val)
+	default:
+		return val
+	}
+}
+
+// ValidateJSON evaluates rules against a JSON object, flattened like InferEnvFromJSON
+func (v *Validator) ValidateJSON(data []byte, rules []RuleEntry, metadata ValidationMetadata) ([]ValidationResult, error) {
+	fields, err := flattenJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	env, err := v.documentEnv(fields)
+	if err != nil {
+		return nil, err
+	}
+	return v.validate(context.Background(), env, fields, rules, metadata)
+}
@@ -0,0 +1,49 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InferEnvFromJSON", func() {
+	sample := []byte(`{"Age": 30, "Score": 1.5, "Email": "a@b.c", "Address": {"City": "LA"}}`)
+
+	It("declares flattened and typed fields", func() {
+		env, err := InferEnvFromJSON(sample)
+		Expect(err).To(BeNil())
+
+		for _, rule := range []string{"Age > 18", "Score < 2.0", "Email != ''", "Address.City == 'LA'"} {
+			_, iss := env.Compile(rule)
+			Expect(iss.Err()).To(BeNil(), rule)
+		}
+	})
+
+	It("rejects rules on unknown fields", func() {
+		env, err := InferEnvFromJSON(sample)
+		Expect(err).To(BeNil())
+
+		_, iss := env.Compile("Unknown == 1")
+		Expect(iss.Err()).To(HaveOccurred())
+	})
+
+	It("errors on invalid JSON", func() {
+		_, err := InferEnvFromJSON([]byte(`[1, 2]`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("validates map values by key in JSON objects", func() {
+		data := []byte(`{"Locations": {"hq": {"City": "LA", "Zip": 90001}, "lab": {"City": ""}}, "Tags": [{"Weight": 2}]}`)
+		results, err := NewValidator().ValidateJSON(data, []RuleEntry{
+			{Rule: "Locations['hq'].City != ''", Enabled: true},
+			{Rule: "Locations['hq'].Zip > 90000", Enabled: true},
+			{Rule: "Locations.all(k, Locations[k].City != '')", Enabled: true},
+			{Rule: "Tags.all(t, t.Weight > 1)", Enabled: true},
+		}, ValidationMetadata{StructName: "Site", Operation: "Default", RuleIndex: -1})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(4))
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[1].Passed).To(BeTrue())
+		Expect(results[2].Passed).To(BeFalse())
+		Expect(results[3].Passed).To(BeTrue())
+	})
+})
@@ -0,0 +1,258 @@
+package celvalidator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ServiceRequest asks a rule service to validate a JSON object against the
+// rules of a struct and operation
+type ServiceRequest struct {
+	StructName  string            `json:"struct"`
+	Operation   string            `json:"operation"`
+	Object      json.RawMessage   `json:"object"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ServiceResult is the wire form of a ValidationResult
+type ServiceResult struct {
+	ID       string   `json:"id,omitempty"`
+	Rule     string   `json:"rule"`
+	Passed   bool     `json:"passed"`
+	Message  string   `json:"message,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Severity Severity `json:"severity,omitempty"`
+	Weight   float64  `json:"weight,omitempty"`
+	Outcome  Outcome  `json:"outcome"`
+	Cost     uint64   `json:"cost,omitempty"`
+	// FieldPaths holds the request fields a failure is attributed to
+	FieldPaths []string `json:"field_paths,omitempty"`
+	// Suggestion and DocURL tell how to fix a failure
+	Suggestion string `json:"suggestion,omitempty"`
+	DocURL     string `json:"doc_url,omitempty"`
+	// Owner, Description, Annotations and Deprecated are those of the rule
+	Owner       string            `json:"owner,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Deprecated  bool              `json:"deprecated,omitempty"`
+	// ChainPath, RuleIndex and the parent fields locate the rule in its rule
+	// tree, see ValidationMetadata
+	ChainPath     string `json:"chain_path,omitempty"`
+	RuleIndex     int    `json:"rule_index"`
+	ParentRule    string `json:"parent_rule,omitempty"`
+	ParentRuleID  string `json:"parent_rule_id,omitempty"`
+	ParentIndexes []int  `json:"parent_indexes,omitempty"`
+}
+
+// ServiceResponse carries the results of a ServiceRequest
+type ServiceResponse struct {
+	Results []ServiceResult `json:"results"`
+}
+
+// maxServiceRequestSize bounds the body of a ServiceRequest
+const maxServiceRequestSize = 4 << 20
+
+// NewRuleService returns a handler evaluating rules centrally, so services in
+// other languages share the engine and its compiled programs. It accepts a
+// POSTed ServiceRequest and answers with a ServiceResponse, evaluating the
+// object like ValidateJSON, along with summary headers, see SetSummaryHeaders.
+func NewRuleService(v *Validator, rules RuleSetMap) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req ServiceRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxServiceRequestSize)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("decoding request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if _, ok := rules[req.StructName]; !ok {
+			http.Error(w, fmt.Sprintf("no rules for struct %q", req.StructName), http.StatusNotFound)
+			return
+		}
+
+		results, err := ValidateServiceRequest(v, rules, req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		resp := ServiceResponse{Results: make([]ServiceResult, 0, len(results))}
+		for _, res := range results {
+			resp.Results = append(resp.Results, NewServiceResult(res))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		SetSummaryHeaders(w.Header(), results)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// ValidateServiceRequest evaluates a request in process, as a rule service does
+func ValidateServiceRequest(v *Validator, rules RuleSetMap, req ServiceRequest) ([]ValidationResult, error) {
+	metadata := ValidationMetadata{
+		StructName:  req.StructName,
+		Operation:   req.Operation,
+		RuleIndex:   -1,
+		Annotations: req.Annotations,
+	}
+	return v.ValidateJSON(req.Object, GetRulesForStruct(req.StructName, req.Operation, rules), metadata)
+}
+
+// NewServiceResult converts r to its wire form
+func NewServiceResult(r ValidationResult) ServiceResult {
+	res := ServiceResult{
+		ID:            r.ID,
+		Rule:          r.Rule,
+		Passed:        r.Passed,
+		Message:       r.Message,
+		Severity:      r.Severity,
+		Weight:        r.Weight,
+		Outcome:       r.Outcome,
+		Cost:          r.Cost,
+		FieldPaths:    r.FieldPaths,
+		Suggestion:    r.Suggestion,
+		DocURL:        r.DocURL,
+		Owner:         r.Owner,
+		Description:   r.Description,
+		Annotations:   r.Annotations,
+		Deprecated:    r.Deprecated,
+		ChainPath:     r.Metadata.ChainPath,
+		RuleIndex:     r.Metadata.RuleIndex,
+		ParentRule:    r.Metadata.ParentRule,
+		ParentRuleID:  r.Metadata.ParentRuleID,
+		ParentIndexes: r.Metadata.ParentIndexes,
+	}
+	if r.Error != nil {
+		res.Error = r.Error.Error()
+	}
+	return res
+}
+
+// Result converts r back to a ValidationResult of a validation with metadata
+func (r ServiceResult) Result(metadata ValidationMetadata) ValidationResult {
+	metadata.ChainPath = r.ChainPath
+	metadata.RuleIndex = r.RuleIndex
+	metadata.RuleID = r.ID
+	metadata.ParentRule = r.ParentRule
+	metadata.ParentRuleID = r.ParentRuleID
+	metadata.ParentIndexes = r.ParentIndexes
+	res := ValidationResult{
+		ID:          r.ID,
+		Rule:        r.Rule,
+		Passed:      r.Passed,
+		Message:     r.Message,
+		Severity:    r.Severity,
+		Weight:      r.Weight,
+		Outcome:     r.Outcome,
+		Cost:        r.Cost,
+		FieldPaths:  r.FieldPaths,
+		Suggestion:  r.Suggestion,
+		DocURL:      r.DocURL,
+		Owner:       r.Owner,
+		Description: r.Description,
+		Annotations: r.Annotations,
+		Deprecated:  r.Deprecated,
+		Metadata:    metadata,
+	}
+	if r.Error != "" {
+		res.Error = errors.New(r.Error)
+	}
+	return res
+}
+
+// ErrServiceUnavailable is returned by a RuleServiceClient without fallback
+// when the rule service cannot be reached
+var ErrServiceUnavailable = errors.New("rule service unavailable")
+
+// defaultServiceTimeout bounds a call to the rule service before falling back
+const defaultServiceTimeout = 2 * time.Second
+
+// RuleServiceClient validates objects through a rule service, falling back to
+// in-process evaluation when the service is unreachable or fails
+type RuleServiceClient struct {
+	URL    string
+	Client *http.Client
+	// Fallback and Rules evaluate requests locally when the service is
+	// unavailable; a nil Fallback reports ErrServiceUnavailable instead
+	Fallback *Validator
+	Rules    RuleSetMap
+}
+
+// NewRuleServiceClient creates a client of the rule service at url, falling
+// back to fallback and rules
+func NewRuleServiceClient(url string, fallback *Validator, rules RuleSetMap) *RuleServiceClient {
+	return &RuleServiceClient{
+		URL:      url,
+		Client:   &http.Client{Timeout: defaultServiceTimeout},
+		Fallback: fallback,
+		Rules:    rules,
+	}
+}
+
+// Validate validates the JSON encoding of obj against the rules of its struct
+func (c *RuleServiceClient) Validate(ctx context.Context, obj any, operation string) ([]ValidationResult, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling object: %w", err)
+	}
+	return c.ValidateJSON(ctx, ServiceRequest{StructName: getStructName(obj), Operation: operation, Object: data})
+}
+
+// ValidateJSON sends req to the rule service. Transport failures and server
+// errors fall back to in-process evaluation, while rejected requests are
+// returned as errors.
+func (c *RuleServiceClient) ValidateJSON(ctx context.Context, req ServiceRequest) ([]ValidationResult, error) {
+	results, err := c.call(ctx, req)
+	if err == nil || !errors.Is(err, ErrServiceUnavailable) {
+		return results, err
+	}
+	if c.Fallback == nil {
+		return nil, err
+	}
+	return ValidateServiceRequest(c.Fallback, c.Rules, req)
+}
+
+func (c *RuleServiceClient) call(ctx context.Context, req ServiceRequest) ([]ValidationResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.Client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrServiceUnavailable, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 500:
+		return nil, fmt.Errorf("%w: %s", ErrServiceUnavailable, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("rule service: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	var decoded ServiceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("%w: decoding response: %v", ErrServiceUnavailable, err)
+	}
+	metadata := ValidationMetadata{StructName: req.StructName, Operation: req.Operation, RuleIndex: -1, Annotations: req.Annotations}
+	results := make([]ValidationResult, 0, len(decoded.Results))
+	for _, r := range decoded.Results {
+		results = append(results, r.Result(metadata))
+	}
+	return results, nil
+}
@@ -0,0 +1,122 @@
+package celvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rule service", func() {
+	rules := RuleSetMap{
+		"Sample": {
+			"Default": {{ID: "adult", Rule: "Age >= 18", Enabled: true, FailureMessage: "too young", Suggestion: "ask a guardian", DocURL: "https://docs.example.com/adult"}},
+			"Create":  {{Rule: "Email != ''", Enabled: true}},
+		},
+	}
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewServer(NewRuleService(NewValidator(), rules))
+		DeferCleanup(server.Close)
+	})
+
+	It("evaluates objects centrally", func() {
+		client := NewRuleServiceClient(server.URL, nil, nil)
+		results, err := client.Validate(context.Background(), Sample{Age: 10}, "Create")
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].ID).To(Equal("adult"))
+		Expect(results[0].Passed).To(BeFalse())
+		Expect(results[0].Message).To(Equal("too young"))
+		Expect(results[0].Outcome).To(Equal(OutcomeFailed))
+		Expect(results[0].Metadata.StructName).To(Equal("Sample"))
+		Expect(results[0].FieldPaths).To(Equal([]string{"Age"}))
+		Expect(results[0].Suggestion).To(Equal("ask a guardian"))
+		Expect(results[0].DocURL).To(Equal("https://docs.example.com/adult"))
+		Expect(results[1].Passed).To(BeFalse())
+		Expect(results[1].FieldPaths).To(Equal([]string{"Email"}))
+	})
+
+	It("returns the results of a local validation", func() {
+		rules := RuleSetMap{"Sample": {"Create": {
+			{ID: "adult", Rule: "Age >= 18", Enabled: true, Owner: "identity", Description: "members must be adults",
+				Annotations: map[string]string{"runbook": "https://runbooks.example.com/adult"}, Deprecated: true, Weight: 2},
+			{ID: "active", Rule: "Active", Enabled: true, Then: []RuleEntry{
+				{Rule: "Email.endsWith('.com')", Enabled: true, Severity: SeverityWarning},
+			}},
+			{Rule: "Unknown == 1", Enabled: true},
+		}}}
+		v := NewValidator(WithPartialEval())
+		server := httptest.NewServer(NewRuleService(v, rules))
+		DeferCleanup(server.Close)
+
+		obj := Sample{Age: 10, Active: true, Email: "a@b.org"}
+		remote, err := NewRuleServiceClient(server.URL, nil, nil).Validate(context.Background(), obj, "Create")
+		Expect(err).To(BeNil())
+
+		data, err := json.Marshal(obj)
+		Expect(err).To(BeNil())
+		local, err := v.ValidateJSON(data, GetRulesForStruct("Sample", "Create", rules), ValidationMetadata{StructName: "Sample", Operation: "Create", RuleIndex: -1})
+		Expect(err).To(BeNil())
+
+		// errors travel as their message, issues and suggested values stay local
+		wire := func(results []ValidationResult) []ValidationResult {
+			out := slices.Clone(results)
+			for i := range out {
+				if out[i].Error != nil {
+					out[i].Error = errors.New(out[i].Error.Error())
+				}
+				out[i].Issues = nil
+			}
+			return out
+		}
+		Expect(remote).To(HaveLen(4))
+		Expect(remote).To(Equal(wire(local)))
+		Expect(remote[2].Metadata.ParentRuleID).To(Equal("active"))
+	})
+
+	It("writes summary headers", func() {
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"struct": "Sample", "operation": "Create", "object": {"Age": 30, "Email": "a@example.com"}}`))
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+		Expect(resp.Header.Get(HeaderFailedCount)).To(Equal("0"))
+		Expect(resp.Header.Values(HeaderRuleIDs)).To(Equal([]string{""}))
+	})
+
+	It("rejects unknown structs and non-POST requests", func() {
+		client := NewRuleServiceClient(server.URL, NewValidator(), rules)
+		_, err := client.ValidateJSON(context.Background(), ServiceRequest{StructName: "Unknown", Object: []byte(`{}`)})
+		Expect(err).To(MatchError(ContainSubstring(`no rules for struct "Unknown"`)))
+		Expect(errors.Is(err, ErrServiceUnavailable)).To(BeFalse())
+
+		resp, err := http.Get(server.URL)
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("falls back to in-process evaluation when the service is unavailable", func() {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+
+		client := NewRuleServiceClient(failing.URL, NewValidator(), rules)
+		results, err := client.Validate(context.Background(), Sample{Age: 30, Email: "a@b.c"}, "Create")
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[1].Passed).To(BeTrue())
+
+		client = NewRuleServiceClient(strings.Replace(failing.URL, "http", "bogus", 1), nil, nil)
+		_, err = client.Validate(context.Background(), Sample{}, "Create")
+		Expect(errors.Is(err, ErrServiceUnavailable)).To(BeTrue())
+	})
+})
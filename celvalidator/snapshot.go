@@ -0,0 +1,92 @@
+package celvalidator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SnapshotPolicy captures the validated object in audit records, from its
+// fields as exposed to rules (flattened names such as Address.City, or nested
+// maps with WithNestedFields)
+type SnapshotPolicy interface {
+	Snapshot(fields map[string]any) (json.RawMessage, error)
+}
+
+// SnapshotPolicyFunc adapts a function to a SnapshotPolicy
+type SnapshotPolicyFunc func(fields map[string]any) (json.RawMessage, error)
+
+// Snapshot calls f
+func (f SnapshotPolicyFunc) Snapshot(fields map[string]any) (json.RawMessage, error) {
+	return f(fields)
+}
+
+// WithAuditSnapshot captures the validated object in audit records with policy,
+// objects are not captured by default
+func WithAuditSnapshot(policy SnapshotPolicy) ValidatorOption {
+	return func(v *Validator) {
+		v.snapshotPolicy = policy
+	}
+}
+
+// FullSnapshot captures every field as JSON
+func FullSnapshot() SnapshotPolicy {
+	return SnapshotPolicyFunc(func(fields map[string]any) (json.RawMessage, error) {
+		return json.Marshal(fields)
+	})
+}
+
+// HashedSnapshot captures only the SHA-256 of the fields JSON, proving which
+// object was validated without retaining its data
+func HashedSnapshot() SnapshotPolicy {
+	return SnapshotPolicyFunc(func(fields map[string]any) (json.RawMessage, error) {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		return json.Marshal(map[string]string{"sha256": hex.EncodeToString(sum[:])})
+	})
+}
+
+// FieldsSnapshot captures the named fields and the fields under them as JSON,
+// e.g. Address captures Address.City
+func FieldsSnapshot(names ...string) SnapshotPolicy {
+	return SnapshotPolicyFunc(func(fields map[string]any) (json.RawMessage, error) {
+		selected := map[string]any{}
+		for field, val := range fields {
+			for _, name := range names {
+				if field == name || strings.HasPrefix(field, name+".") {
+					selected[field] = val
+					break
+				}
+			}
+		}
+		return json.Marshal(selected)
+	})
+}
+
+// auditSnapshot captures fields with the validator snapshot policy, leaving
+// out variables that are not fields of the object
+func (v *Validator) auditSnapshot(fields map[string]any) (json.RawMessage, error) {
+	if v.snapshotPolicy == nil {
+		return nil, nil
+	}
+	if v.self || v.nativeVariable != "" {
+		own := make(map[string]any, len(fields))
+		for name, val := range fields {
+			if (!v.self || name != selfVariable) && name != v.nativeVariable {
+				own[name] = val
+			}
+		}
+		fields = own
+	}
+
+	snapshot, err := v.snapshotPolicy.Snapshot(fields)
+	if err != nil {
+		return nil, fmt.Errorf("capturing audit snapshot: %w", err)
+	}
+	return snapshot, nil
+}
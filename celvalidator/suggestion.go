@@ -0,0 +1,50 @@
+package celvalidator
+
+import "fmt"
+
+// suggest sets the fix-it hint and documentation link of a failed rule on
+// result. A suggestion expression that cannot be evaluated is reported as a
+// warning issue.
+func (e *evaluation) suggest(entry RuleEntry, result *ValidationResult) {
+	result.Suggestion = entry.Suggestion
+	result.DocURL = entry.DocURL
+	if entry.SuggestionExpression == "" {
+		return
+	}
+
+	value, err := e.evalExpression(entry, entry.SuggestionExpression)
+	if err != nil {
+		result.Issues = append(result.Issues, RuleIssue{
+			Severity: IssueWarning,
+			Message:  fmt.Sprintf("suggestion_expression: %v", err),
+		})
+		return
+	}
+	result.SuggestedValue = value
+}
+
+// evalExpression evaluates a non-rule expression of entry against the
+// validated object, under the security profile, timeout and cost limits of
+// the rule. Its cost is added to the validation, the rule reporting
+// ErrTotalCostLimit once exceeded.
+func (e *evaluation) evalExpression(entry RuleEntry, expression string) (any, error) {
+	ast, iss := e.env.Compile(expression)
+	if iss != nil && iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if err := e.v.deniedCall(ast); err != nil {
+		return nil, err
+	}
+	prg, err := e.env.Program(ast, e.prgOpts...)
+	if err != nil {
+		return nil, err
+	}
+	out, details, err := e.evalProgram(prg, entry)
+	if costErr := e.addCost(actualCost(details)); err == nil {
+		err = costErr
+	}
+	if err != nil {
+		return nil, e.v.costError(err)
+	}
+	return out.Value(), nil
+}
@@ -0,0 +1,48 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fix-it suggestions", func() {
+	ruleFile := []byte(`Sample:
+  Create:
+    - rule: "Email != ''"
+      enabled: true
+      message: "Email missing"
+      suggestion: "did you mean to copy the contact email?"
+      suggestion_expression: "Details['contact']"
+      doc_url: "https://docs.example.com/rules/email"
+    - rule: "Age > 18"
+      enabled: true
+      suggestion_expression: "Unknown + 1"
+`)
+
+	It("reports suggestions on failed rules", func() {
+		file, err := ParseRuleFileYAML(ruleFile)
+		Expect(err).To(BeNil())
+
+		obj := Sample{Age: 10, Details: map[string]string{"contact": "a@b.c"}}
+		results, err := NewValidator().Validate(obj, GetRulesFor(obj, "Create", file.Rules), ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+
+		Expect(results[0].Suggestion).To(Equal("did you mean to copy the contact email?"))
+		Expect(results[0].SuggestedValue).To(Equal("a@b.c"))
+		Expect(results[0].DocURL).To(Equal("https://docs.example.com/rules/email"))
+
+		Expect(results[1].SuggestedValue).To(BeNil())
+		Expect(results[1].Issues).To(ContainElement(HaveField("Message", ContainSubstring("suggestion_expression"))))
+	})
+
+	It("does not suggest on passing rules", func() {
+		results, err := NewValidator().Validate(Sample{Email: "x"}, []RuleEntry{
+			{Rule: "Email != ''", Enabled: true, Suggestion: "hint", SuggestionExpression: "'value'", DocURL: "https://docs.example.com"},
+		}, ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+		Expect(results[0].Suggestion).To(BeEmpty())
+		Expect(results[0].SuggestedValue).To(BeNil())
+		Expect(results[0].DocURL).To(BeEmpty())
+	})
+})
@@ -0,0 +1,151 @@
+package celvalidator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+)
+
+// ruleTag is the struct tag declaring the rule of a field, e.g.
+// `celrule:"self > 18,msg=must be adult"`
+const ruleTag = "celrule"
+
+// RulesFromTags returns the rules declared by celrule tags on the fields of
+// obj's type, nested structs included. self names the field in the rule, which
+// is rewritten to its flattened variable, e.g. Address.City. Options follow
+// the rule as trailing comma separated msg=, id= and severity= pairs, so
+// messages cannot contain commas. Fields are named after their Go name.
+func RulesFromTags(obj any) ([]RuleEntry, error) {
+	typ := reflect.TypeOf(obj)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("rules from tags: %v is not a struct", typ)
+	}
+	return appendTagRules(nil, typ, "", map[reflect.Type]bool{typ: true})
+}
+
+func appendTagRules(rules []RuleEntry, typ reflect.Type, prefix string, visiting map[reflect.Type]bool) ([]RuleEntry, error) {
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		path := prefix + field.Name
+
+		if tag, ok := field.Tag.Lookup(ruleTag); ok {
+			entry, err := parseRuleTag(tag, path)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", path, err)
+			}
+			rules = append(rules, entry)
+		}
+
+		nested := field.Type
+		for nested.Kind() == reflect.Ptr {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && !isValueStructType(nested) && !visiting[nested] {
+			visiting[nested] = true
+			var err error
+			if rules, err = appendTagRules(rules, nested, path+".", visiting); err != nil {
+				return nil, err
+			}
+			delete(visiting, nested)
+		}
+	}
+	return rules, nil
+}
+
+// parseRuleTag parses a celrule tag of the field at path
+func parseRuleTag(tag, path string) (RuleEntry, error) {
+	entry := RuleEntry{Enabled: true}
+	for {
+		i := strings.LastIndex(tag, ",")
+		if i < 0 {
+			break
+		}
+		key, value, _ := strings.Cut(strings.TrimSpace(tag[i+1:]), "=")
+		switch key {
+		case "msg":
+			entry.FailureMessage = value
+		case "id":
+			entry.ID = value
+		case "severity":
+			entry.Severity = Severity(value)
+		default:
+			i = -1
+		}
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+
+	rule, err := bindSelf(strings.TrimSpace(tag), path)
+	if err != nil {
+		return RuleEntry{}, err
+	}
+	entry.Rule = rule
+	return entry, nil
+}
+
+// bindSelf rewrites the self variable of rule to the variable at path
+func bindSelf(rule, path string) (string, error) {
+	env, err := cel.NewEnv(cel.EnableMacroCallTracking())
+	if err != nil {
+		return "", err
+	}
+	parsed, iss := env.Parse(rule)
+	if iss != nil && iss.Err() != nil {
+		return "", iss.Err()
+	}
+
+	native := parsed.NativeRep()
+	fac := ast.NewExprFactory()
+	rename := ast.NewExprVisitor(func(e ast.Expr) {
+		if e.Kind() == ast.IdentKind && e.AsIdent() == selfVariable {
+			e.SetKindCase(fac.NewIdent(e.ID(), path))
+		}
+	})
+	ast.PostOrderVisit(native.Expr(), rename)
+	for _, call := range native.SourceInfo().MacroCalls() {
+		ast.PostOrderVisit(call, rename)
+	}
+	return cel.AstToString(parsed)
+}
+
+// MergeTagRules returns a copy of rules with the tag rules of objs added to the
+// Default rules of their struct, skipping the rules whose ID or expression the
+// struct already has
+func MergeTagRules(rules RuleSetMap, objs ...any) (RuleSetMap, error) {
+	merged := copyRuleSetMap(rules)
+	for _, obj := range objs {
+		tagRules, err := RulesFromTags(obj)
+		if err != nil {
+			return nil, err
+		}
+		name := getStructName(obj)
+		if merged[name] == nil {
+			merged[name] = map[string][]RuleEntry{}
+		}
+
+		existing := map[string]bool{}
+		for _, entries := range merged[name] {
+			for _, entry := range flattenRuleTree(entries) {
+				existing[entry.Rule] = true
+				existing[entry.key()] = true
+			}
+		}
+		for _, entry := range tagRules {
+			if !existing[entry.Rule] && !existing[entry.key()] {
+				merged[name]["Default"] = append(merged[name]["Default"], entry)
+			}
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,58 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type taggedAddress struct {
+	City string `celrule:"size(self) > 0,msg=city is required"`
+}
+
+type taggedUser struct {
+	Name    string   `celrule:"self.startsWith('A') || self == 'root'"`
+	Age     int      `celrule:"self > 18,id=adult,severity=warning,msg=must be adult"`
+	Tags    []string `celrule:"self.all(t, t != ''),msg=no empty tags"`
+	Address *taggedAddress
+	Manager *taggedUser
+}
+
+var _ = Describe("Tag rules", func() {
+	It("collects the rules of fields, self naming the field", func() {
+		rules, err := RulesFromTags(&taggedUser{})
+		Expect(err).To(BeNil())
+		Expect(rules).To(Equal([]RuleEntry{
+			{Rule: `Name.startsWith("A") || Name == "root"`, Enabled: true},
+			{ID: "adult", Rule: "Age > 18", Enabled: true, Severity: SeverityWarning, FailureMessage: "must be adult"},
+			{Rule: `Tags.all(t, t != "")`, Enabled: true, FailureMessage: "no empty tags"},
+			{Rule: "size(Address.City) > 0", Enabled: true, FailureMessage: "city is required"},
+		}))
+	})
+
+	It("rejects invalid tags and types", func() {
+		type broken struct {
+			Age int `celrule:"self >"`
+		}
+		_, err := RulesFromTags(broken{})
+		Expect(err).To(MatchError(ContainSubstring("field Age")))
+		_, err = RulesFromTags(3)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("merges tag rules into the Default rules of a rule set", func() {
+		rules := RuleSetMap{"taggedUser": {"Create": {{Rule: "Age > 18", Enabled: true}}}}
+		merged, err := MergeTagRules(rules, taggedUser{})
+		Expect(err).To(BeNil())
+		Expect(rules["taggedUser"]).NotTo(HaveKey("Default"))
+		Expect(merged["taggedUser"]["Default"]).To(HaveLen(3))
+
+		obj := taggedUser{Name: "Ann", Age: 10, Address: &taggedAddress{City: "Oslo"}}
+		results, err := NewValidator(WithPartialEval()).Validate(obj, GetRulesFor(obj, "Create", merged), NewValidationMetadata(obj, "Create", merged))
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(4))
+		for _, res := range results {
+			Expect(res.Error).To(BeNil())
+			Expect(res.Passed).To(Equal(res.Rule != "Age > 18"), res.Rule)
+		}
+	})
+})
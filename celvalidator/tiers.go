@@ -0,0 +1,58 @@
+package celvalidator
+
+// RuleTiers is a rule set split by estimated cost
+type RuleTiers struct {
+	Fast []RuleEntry
+	Slow []RuleEntry
+}
+
+// PartitionByCost splits rules into a fast tier, whose rule trees (a rule and
+// its Then children) have an estimated cost up to threshold, and a slow tier.
+// Rules that fail to compile go to the fast tier so their errors surface early.
+func (v *Validator) PartitionByCost(obj any, rules []RuleEntry, threshold uint64) (RuleTiers, error) {
+	env, _, err := v.buildEnv(obj)
+	if err != nil {
+		return RuleTiers{}, err
+	}
+
+	var tiers RuleTiers
+	for _, r := range rules {
+		var total uint64
+		for _, entry := range flattenRuleTree([]RuleEntry{r}) {
+			if cost, err := estimateRuleCost(env, entry.Rule); err == nil {
+				total += cost
+			}
+		}
+
+		if total <= threshold {
+			tiers.Fast = append(tiers.Fast, r)
+		} else {
+			tiers.Slow = append(tiers.Slow, r)
+		}
+	}
+	return tiers, nil
+}
+
+// ValidateTiered validates the fast tier synchronously and the slow tier in the
+// background, delivering its results to onSlow. onSlow is not called when
+// there are no slow rules.
+func (v *Validator) ValidateTiered(
+	obj any,
+	rules []RuleEntry,
+	metadata ValidationMetadata,
+	threshold uint64,
+	onSlow func([]ValidationResult, error),
+) ([]ValidationResult, error) {
+	tiers, err := v.PartitionByCost(obj, rules, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tiers.Slow) > 0 {
+		go func() {
+			onSlow(v.Validate(obj, tiers.Slow, metadata))
+		}()
+	}
+
+	return v.Validate(obj, tiers.Fast, metadata)
+}
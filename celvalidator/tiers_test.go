@@ -0,0 +1,39 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tiered validation", func() {
+	obj := Sample{Age: 21, Email: "test@example.com", Details: map[string]string{"type": "admin"}}
+	rules := []RuleEntry{
+		{Rule: "Age > 18", Enabled: true},
+		{Rule: "Email.matches('^[a-z]+@[a-z]+\\\\.com$')", Enabled: true},
+	}
+
+	It("partitions rules by estimated cost", func() {
+		tiers, err := NewValidator().PartitionByCost(obj, rules, 10)
+		Expect(err).To(BeNil())
+		Expect(tiers.Fast).To(ConsistOf(HaveField("Rule", "Age > 18")))
+		Expect(tiers.Slow).To(HaveLen(1))
+	})
+
+	It("delivers slow tier results through the callback", func() {
+		slow := make(chan []ValidationResult, 1)
+		results, err := NewValidator().ValidateTiered(obj, rules, ValidationMetadata{StructName: "Sample"}, 10,
+			func(res []ValidationResult, err error) {
+				defer GinkgoRecover()
+				Expect(err).To(BeNil())
+				slow <- res
+			})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Rule).To(Equal("Age > 18"))
+
+		var slowResults []ValidationResult
+		Eventually(slow).Should(Receive(&slowResults))
+		Expect(slowResults).To(HaveLen(1))
+		Expect(slowResults[0].Passed).To(BeTrue())
+	})
+})
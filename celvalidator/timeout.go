@@ -0,0 +1,45 @@
+package celvalidator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// ErrRuleTimeout is reported on the result of a rule exceeding its timeout
+var ErrRuleTimeout = errors.New("rule evaluation timed out")
+
+// WithRuleTimeout sets the timeout of rules without their own timeout
+func WithRuleTimeout(d time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.ruleTimeout = d
+	}
+}
+
+// timeout returns the evaluation timeout of the rule, 0 meaning none
+func (e *evaluation) timeout(entry RuleEntry) time.Duration {
+	if entry.Timeout > 0 {
+		return entry.Timeout
+	}
+	return e.v.ruleTimeout
+}
+
+// evalProgram evaluates a rule program within the rule timeout
+func (e *evaluation) evalProgram(prg cel.Program, entry RuleEntry) (ref.Val, *cel.EvalDetails, error) {
+	timeout := e.timeout(entry)
+	if timeout <= 0 {
+		return prg.ContextEval(e.ctx, e.vars)
+	}
+
+	ctx, cancel := context.WithTimeout(e.ctx, timeout)
+	defer cancel()
+	out, details, err := prg.ContextEval(ctx, e.vars)
+	if err != nil && e.ctx.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w after %v", ErrRuleTimeout, timeout)
+	}
+	return out, details, err
+}
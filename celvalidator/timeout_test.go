@@ -0,0 +1,54 @@
+package celvalidator
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rule timeouts", func() {
+	md := ValidationMetadata{StructName: "Sample"}
+	// slowRule iterates 10^8 times over resolved lists
+	slowRule := "ext.outer.all(a, ext.inner.all(b, a + b >= 0))"
+	lists := map[string][]any{"outer": make([]any, 100), "inner": make([]any, 1000000)}
+	for _, list := range lists {
+		for i := range list {
+			list[i] = i
+		}
+	}
+	withLists := WithVariableResolver("ext", VariableResolverFunc(func(rc ResolverContext, name string) (any, error) {
+		return lists[name], nil
+	}))
+
+	It("reports rules exceeding their timeout", func() {
+		rules := []RuleEntry{
+			{Rule: slowRule, Enabled: true, Timeout: 20 * time.Millisecond},
+			{Rule: "Age == 0", Enabled: true},
+		}
+		start := time.Now()
+		results, err := NewValidator(withLists, WithPartialEval()).Validate(Sample{}, rules, md)
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Error).To(MatchError(ErrRuleTimeout))
+		Expect(results[0].Outcome).To(Equal(OutcomeError))
+		Expect(results[1].Passed).To(BeTrue())
+	})
+
+	It("stops at a timed out rule without partial evaluation", func() {
+		rules := []RuleEntry{
+			{Rule: slowRule, Enabled: true},
+			{Rule: "Age == 0", Enabled: true},
+		}
+		results, err := NewValidator(withLists, WithRuleTimeout(20*time.Millisecond)).Validate(Sample{}, rules, md)
+		Expect(err).To(MatchError(ErrRuleTimeout))
+		Expect(results).To(HaveLen(1))
+	})
+
+	It("reads timeouts from rule files", func() {
+		file, err := ParseRuleFileYAML([]byte("Sample:\n  Default:\n    - rule: Age > 1\n      enabled: true\n      timeout: 150ms\n"))
+		Expect(err).To(BeNil())
+		Expect(file.Rules["Sample"]["Default"][0].Timeout).To(Equal(150 * time.Millisecond))
+	})
+})
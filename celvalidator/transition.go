@@ -0,0 +1,40 @@
+package celvalidator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// oldVariable names the variable holding the previous object in transitions
+const oldVariable = "old"
+
+// ValidateTransition evaluates Update rules against the change of an object
+// from oldObj to newObj. The fields of newObj are declared as with Validate,
+// the whole of it as self and the previous object as old, so rules can express
+// immutability and state machines, e.g. old.Status == 'draft' || Status == old.Status.
+// Both objects must have the same type.
+func (v *Validator) ValidateTransition(oldObj, newObj any, rules []RuleEntry, metadata ValidationMetadata) ([]ValidationResult, error) {
+	return v.ValidateTransitionContext(context.Background(), oldObj, newObj, rules, metadata)
+}
+
+// ValidateTransitionContext is ValidateTransition with a context, like ValidateContext
+func (v *Validator) ValidateTransitionContext(ctx context.Context, oldObj, newObj any, rules []RuleEntry, metadata ValidationMetadata) (results []ValidationResult, err error) {
+	metadata = v.identify(newObj, metadata)
+	if v.recover {
+		defer v.recoverValidation(metadata, &results, &err)
+	}
+	if oldObj == nil || reflect.TypeOf(oldObj) != reflect.TypeOf(newObj) {
+		return nil, fmt.Errorf("transition from %T to %T", oldObj, newObj)
+	}
+
+	fields := v.objectFields(newObj)
+	fields[selfVariable] = nestStruct(newObj, v.fieldNaming)
+	fields[oldVariable] = nestStruct(oldObj, v.fieldNaming)
+	v.addRequestVars(ctx, fields)
+	env, vars, err := v.buildFieldsEnv(newObj, fields)
+	if err != nil {
+		return nil, err
+	}
+	return v.validate(ctx, env, vars, rules, metadata)
+}
@@ -0,0 +1,57 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transition rules", func() {
+	type Document struct {
+		Status  string
+		Owner   string
+		Version int
+		Address Address
+	}
+	md := ValidationMetadata{StructName: "Document", Operation: "Update"}
+	rules := []RuleEntry{
+		{ID: "owner-immutable", Rule: "Owner == old.Owner", Enabled: true},
+		{ID: "status", Rule: "old.Status == 'draft' || Status == old.Status", Enabled: true},
+		{ID: "version", Rule: "self.Version > old.Version", Enabled: true},
+		{ID: "city", Rule: "Address.City == old.Address.City", Enabled: true},
+	}
+
+	It("binds the previous object as old and the new one as self", func() {
+		v := NewValidator(WithPartialEval())
+		before := Document{Status: "draft", Owner: "ann", Version: 1, Address: Address{City: "Oslo"}}
+		results, err := v.ValidateTransition(before, Document{Status: "published", Owner: "ann", Version: 2, Address: Address{City: "Oslo"}}, rules, md)
+		Expect(err).To(BeNil())
+		for _, res := range results {
+			Expect(res.Error).To(BeNil())
+			Expect(res.Passed).To(BeTrue(), res.Rule)
+		}
+
+		before.Status = "published"
+		results, err = v.ValidateTransition(&before, &Document{Status: "draft", Owner: "bob", Version: 1, Address: Address{City: "Rome"}}, rules, md)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(4))
+		for _, res := range results {
+			Expect(res.Passed).To(BeFalse(), res.Rule)
+		}
+	})
+
+	It("keeps transition environments apart from plain validations", func() {
+		v := NewValidator()
+		_, err := v.ValidateTransition(Document{}, Document{}, rules[:1], md)
+		Expect(err).To(BeNil())
+		results, err := v.Validate(Document{}, []RuleEntry{{Rule: "old.Owner == ''", Enabled: true}}, md)
+		Expect(err).To(MatchError(ContainSubstring("undeclared reference to 'old'")))
+		Expect(results).To(HaveLen(1))
+	})
+
+	It("rejects objects of different types", func() {
+		_, err := NewValidator().ValidateTransition(Address{}, Document{}, rules, md)
+		Expect(err).To(MatchError(ContainSubstring("transition from celvalidator.Address to")))
+		_, err = NewValidator().ValidateTransition(nil, Document{}, rules, md)
+		Expect(err).To(HaveOccurred())
+	})
+})
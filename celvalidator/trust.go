@@ -0,0 +1,176 @@
+package celvalidator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+)
+
+// ErrFunctionDenied is reported on the result of a rule calling a function
+// denied by the security profile of the validator
+var ErrFunctionDenied = errors.New("function denied")
+
+// TrustLevel classifies a rule source
+type TrustLevel string
+
+const (
+	// TrustTrusted sources, e.g. rule files shipped with the service, get the full environment
+	TrustTrusted TrustLevel = "trusted"
+	// TrustUntrusted sources, e.g. tenant-submitted rules, are evaluated under a security profile
+	TrustUntrusted TrustLevel = "untrusted"
+)
+
+// TrustAnnotation is the metadata annotation reporting the trust level of the
+// source of the rules
+const TrustAnnotation = "celvalidator/trust"
+
+// SecurityProfile restricts what rules can do. Cost limits only tighten those
+// of the validator, 0 keeping them.
+type SecurityProfile struct {
+	RuleCostLimit  uint64
+	TotalCostLimit uint64
+	// DeniedFunctions names the CEL functions rules may not call, e.g. matches
+	DeniedFunctions []string
+	// DenyResolvers denies the functions registered with WithResolver, which
+	// reach external systems
+	DenyResolvers bool
+}
+
+// StrictSecurityProfile is the profile applied to untrusted rules by default:
+// bounded cost, no regular expressions and no resolvers
+func StrictSecurityProfile() SecurityProfile {
+	return SecurityProfile{
+		RuleCostLimit:   10_000,
+		TotalCostLimit:  100_000,
+		DeniedFunctions: []string{"matches"},
+		DenyResolvers:   true,
+	}
+}
+
+// WithSecurityProfile restricts the rules evaluated by the validator to profile.
+// A rule calling a denied function fails like a rule that does not compile,
+// with ErrFunctionDenied.
+func WithSecurityProfile(profile SecurityProfile) ValidatorOption {
+	return func(v *Validator) {
+		v.ruleCostLimit = minLimit(v.ruleCostLimit, profile.RuleCostLimit)
+		v.totalCostLimit = minLimit(v.totalCostLimit, profile.TotalCostLimit)
+		denied := make(map[string]bool, len(v.deniedFunctions)+len(profile.DeniedFunctions))
+		for name := range v.deniedFunctions {
+			denied[name] = true
+		}
+		for _, name := range profile.DeniedFunctions {
+			denied[name] = true
+		}
+		v.deniedFunctions = denied
+		v.denyResolvers = v.denyResolvers || profile.DenyResolvers
+	}
+}
+
+// minLimit returns the lower of two limits, 0 meaning no limit
+func minLimit(a, b uint64) uint64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// deniedCall returns ErrFunctionDenied when the checked rule calls a function
+// denied to the validator
+func (v *Validator) deniedCall(checked *cel.Ast) error {
+	if len(v.deniedFunctions) == 0 && !v.denyResolvers {
+		return nil
+	}
+	var denied string
+	ast.PreOrderVisit(checked.NativeRep().Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+		if denied != "" || e.Kind() != ast.CallKind {
+			return
+		}
+		if name := e.AsCall().FunctionName(); v.deniedFunctions[name] || (v.denyResolvers && v.isResolver(name)) {
+			denied = name
+		}
+	}))
+	if denied != "" {
+		return fmt.Errorf("%w: %s", ErrFunctionDenied, denied)
+	}
+	return nil
+}
+
+// isResolver reports whether name is a function registered with WithResolver
+func (v *Validator) isResolver(name string) bool {
+	return slices.ContainsFunc(v.resolvers, func(r resolver) bool {
+		return r.name == name
+	})
+}
+
+// CompositeProvider serves the rules of several providers, each with a trust
+// level. Rules of untrusted sources are always evaluated under the security
+// profile of the composite.
+type CompositeProvider struct {
+	trusted   *Validator
+	untrusted *Validator
+
+	mu      sync.Mutex
+	sources []compositeSource
+}
+
+type compositeSource struct {
+	provider *RuleProvider
+	trust    TrustLevel
+}
+
+// NewCompositeProvider creates a composite validating trusted rules with a
+// validator configured by opts, and untrusted rules with one also applying
+// profile, e.g. StrictSecurityProfile()
+func NewCompositeProvider(profile SecurityProfile, opts ...ValidatorOption) *CompositeProvider {
+	return &CompositeProvider{
+		trusted:   NewValidator(opts...),
+		untrusted: NewValidator(append(slices.Clip(opts), WithSecurityProfile(profile))...),
+	}
+}
+
+// Add adds a rule source. Sources of any trust level other than TrustTrusted
+// are untrusted.
+func (p *CompositeProvider) Add(provider *RuleProvider, trust TrustLevel) {
+	if trust != TrustTrusted {
+		trust = TrustUntrusted
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sources = append(p.sources, compositeSource{provider: provider, trust: trust})
+}
+
+// Validate validates obj for an operation with the rules of every source, in
+// the order they were added, reporting the trust level of each source under
+// TrustAnnotation in the results metadata. It stops at the first source
+// returning an error, along with the results so far.
+func (p *CompositeProvider) Validate(obj any, operation string) ([]ValidationResult, error) {
+	p.mu.Lock()
+	sources := slices.Clone(p.sources)
+	p.mu.Unlock()
+
+	var results []ValidationResult
+	for _, source := range sources {
+		snapshot := source.provider.Snapshot()
+		rules := snapshot.RulesFor(obj, operation)
+		if len(rules) == 0 {
+			continue
+		}
+		validator := p.trusted
+		if source.trust == TrustUntrusted {
+			validator = p.untrusted
+		}
+		metadata := MetadataBuilderFrom(snapshot.Metadata(obj, operation)).
+			WithAnnotation(TrustAnnotation, string(source.trust)).
+			Build()
+		res, err := validator.Validate(obj, rules, metadata)
+		results = append(results, res...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,86 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rule source trust", func() {
+	regexRule := RuleEntry{Rule: "Email.matches('^[a-z]+@')", Enabled: true}
+	sourceWith := func(rules ...RuleEntry) *RuleProvider {
+		return NewRuleProvider(RuleSetMap{"Sample": {"Default": rules}})
+	}
+
+	It("restricts untrusted sources to the security profile", func() {
+		composite := NewCompositeProvider(StrictSecurityProfile(), WithPartialEval())
+		composite.Add(sourceWith(regexRule), TrustTrusted)
+		composite.Add(sourceWith(RuleEntry{ID: "tenant", Rule: regexRule.Rule, Enabled: true}), TrustUntrusted)
+
+		results, err := composite.Validate(Sample{Email: "ann@example.com"}, "Default")
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[0].Metadata.Annotations).To(HaveKeyWithValue(TrustAnnotation, "trusted"))
+		Expect(results[1].Error).To(MatchError(ErrFunctionDenied))
+		Expect(results[1].Outcome).To(Equal(OutcomeError))
+		Expect(results[1].Metadata.Annotations).To(HaveKeyWithValue(TrustAnnotation, "untrusted"))
+	})
+
+	It("treats sources of unknown trust as untrusted", func() {
+		composite := NewCompositeProvider(StrictSecurityProfile())
+		composite.Add(sourceWith(regexRule), "")
+		_, err := composite.Validate(Sample{Email: "ann@example.com"}, "Default")
+		Expect(err).To(MatchError(ErrFunctionDenied))
+	})
+
+	It("applies the cost limits of the profile", func() {
+		expensive := RuleEntry{Rule: "[1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(a, [1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(b, a + b > Age))", Enabled: true}
+		composite := NewCompositeProvider(SecurityProfile{RuleCostLimit: 50}, WithPartialEval())
+		composite.Add(sourceWith(expensive), TrustUntrusted)
+		results, err := composite.Validate(Sample{}, "Default")
+		Expect(err).To(BeNil())
+		Expect(results[0].Error).To(MatchError(ErrRuleCostLimit))
+	})
+
+	It("denies resolvers", func() {
+		v := NewValidator(
+			WithResolver("isBlocked", 1, func(rc ResolverContext, args []any) (any, error) { return false, nil }),
+			WithSecurityProfile(SecurityProfile{DenyResolvers: true}),
+		)
+		_, err := v.Validate(Sample{Email: "x"}, []RuleEntry{{Rule: "!isBlocked(Email)", Enabled: true}}, ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(MatchError(ContainSubstring("isBlocked")))
+		Expect(err).To(MatchError(ErrFunctionDenied))
+	})
+
+	It("denies functions in message and suggestion expressions", func() {
+		called := false
+		v := NewValidator(
+			WithResolver("lookup", 1, func(rc ResolverContext, args []any) (any, error) {
+				called = true
+				return "secret", nil
+			}),
+			WithSecurityProfile(StrictSecurityProfile()),
+		)
+		results, err := v.Validate(Sample{Email: "x"}, []RuleEntry{{
+			Rule:                 "Email == ''",
+			Enabled:              true,
+			FailureMessage:       "not empty",
+			MessageExpression:    "'leak: ' + string(lookup(Email)) + string(Email.matches('x.*'))",
+			SuggestionExpression: "lookup(Email)",
+		}}, ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+		Expect(called).To(BeFalse())
+		Expect(results[0].Message).To(Equal("not empty"))
+		Expect(results[0].SuggestedValue).To(BeNil())
+		Expect(results[0].Issues).To(ContainElements(
+			HaveField("Message", ContainSubstring("message_expression: function denied")),
+			HaveField("Message", ContainSubstring("suggestion_expression: function denied")),
+		))
+	})
+
+	It("only tightens cost limits", func() {
+		v := NewValidator(WithCostLimit(100, 0), WithSecurityProfile(SecurityProfile{RuleCostLimit: 1000, TotalCostLimit: 500}))
+		Expect(v.ruleCostLimit).To(Equal(uint64(100)))
+		Expect(v.totalCostLimit).To(Equal(uint64(500)))
+	})
+})
@@ -1,7 +1,14 @@
 package celvalidator
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
 	"reflect"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/checker/decls"
@@ -10,10 +17,130 @@ import (
 
 // RuleEntry defines a CEL rule with optional dependent rules
 type RuleEntry struct {
-	Rule           string      `yaml:"rule"`
-	Enabled        bool        `yaml:"enabled"`
-	FailureMessage string      `yaml:"message,omitempty"`
-	Then           []RuleEntry `yaml:"then,omitempty"`
+	ID string `yaml:"id,omitempty"`
+	// Override makes the entry adjust the Then child with that ID instead of
+	// being a rule, see GetRulesFor
+	Override       string `yaml:"override,omitempty"`
+	Rule           string `yaml:"rule"`
+	Enabled        bool   `yaml:"enabled"`
+	FailureMessage string `yaml:"message,omitempty"`
+	SuccessMessage string `yaml:"success_message,omitempty"`
+	// Messages holds translations of the failure message keyed by locale. Like
+	// FailureMessage and SuccessMessage they may reference fields as {Field},
+	// e.g. "must be adult, got {Age}", rendered with the values of the object.
+	Messages map[string]string `yaml:"messages,omitempty"`
+	// MessageExpression is a CEL string expression evaluated on failure, e.g.
+	// 'quota exceeded by ' + string(Used - Limit), replacing the failure message
+	MessageExpression string `yaml:"message_expression,omitempty"`
+	// Suggestion is a fix-it hint reported on failure, SuggestionExpression a CEL
+	// expression evaluated on failure to compute a suggested value and DocURL a
+	// page explaining how to fix the failure
+	Suggestion           string   `yaml:"suggestion,omitempty"`
+	SuggestionExpression string   `yaml:"suggestion_expression,omitempty"`
+	DocURL               string   `yaml:"doc_url,omitempty"`
+	Severity             Severity `yaml:"severity,omitempty"`
+	Weight               float64  `yaml:"weight,omitempty"`
+	// SampleRate runs an advisory rule, of warning or info severity, on only
+	// this fraction (0 to 1) of evaluations; unset runs it on every evaluation
+	SampleRate float64 `yaml:"sample_rate,omitempty"`
+	// Timeout bounds the evaluation of the rule, e.g. 100ms
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// LintWaivers names the lint checks the rule is exempt from, e.g. max_length
+	LintWaivers []string `yaml:"lint_waivers,omitempty"`
+	// Owner is the team responsible for the rule, alerted by a FailureRouter,
+	// Description explains the intent of the rule and Annotations carries
+	// arbitrary metadata, e.g. a runbook or ticket, all attached to results
+	Owner       string            `yaml:"owner,omitempty"`
+	Description string            `yaml:"description,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	// FieldPath is the request field failures are attributed to, e.g.
+	// Address.City, by default the object fields the rule references
+	FieldPath string `yaml:"field_path,omitempty"`
+	// Deprecated rules still run but their results are flagged; past Sunset
+	// the rule file no longer loads
+	Deprecated bool      `yaml:"deprecated,omitempty"`
+	Sunset     time.Time `yaml:"sunset,omitempty"`
+	// Examples holds sample field values the rule passes and fails, rendered by
+	// WriteRuleDocs and checked by VerifyExamples
+	Examples RuleExamples `yaml:"examples,omitempty"`
+	Then     []RuleEntry  `yaml:"then,omitempty"`
+	Source   RuleSource   `yaml:"-"`
+
+	// keepEnabled marks an override decoded without an enabled key, which
+	// leaves the enabled flag of the child unchanged
+	keepEnabled bool
+}
+
+// Severity classifies how serious a rule failure is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Rank orders severities, info < warning < error. Unset is treated as error.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarning:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// severity returns the rule severity, defaulting to error
+func (r RuleEntry) severity() Severity {
+	if r.Severity == "" {
+		return SeverityError
+	}
+	return r.Severity
+}
+
+// key identifies the rule for deduplication, by ID when set so rules keep
+// their identity when their expression changes
+func (r RuleEntry) key() string {
+	return ruleKey(r.ID, r.Rule)
+}
+
+// expressions returns the CEL expressions evaluated for the rule: the rule
+// itself and its message and suggestion expressions when set
+func (r RuleEntry) expressions() []string {
+	exprs := []string{r.Rule}
+	if r.MessageExpression != "" {
+		exprs = append(exprs, r.MessageExpression)
+	}
+	if r.SuggestionExpression != "" {
+		exprs = append(exprs, r.SuggestionExpression)
+	}
+	return exprs
+}
+
+// ruleKey returns "\x00" + id for rules with an ID, and rule otherwise
+func ruleKey(id, rule string) string {
+	if id != "" {
+		return "\x00" + id
+	}
+	return rule
+}
+
+// weight returns the rule weight used by weighted decision policies, defaulting to 1
+func (r RuleEntry) weight() float64 {
+	if r.Weight == 0 {
+		return 1
+	}
+	return r.Weight
+}
+
+// RuleSource records where a rule was defined
+type RuleSource struct {
+	File    string
+	Path    string
+	Line    int
+	Version string
 }
 
 // RuleSetMap maps StructName -> Operation -> Rules
@@ -23,30 +150,126 @@ type RuleSetMap map[string]map[string][]RuleEntry
 type ValidationMetadata struct {
 	StructName string
 	Operation  string
-	ChainPath  string
-	RuleIndex  int
+	// ObjectID is the business identifier of the validated object, see WithIDExtractor
+	ObjectID  string
+	ChainPath string
+	// RuleIndex is the index of the rule in its list, the top-level rules or the
+	// Then children of its parent
+	RuleIndex int
+	// RuleID is the ID of the rule, if any
+	RuleID     string
 	ParentRule string
+	// ParentRuleID is the ID of the parent of a Then child, if any
+	ParentRuleID string
+	// ParentIndexes holds the RuleIndex of each ancestor of a Then child, from
+	// the top-level rule down, and is empty for top-level rules
+	ParentIndexes []int
+	// Annotations carry embedding application context through nested validations
+	Annotations map[string]string
 }
 
 // ValidationResult represents the outcome of a single rule evaluation
 type ValidationResult struct {
+	// ID is the ID of the rule, if any
+	ID       string
 	Rule     string
 	Passed   bool
 	Error    error
 	Message  string
-	Metadata ValidationMetadata
+	Severity Severity
+	Weight   float64
+	Outcome  Outcome
+	// Cost is the actual CEL cost of evaluating the rule
+	Cost uint64
+	// Issues holds the compile errors and warnings of the rule
+	Issues []RuleIssue
+	// Suggestion, SuggestedValue and DocURL carry the fix-it hint of a failed rule
+	Suggestion     string
+	SuggestedValue any
+	DocURL         string
+	Metadata       ValidationMetadata
+	Source         RuleSource
+	// Owner, Description and Annotations are those of the rule, for triage
+	Owner       string
+	Description string
+	Annotations map[string]string
+	// Deprecated reports that the rule is deprecated and due to be retired
+	Deprecated bool
+	// FieldPaths holds the request fields a failure is attributed to, so API
+	// layers can point a 422 response at them
+	FieldPaths []string
 }
 
+// Outcome classifies a result independently of how Passed and Error combine
+type Outcome string
+
+const (
+	OutcomePassed Outcome = "passed"
+	OutcomeFailed Outcome = "failed"
+	// OutcomeError means the rule could not be evaluated
+	OutcomeError Outcome = "error"
+	// OutcomeSampledOut means the rule was left out by its sample rate. Such
+	// results are Passed so they never block the object.
+	OutcomeSampledOut Outcome = "sampled_out"
+)
+
+// ErrorPolicy selects the outcome of rules failing with a runtime error
+type ErrorPolicy int
+
+const (
+	// ErrorsAsSystemErrors reports runtime errors with OutcomeError, the default
+	ErrorsAsSystemErrors ErrorPolicy = iota
+	// ErrorsAsFailures reports runtime errors, e.g. an index out of range, with
+	// OutcomeFailed as if the object were invalid. Compile errors keep OutcomeError.
+	ErrorsAsFailures
+)
+
 // Validator encapsulates options for validation
 type Validator struct {
-	partialEval bool
+	partialEval       bool
+	successMessages   bool
+	nestedFields      bool
+	self              bool
+	checkedArith      bool
+	decimal           bool
+	money             bool
+	resolvers         []resolver
+	variableResolvers []variableResolver
+	auditSink         AuditSink
+	errorPolicy       ErrorPolicy
+	failFast          bool
+	optimizer         *RuleOptimizer
+	snapshotPolicy    SnapshotPolicy
+	nativeVariable    string
+	envs              *envCache
+	programs          *programCache
+	concurrency       int
+	batchConcurrency  int
+	ruleTimeout       time.Duration
+	ruleCostLimit     uint64
+	totalCostLimit    uint64
+	recover           bool
+	versions          map[string]VersionMapping
+	stdHelpers        bool
+	extensions        []cel.EnvOption
+	finiteFloats      bool
+	failureRouter     *FailureRouter
+	fieldNaming       FieldNaming
+	methods           bool
+	metrics           Metrics
+	globals           map[string]any
+	clock             Clock
+	idExtractor       IDExtractor
+	deniedFunctions   map[string]bool
+	denyResolvers     bool
+	debugRecording    bool
 }
 
 type ValidatorOption func(*Validator)
 
 // New creates a new Validator
 func NewValidator(opts ...ValidatorOption) *Validator {
-	v := &Validator{}
+	v := &Validator{envs: newEnvCache(), programs: newProgramCache(defaultProgramCacheSize)}
 	for _, opt := range opts {
 		opt(v)
 	}
@@ -60,106 +283,396 @@ func WithPartialEval() ValidatorOption {
 	}
 }
 
+// WithSuccessMessages populates Message on passing results from the rule's SuccessMessage
+func WithSuccessMessages() ValidatorOption {
+	return func(v *Validator) {
+		v.successMessages = true
+	}
+}
+
+// WithNestedFields declares nested structs as map variables instead of flattened
+// dotted names, so Address.City resolves through member selection and has() works
+func WithNestedFields() ValidatorOption {
+	return func(v *Validator) {
+		v.nestedFields = true
+	}
+}
+
+// selfVariable names the variable holding the whole object with WithSelf
+const selfVariable = "self"
+
+// WithSelf also exposes the whole object as the map variable self, so rules can
+// pass it to registered functions, e.g. checkQuota(self), or test has(self.Field).
+// It shadows a top-level field named self.
+func WithSelf() ValidatorOption {
+	return func(v *Validator) {
+		v.self = true
+	}
+}
+
+// WithErrorPolicy selects the outcome of rules failing with a runtime error
+func WithErrorPolicy(p ErrorPolicy) ValidatorOption {
+	return func(v *Validator) {
+		v.errorPolicy = p
+	}
+}
+
 // Validate evaluates rules and returns results with structured context
 func (v *Validator) Validate(
 	obj any,
 	rules []RuleEntry,
 	metadata ValidationMetadata,
 ) ([]ValidationResult, error) {
-	results := []ValidationResult{}
-	env, vars, err := v.buildEnv(obj)
+	return v.ValidateContext(context.Background(), obj, rules, metadata)
+}
+
+// ValidateContext evaluates rules like Validate, propagating ctx (deadline,
+// caller identity) to resolvers called by the rules and declaring its request
+// variables, see WithRequestVars. Evaluation stops when ctx is done, between
+// rules and within comprehensions, returning the results so far along with the
+// context error.
+func (v *Validator) ValidateContext(
+	ctx context.Context,
+	obj any,
+	rules []RuleEntry,
+	metadata ValidationMetadata,
+) (results []ValidationResult, err error) {
+	metadata = v.identify(obj, metadata)
+	if v.recover {
+		defer v.recoverValidation(metadata, &results, &err)
+	}
+	fields := v.objectFields(obj)
+	v.addRequestVars(ctx, fields)
+	env, vars, err := v.buildFieldsEnv(obj, fields)
 	if err != nil {
 		return nil, err
 	}
+	return v.validate(ctx, env, vars, rules, metadata)
+}
 
-	seen := map[string]bool{}
+// validate evaluates rules against variables declared in env
+func (v *Validator) validate(
+	ctx context.Context,
+	env *cel.Env,
+	vars map[string]any,
+	rules []RuleEntry,
+	metadata ValidationMetadata,
+) ([]ValidationResult, error) {
+	e := v.newEvaluation(ctx, env, vars, metadata)
+	err := e.evalRules(rules, metadata)
+	return e.results, e.finish(err)
+}
 
-	var eval func(entries []RuleEntry, metadata ValidationMetadata) error
-	eval = func(entries []RuleEntry, metadata ValidationMetadata) error {
-		for i, entry := range entries {
-			if !entry.Enabled || seen[entry.Rule] {
-				continue
-			}
-			seen[entry.Rule] = true
-
-			ast, iss := env.Compile(entry.Rule)
-			if iss != nil && iss.Err() != nil {
-				results = append(results, ValidationResult{
-					Rule:   entry.Rule,
-					Passed: false,
-					Error:  iss.Err(),
-					Metadata: ValidationMetadata{
-						StructName: metadata.StructName,
-						Operation:  metadata.Operation,
-						ChainPath:  metadata.ChainPath + " > compileError",
-						RuleIndex:  i,
-						ParentRule: metadata.ParentRule,
-					},
-				})
-				if !v.partialEval {
-					return iss.Err()
-				}
-				continue
-			}
+// evaluation holds the state of a single validation
+type evaluation struct {
+	v        *Validator
+	ctx      context.Context
+	env      *cel.Env
+	fields   map[string]any
+	vars     map[string]any
+	prgOpts  []cel.ProgramOption
+	locale   string
+	metadata ValidationMetadata
+	seen     map[string]bool
+	results  []ValidationResult
+	// compiled holds rules compiled ahead of time by a CompiledRuleSet
+	compiled map[string]*compiledRule
+	// cost is the actual cost of the rules evaluated, shared with forks
+	cost *atomic.Uint64
+	// nonFinite holds the paths of NaN and infinite fields with WithFiniteFloats
+	nonFinite []string
+	start     time.Time
+}
 
-			prg, err := env.Program(ast)
-			if err != nil {
-				results = append(results, ValidationResult{
-					Rule:   entry.Rule,
-					Passed: false,
-					Error:  err,
-					Metadata: ValidationMetadata{
-						StructName: metadata.StructName,
-						Operation:  metadata.Operation,
-						ChainPath:  metadata.ChainPath + " > programError",
-						RuleIndex:  i,
-						ParentRule: metadata.ParentRule,
-					},
-				})
-				if !v.partialEval {
-					return err
-				}
-				continue
-			}
+func (v *Validator) newEvaluation(ctx context.Context, env *cel.Env, vars map[string]any, metadata ValidationMetadata) *evaluation {
+	prgOpts := v.programOptions()
+	if len(v.resolvers) > 0 {
+		prgOpts = append(prgOpts, v.resolverBindings(ctx, metadata))
+	}
+	var nonFinite []string
+	if v.finiteFloats {
+		nonFinite = nonFiniteFields(vars)
+	}
+	return &evaluation{
+		v:         v,
+		ctx:       ctx,
+		env:       env,
+		fields:    vars,
+		vars:      v.withLazyVariables(ctx, v.withGlobals(vars), metadata),
+		prgOpts:   prgOpts,
+		locale:    LocaleFrom(ctx),
+		metadata:  metadata,
+		seen:      map[string]bool{},
+		results:   []ValidationResult{},
+		cost:      &atomic.Uint64{},
+		nonFinite: nonFinite,
+		start:     time.Now(),
+	}
+}
 
-			out, _, err := prg.Eval(vars)
-			passed := err == nil && out.Value() == true
-			validationResult := ValidationResult{
-				Rule:   entry.Rule,
-				Passed: passed,
-				Error:  err,
-				Metadata: ValidationMetadata{
-					StructName: metadata.StructName,
-					Operation:  metadata.Operation,
-					ChainPath:  metadata.ChainPath,
-					RuleIndex:  i,
-					ParentRule: metadata.ParentRule,
-				},
-			}
-			if !passed {
-				validationResult.Message = entry.FailureMessage
-			}
+// eval evaluates entries and the Then children of those passing
+func (e *evaluation) eval(entries []RuleEntry, metadata ValidationMetadata) error {
+	for _, i := range e.v.evalOrder(e.env, metadata.StructName, entries) {
+		if err := e.evalEntry(i, entries[i], metadata); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			results = append(results, validationResult)
-
-			if passed && len(entry.Then) > 0 {
-				childMetadata := ValidationMetadata{
-					StructName: metadata.StructName,
-					Operation:  metadata.Operation,
-					ChainPath:  extendChainPath(metadata.ChainPath, "then"),
-					RuleIndex:  -1,
-					ParentRule: entry.Rule,
-				}
-				if err := eval(entry.Then, childMetadata); err != nil && !v.partialEval {
-					return err
-				}
-			}
+// evalEntry evaluates the entry at index i of the current chain
+func (e *evaluation) evalEntry(i int, entry RuleEntry, metadata ValidationMetadata) (err error) {
+	v := e.v
+	if !entry.Enabled || e.seen[entry.key()] {
+		return nil
+	}
+	metadata.RuleID = entry.ID
+	if v.recover {
+		defer e.recoverEntry(i, entry, metadata, &err)
+	}
+	if err := e.ctx.Err(); err != nil {
+		return err
+	}
+	e.seen[entry.key()] = true
+
+	if e.sampledOut(entry, metadata) {
+		result := newResult(entry, metadata, i)
+		result.Passed = true
+		result.Outcome = OutcomeSampledOut
+		e.results = append(e.results, result)
+		return nil
+	}
+
+	c := e.compileRule(entry.Rule)
+	if iss := c.iss; iss != nil && iss.Err() != nil {
+		result := newResult(entry, metadata, i)
+		result.Error = compileError{iss.Err()}
+		result.Outcome = OutcomeError
+		result.Issues = compileIssues(iss)
+		result.Metadata = metadata.at(i, metadata.ChainPath+" > compileError")
+		e.results = append(e.results, result)
+		if !v.partialEval {
+			return iss.Err()
+		}
+		if v.failFast {
+			return errFailFast
+		}
+		return nil
+	}
+
+	if err := v.deniedCall(c.ast); err != nil {
+		result := newResult(entry, metadata, i)
+		result.Error = compileError{err}
+		result.Outcome = OutcomeError
+		result.Metadata = metadata.at(i, metadata.ChainPath+" > compileError")
+		e.results = append(e.results, result)
+		if !v.partialEval {
+			return err
+		}
+		if v.failFast {
+			return errFailFast
+		}
+		return nil
+	}
+
+	warnings := c.warnings
+	if err := e.nonFiniteError(entry.Rule); err != nil {
+		result := newResult(entry, metadata, i)
+		result.Error = err
+		result.Outcome = v.outcome(false, err)
+		result.Issues = warnings
+		e.results = append(e.results, result)
+		if v.failFast {
+			return errFailFast
+		}
+		return nil
+	}
+
+	prg, err := e.program(c)
+	if err != nil {
+		result := newResult(entry, metadata, i)
+		result.Error = compileError{err}
+		result.Outcome = OutcomeError
+		result.Issues = warnings
+		result.Metadata = metadata.at(i, metadata.ChainPath+" > programError")
+		e.results = append(e.results, result)
+		if !v.partialEval {
+			return err
+		}
+		if v.failFast {
+			return errFailFast
 		}
 		return nil
 	}
 
-	err = eval(rules, metadata)
-	return results, err
+	out, details, err := e.evalProgram(prg, entry)
+	if ctxErr := e.ctx.Err(); err != nil && ctxErr != nil {
+		// Report the cause rather than cel-go's interruption error
+		err = ctxErr
+	}
+	err = v.costError(err)
+	if err == nil && v.checkedArith {
+		err = e.checkOverflow(c)
+	}
+	cost := actualCost(details)
+	passed := err == nil && out.Value() == true
+	validationResult := newResult(entry, metadata, i)
+	validationResult.Passed = passed
+	validationResult.Error = err
+	validationResult.Outcome = v.outcome(passed, err)
+	validationResult.Cost = cost
+	validationResult.Issues = warnings
+	if !passed {
+		validationResult.Message = e.renderMessage(entry.failureMessage(e.locale))
+		e.expressionMessage(entry, &validationResult)
+		e.suggest(entry, &validationResult)
+		validationResult.FieldPaths = e.fieldPaths(entry)
+	} else if v.successMessages {
+		validationResult.Message = e.renderMessage(entry.SuccessMessage)
+	}
+
+	e.results = append(e.results, validationResult)
+	if (errors.Is(err, ErrRuleTimeout) || errors.Is(err, ErrRuleCostLimit)) && !v.partialEval {
+		return err
+	}
+	if err := e.addCost(cost); err != nil {
+		return err
+	}
+	if !passed && v.failFast {
+		return errFailFast
+	}
+
+	if passed && len(entry.Then) > 0 {
+		childMetadata := metadata.child("then", entry.Rule, i)
+		if err := e.eval(entry.Then, childMetadata); err != nil && (!v.partialEval || errors.Is(err, errFailFast) || errors.Is(err, ErrTotalCostLimit) || e.ctx.Err() != nil) {
+			return err
+		}
+	}
+	return nil
+}
+
+// newResult returns a failed result of the entry at index i of the chain,
+// carrying the rule's identity, ownership and metadata
+func newResult(entry RuleEntry, metadata ValidationMetadata, i int) ValidationResult {
+	return ValidationResult{
+		ID:          entry.ID,
+		Rule:        entry.Rule,
+		Severity:    entry.severity(),
+		Weight:      entry.weight(),
+		Source:      entry.Source,
+		Owner:       entry.Owner,
+		Description: entry.Description,
+		Annotations: maps.Clone(entry.Annotations),
+		Deprecated:  entry.deprecated(),
+		Metadata:    metadata.at(i, metadata.ChainPath),
+	}
+}
+
+// compiledRule is a checked rule with its warnings and, when it does not
+// depend on per-validation bindings, its programs
+type compiledRule struct {
+	ast      *cel.Ast
+	iss      *cel.Issues
+	warnings []RuleIssue
+	prg      cel.Program
+	// tracked is the program tracking evaluation state for checked arithmetic
+	tracked cel.Program
+}
+
+// compileRule returns the compiled rule, compiling it unless done ahead of time or cached
+func (e *evaluation) compileRule(rule string) *compiledRule {
+	if c, ok := e.compiled[rule]; ok {
+		return c
+	}
+	return e.cachedCompileRule(rule)
+}
+
+func compileRule(env *cel.Env, rule string, vars map[string]any) *compiledRule {
+	ast, iss := env.Compile(rule)
+	c := &compiledRule{ast: ast, iss: iss}
+	if iss == nil || iss.Err() == nil {
+		c.warnings = ruleWarnings(ast, vars)
+	}
+	return c
+}
+
+// program returns the program of a compiled rule
+func (e *evaluation) program(c *compiledRule) (cel.Program, error) {
+	if c.prg != nil {
+		return c.prg, nil
+	}
+	return e.env.Program(c.ast, e.prgOpts...)
+}
+
+// finish records the results of the evaluation, err being the evaluation error
+func (e *evaluation) finish(err error) error {
+	if errors.Is(err, errFailFast) {
+		err = nil
+	}
+	if e.v.debugRecording {
+		debug.recordValidation(e.metadata, e.results)
+	}
+	if e.v.optimizer != nil {
+		e.v.optimizer.record(e.metadata.StructName, e.results)
+	}
+	if e.v.failureRouter != nil {
+		e.v.failureRouter.record(e.metadata, e.results)
+	}
+	if e.v.metrics != nil {
+		e.v.metrics.RecordValidation(e.metadata, e.results, time.Since(e.start))
+	}
+
+	if e.v.auditSink != nil {
+		record := newAuditRecord(e.metadata, e.results)
+		snapshot, auditErr := e.v.auditSnapshot(e.fields)
+		if auditErr == nil {
+			record.Object = snapshot
+			auditErr = e.v.auditSink.Record(record)
+		}
+		if auditErr != nil {
+			return errors.Join(err, auditErr)
+		}
+	}
+
+	return err
+}
+
+// outcome classifies an evaluated rule according to the error policy
+func (v *Validator) outcome(passed bool, err error) Outcome {
+	switch {
+	case passed:
+		return OutcomePassed
+	case err != nil && v.errorPolicy == ErrorsAsSystemErrors:
+		return OutcomeError
+	}
+	return OutcomeFailed
+}
+
+// at returns the metadata of the rule at index i of the current chain
+func (m ValidationMetadata) at(i int, chainPath string) ValidationMetadata {
+	m.RuleIndex = i
+	m.ChainPath = chainPath
+	return m
+}
+
+// child returns the metadata for rules chained under parentRule, at parentIndex
+// of the current chain
+func (m ValidationMetadata) child(step, parentRule string, parentIndex int) ValidationMetadata {
+	m.ChainPath = extendChainPath(m.ChainPath, step)
+	m.RuleIndex = -1
+	m.ParentRule = parentRule
+	m.ParentRuleID = m.RuleID
+	m.RuleID = ""
+	n := len(m.ParentIndexes)
+	m.ParentIndexes = append(m.ParentIndexes[:n:n], parentIndex)
+	return m
+}
+
+// IndexPath returns the position of the rule in the rule tree, the indexes of
+// its ancestors followed by its own, e.g. [2 0] for the first Then child of the
+// third top-level rule
+func (m ValidationMetadata) IndexPath() []int {
+	return append(m.ParentIndexes[:len(m.ParentIndexes):len(m.ParentIndexes)], m.RuleIndex)
 }
 
 func extendChainPath(current, next string) string {
@@ -169,139 +682,529 @@ func extendChainPath(current, next string) string {
 	return current + " > " + next
 }
 
-// GetRulesFor retrieves rules for a struct (default) + operation from the rule set
+// GetRulesFor retrieves rules for a struct (default) + operation from the rule set.
+// Operation entries with Override set are not rules, they replace the enabled
+// flag and message of the Default Then child with that ID. Overrides decoded
+// from YAML without an enabled key keep the child's flag. Rules keyed by the
+// name of a registered interface obj implements follow the struct's own rules.
 func GetRulesFor(obj any, operation string, rules RuleSetMap) []RuleEntry {
-	name := getStructName(obj)
-
-	var merged []RuleEntry
 	seen := map[string]bool{}
+	merged := appendStructRules(nil, seen, rules[getStructName(obj)], operation)
+	for _, name := range implementedInterfaces(obj) {
+		merged = appendStructRules(merged, seen, rules[name], operation)
+	}
+	return merged
+}
 
-	if structRules, ok := rules[name]; ok {
-		// Include Default rules if present
-		if defaultRules, ok := structRules["Default"]; ok {
-			for _, r := range defaultRules {
-				if _, exists := seen[r.Rule]; !exists && r.Enabled {
-					filtered := filterEnabledRules(r)
-					merged = append(merged, filtered)
-					seen[r.Rule] = true
-				}
-			}
+// GetRulesForStruct retrieves rules by struct name, for input without a Go type
+func GetRulesForStruct(name string, operation string, rules RuleSetMap) []RuleEntry {
+	return appendStructRules(nil, map[string]bool{}, rules[name], operation)
+}
+
+// appendStructRules appends the enabled Default and operation rules of a
+// struct, skipping rules already seen by ID, or by expression without one
+func appendStructRules(merged []RuleEntry, seen map[string]bool, structRules map[string][]RuleEntry, operation string) []RuleEntry {
+	if structRules == nil {
+		return merged
+	}
+	opRules := structRules[operation]
+
+	overrides := map[string]RuleEntry{}
+	for _, r := range opRules {
+		if r.Override != "" {
+			overrides[r.Override] = r
 		}
+	}
 
-		// Include specific operation rules
-		if opRules, ok := structRules[operation]; ok {
-			for _, r := range opRules {
-				if _, exists := seen[r.Rule]; !exists && r.Enabled {
-					filtered := filterEnabledRules(r)
-					merged = append(merged, filtered)
-					seen[r.Rule] = true
-				}
+	// Include Default rules if present
+	if defaultRules, ok := structRules["Default"]; ok {
+		for _, r := range defaultRules {
+			if _, exists := seen[r.key()]; !exists && r.Enabled && r.Override == "" {
+				filtered := filterEnabledRules(r, overrides)
+				merged = append(merged, filtered)
+				seen[r.key()] = true
 			}
 		}
 	}
 
+	// Include specific operation rules
+	for _, r := range opRules {
+		if _, exists := seen[r.key()]; !exists && r.Enabled && r.Override == "" {
+			filtered := filterEnabledRules(r, nil)
+			merged = append(merged, filtered)
+			seen[r.key()] = true
+		}
+	}
+
 	return merged
 }
 
-// filterEnabledRules returns a deep copy of a RuleEntry with only enabled nested rules
-func filterEnabledRules(rule RuleEntry) RuleEntry {
-	filtered := RuleEntry{
-		Rule:           rule.Rule,
-		Enabled:        rule.Enabled,
-		FailureMessage: rule.FailureMessage,
+var (
+	// ErrNoRulesForType is returned by the strict lookups when the rule set has no
+	// rules for the struct
+	ErrNoRulesForType = errors.New("no rules for type")
+	// ErrNoRulesForOperation is returned by the strict lookups when the struct has
+	// rules but none enabled apply to the operation
+	ErrNoRulesForOperation = errors.New("no rules for operation")
+)
+
+// GetRulesForStrict is GetRulesFor erroring with ErrNoRulesForType or
+// ErrNoRulesForOperation when nothing would be checked
+func GetRulesForStrict(obj any, operation string, rules RuleSetMap) ([]RuleEntry, error) {
+	name := getStructName(obj)
+	interfaces := implementedInterfaces(obj)
+	if !hasRulesFor(rules, name, interfaces) {
+		return nil, fmt.Errorf("%w %s", ErrNoRulesForType, name)
+	}
+	merged := GetRulesFor(obj, operation, rules)
+	if len(merged) == 0 {
+		return nil, fmt.Errorf("%w %s.%s", ErrNoRulesForOperation, name, operation)
+	}
+	return merged, nil
+}
+
+// hasRulesFor reports whether the rule set has rules for the struct or any of its interfaces
+func hasRulesFor(rules RuleSetMap, name string, interfaces []string) bool {
+	if _, ok := rules[name]; ok {
+		return true
+	}
+	for _, i := range interfaces {
+		if _, ok := rules[i]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRulesForStructStrict is GetRulesForStruct erroring with ErrNoRulesForType
+// or ErrNoRulesForOperation when nothing would be checked
+func GetRulesForStructStrict(name string, operation string, rules RuleSetMap) ([]RuleEntry, error) {
+	if _, ok := rules[name]; !ok {
+		return nil, fmt.Errorf("%w %s", ErrNoRulesForType, name)
+	}
+	merged := GetRulesForStruct(name, operation, rules)
+	if len(merged) == 0 {
+		return nil, fmt.Errorf("%w %s.%s", ErrNoRulesForOperation, name, operation)
 	}
+	return merged, nil
+}
+
+// filterEnabledRules returns a deep copy of a RuleEntry with only enabled nested
+// rules, after applying overrides to nested rules by ID
+func filterEnabledRules(rule RuleEntry, overrides map[string]RuleEntry) RuleEntry {
+	filtered := rule
+	filtered.Then = nil
 
 	for _, child := range rule.Then {
+		if o, ok := overrides[child.ID]; ok && child.ID != "" {
+			if !o.keepEnabled {
+				child.Enabled = o.Enabled
+			}
+			if o.FailureMessage != "" {
+				child.FailureMessage = o.FailureMessage
+			}
+			if o.SuccessMessage != "" {
+				child.SuccessMessage = o.SuccessMessage
+			}
+			if len(o.Messages) > 0 {
+				child.Messages = o.Messages
+			}
+			if o.MessageExpression != "" {
+				child.MessageExpression = o.MessageExpression
+			}
+		}
 		if child.Enabled {
-			filtered.Then = append(filtered.Then, filterEnabledRules(child))
+			filtered.Then = append(filtered.Then, filterEnabledRules(child, overrides))
 		}
 	}
 
 	return filtered
 }
 
-// NewValidationMetadata creates a context from struct type and rule set
-func NewValidationMetadata(obj any, operation string, rules RuleSetMap) ValidationMetadata {
-	structName := getStructName(obj)
-	op := operation
-
-	if structRules, ok := rules[structName]; ok {
-		if op == "" {
-			if len(structRules) == 1 {
-				for k := range structRules {
-					op = k
-				}
-			} else {
-				op = "Default"
-			}
-		}
-	} else {
-		op = "Default"
+// ErrAmbiguousOperation is returned in strict mode when no operation is given
+// and none can be selected unambiguously for the struct
+var ErrAmbiguousOperation = errors.New("ambiguous operation")
+
+// MetadataOption configures how validation metadata is resolved
+type MetadataOption func(*metadataConfig)
+
+type metadataConfig struct {
+	defaultOperations map[string]string
+	strict            bool
+	versions          map[string]VersionMapping
+}
+
+// WithDefaultOperations sets the operation to use per struct name when none is given
+func WithDefaultOperations(defaults map[string]string) MetadataOption {
+	return func(c *metadataConfig) {
+		c.defaultOperations = defaults
+	}
+}
+
+// WithStrictOperation disables operation guessing, an empty operation must be
+// resolved through a configured default operation
+func WithStrictOperation() MetadataOption {
+	return func(c *metadataConfig) {
+		c.strict = true
+	}
+}
+
+// NewValidationMetadata creates a context from struct type and rule set,
+// falling back to the "Default" operation when it cannot be resolved
+func NewValidationMetadata(obj any, operation string, rules RuleSetMap, opts ...MetadataOption) ValidationMetadata {
+	metadata, err := ResolveValidationMetadata(obj, operation, rules, opts...)
+	if err != nil {
+		metadata.Operation = "Default"
+	}
+	return metadata
+}
+
+// ResolveValidationMetadata creates a context from struct type and rule set,
+// returning ErrAmbiguousOperation in strict mode when no operation can be selected
+func ResolveValidationMetadata(obj any, operation string, rules RuleSetMap, opts ...MetadataOption) (ValidationMetadata, error) {
+	cfg := &metadataConfig{}
+	for _, opt := range opts {
+		opt(cfg)
 	}
 
-	return ValidationMetadata{
+	structName := getStructName(obj)
+	ruleName := logicalName(structName, cfg.versions)
+	metadata := ValidationMetadata{
 		StructName: structName,
-		Operation:  op,
+		Operation:  operation,
 		ChainPath:  "",
 		RuleIndex:  -1,
 		ParentRule: "",
 	}
+	if operation != "" {
+		return metadata, nil
+	}
+
+	if op, ok := cfg.defaultOperations[ruleName]; ok && op != "" {
+		metadata.Operation = op
+		return metadata, nil
+	}
+
+	structRules := rules[ruleName]
+	if cfg.strict {
+		// Only a struct without operation-specific rules is unambiguous
+		if _, hasDefault := structRules["Default"]; len(structRules) == 0 || (hasDefault && len(structRules) == 1) {
+			metadata.Operation = "Default"
+			return metadata, nil
+		}
+		return metadata, fmt.Errorf("%w: no default operation for %s", ErrAmbiguousOperation, structName)
+	}
+
+	if len(structRules) == 1 {
+		for k := range structRules {
+			metadata.Operation = k
+		}
+	} else {
+		metadata.Operation = "Default"
+	}
+
+	return metadata, nil
 }
 
 // buildEnv prepares the CEL environment and flattened variables
 func (v *Validator) buildEnv(obj any) (*cel.Env, map[string]any, error) {
-	fields := flattenStruct(obj)
-	declarations := make([]*expr.Decl, 0, len(fields))
-	for name, val := range fields {
-		declarations = append(declarations, decls.NewVar(name, inferType(val)))
+	return v.buildFieldsEnv(obj, v.objectFields(obj))
+}
+
+// buildFieldsEnv prepares the CEL environment declaring fields, the variables of obj
+func (v *Validator) buildFieldsEnv(obj any, fields map[string]any) (*cel.Env, map[string]any, error) {
+	var key envKey
+	if v.envs != nil {
+		key = envKey{typ: reflect.TypeOf(obj), shape: fieldsShape(fields)}
+		if env, ok := v.envs.get(key); ok {
+			v.addNativeVariable(fields, obj)
+			return env, fields, nil
+		}
+	}
+
+	opts := v.envOptions()
+	if v.methods && v.nativeVariable == "" {
+		return nil, nil, errMethodsWithoutNativeTypes
+	}
+	if v.nativeVariable != "" {
+		nativeOpts, err := v.nativeEnvOptions(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(nativeOpts, opts...)
 	}
-	env, err := cel.NewEnv(cel.Declarations(declarations...))
+	env, err := newEnvFromFields(fields, append(opts, v.globalDeclarations(declaredIn(fields))...)...)
 	if err != nil {
 		return nil, nil, err
 	}
+	if v.envs != nil {
+		v.envs.put(key, env)
+	}
+	v.addNativeVariable(fields, obj)
 	return env, fields, nil
 }
 
-// flattenStruct flattens struct fields (including nested)
-func flattenStruct(obj any) map[string]any {
-	result := make(map[string]any)
-	val := reflect.ValueOf(obj)
-	typ := reflect.TypeOf(obj)
+// objectFields returns the fields of obj declared as variables
+func (v *Validator) objectFields(obj any) map[string]any {
+	var fields map[string]any
+	if v.nestedFields {
+		fields = nestStruct(obj, v.fieldNaming)
+	} else {
+		fields = flattenStruct(obj, v.fieldNaming)
+	}
+	if len(v.versions) > 0 {
+		v.mapVersionFields(obj, fields)
+	}
+	if v.self {
+		fields[selfVariable] = nestStruct(obj, v.fieldNaming)
+	}
+	return fields
+}
 
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-		typ = typ.Elem()
+// envOptions returns the CEL environment options enabled on the validator
+func (v *Validator) envOptions() []cel.EnvOption {
+	var opts []cel.EnvOption
+	if v.decimal {
+		opts = append(opts, decimalLibrary())
+	}
+	if v.money {
+		opts = append(opts, moneyLibrary())
+	}
+	if v.stdHelpers {
+		opts = append(opts, helpersLibrary())
 	}
+	opts = append(opts, v.extensions...)
+	opts = append(opts, v.resolverDeclarations()...)
+	opts = append(opts, v.variableResolverDeclarations()...)
+	return opts
+}
+
+// envLib bundles environment options as a CEL library
+type envLib struct {
+	opts []cel.EnvOption
+}
+
+func (l envLib) CompileOptions() []cel.EnvOption {
+	return l.opts
+}
+
+func (envLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+// interruptCheckFrequency is the number of comprehension iterations between
+// checks of the validation context
+const interruptCheckFrequency = 100
+
+// programOptions returns the CEL program options enabled on the validator
+func (v *Validator) programOptions() []cel.ProgramOption {
+	opts := []cel.ProgramOption{cel.InterruptCheckFrequency(interruptCheckFrequency), cel.CostTracking(nil)}
+	if v.ruleCostLimit > 0 {
+		opts = append(opts, cel.CostLimit(v.ruleCostLimit))
+	}
+	return opts
+}
+
+// newEnvFromFields declares a CEL variable per flattened field
+func newEnvFromFields(fields map[string]any, opts ...cel.EnvOption) (*cel.Env, error) {
+	declarations := make([]*expr.Decl, 0, len(fields))
+	declared := make(map[string]bool, len(fields))
+	for name, val := range fields {
+		declarations = append(declarations, decls.NewVar(name, inferType(val)))
+		declared[name] = true
+	}
+	opts = append([]cel.EnvOption{cel.Declarations(declarations...), presenceMacro(declared)}, opts...)
+	return cel.NewEnv(append(opts, nullPointerAdapter())...)
+}
+
+// nestStruct converts struct fields to a map, nested structs becoming nested maps
+func nestStruct(obj any, naming FieldNaming) map[string]any {
+	return nestValue(indirect(reflect.ValueOf(obj)), naming)
+}
+
+func nestValue(val reflect.Value, naming FieldNaming) map[string]any {
+	result := make(map[string]any)
+	if val.Kind() != reflect.Struct {
+		return result
+	}
+	typ := val.Type()
 
 	for i := 0; i < val.NumField(); i++ {
 		field := typ.Field(i)
 		value := val.Field(i)
 
-		if !value.CanInterface() {
+		name, ok := naming.fieldName(field)
+		if !value.CanInterface() || !ok {
 			continue
 		}
 
-		name := field.Name
+		result[name] = celValue(value, naming)
+	}
+	return result
+}
+
+// indirect follows pointers and interfaces until a concrete or nil value
+func indirect(val reflect.Value) reflect.Value {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return val
+		}
+		val = val.Elem()
+	}
+	return val
+}
+
+// isValueStruct reports whether a struct is exposed as a single value (decimal,
+// money, timestamp) rather than flattened into its fields
+func isValueStruct(val reflect.Value) bool {
+	return isValueStructType(val.Type())
+}
+
+func isValueStructType(typ reflect.Type) bool {
+	return typ == ratType || typ == timeType || isMoneyType(typ)
+}
 
-		switch value.Kind() {
-		case reflect.Struct:
-			nested := flattenStruct(value.Interface())
-			for k, v := range nested {
-				result[name+"."+k] = v
+// celValue converts a field value to a value CEL can adapt: nil pointers become
+// null, structs become maps and slices of structs or pointers become lists of
+// them, slices of structs being typed lists of maps
+func celValue(val reflect.Value, naming FieldNaming) any {
+	val = indirect(val)
+
+	switch val.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		return nil
+	case reflect.Struct:
+		if val.Type() == ratType {
+			r := val.Interface().(big.Rat)
+			return newDecimal(&r)
+		}
+		if m, ok := moneyValue(val); ok {
+			return m
+		}
+		if val.Type() == timeType {
+			return val.Interface()
+		}
+		return nestValue(val, naming)
+	case reflect.Map:
+		if m, ok := mapValue(val, naming); ok {
+			return m
+		}
+	case reflect.Slice, reflect.Array:
+		if l, ok := structList(val, naming); ok {
+			return l
+		}
+		switch val.Type().Elem().Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Struct, reflect.Slice, reflect.Array:
+			list := make([]any, val.Len())
+			for i := range list {
+				list[i] = celValue(val.Index(i), naming)
 			}
-		default:
-			result[name] = value.Interface()
+			return list
 		}
 	}
-	return result
+	if v, ok := primitiveValue(val); ok {
+		return v
+	}
+	if l, ok := primitiveList(val); ok {
+		return l
+	}
+	return val.Interface()
+}
+
+// structList returns a slice of structs as a list of maps, typed as such unlike
+// lists of pointers which may hold null
+func structList(val reflect.Value, naming FieldNaming) ([]map[string]any, bool) {
+	if val.Kind() != reflect.Slice || val.Type().Elem().Kind() != reflect.Struct || isValueStructType(val.Type().Elem()) {
+		return nil, false
+	}
+	list := make([]map[string]any, val.Len())
+	for i := range list {
+		list[i] = nestValue(val.Index(i), naming)
+	}
+	return list, true
+}
+
+// mapValue converts the values of a map holding structs, pointers, slices or
+// maps, structs becoming maps so their fields are selectable. Other maps are
+// adapted by cel-go as they are.
+func mapValue(val reflect.Value, naming FieldNaming) (any, bool) {
+	typ := val.Type()
+	elem := typ.Elem()
+	switch elem.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return nil, false
+	}
+	valueType := reflect.TypeFor[any]()
+	if elem.Kind() == reflect.Struct && !isValueStructType(elem) {
+		valueType = reflect.TypeFor[map[string]any]()
+	}
+	m := reflect.MakeMapWithSize(reflect.MapOf(typ.Key(), valueType), val.Len())
+	for iter := val.MapRange(); iter.Next(); {
+		if valueType.Kind() == reflect.Map {
+			m.SetMapIndex(iter.Key(), reflect.ValueOf(nestValue(iter.Value(), naming)))
+			continue
+		}
+		v := reflect.New(valueType).Elem()
+		if c := celValue(iter.Value(), naming); c != nil {
+			v.Set(reflect.ValueOf(c))
+		}
+		m.SetMapIndex(iter.Key(), v)
+	}
+	return m.Interface(), true
+}
+
+// primitiveList returns a slice of a named type with predeclared elements, e.g.
+// type Tags []string, as the unnamed slice inferType declares as a typed list
+func primitiveList(val reflect.Value) (any, bool) {
+	typ := val.Type()
+	if typ.Kind() != reflect.Slice || typ.Name() == "" || typ.Elem().PkgPath() != "" {
+		return nil, false
+	}
+	return val.Convert(reflect.SliceOf(typ.Elem())).Interface(), true
+}
+
+// primitiveValue returns the value of a field of a predeclared type through its
+// typed accessor, which avoids the allocation of Interface for small values
+func primitiveValue(val reflect.Value) (any, bool) {
+	if val.Type().PkgPath() != "" {
+		return nil, false
+	}
+	switch val.Kind() {
+	case reflect.Bool:
+		return val.Bool(), true
+	case reflect.String:
+		return val.String(), true
+	case reflect.Int:
+		return int(val.Int()), true
+	case reflect.Int64:
+		return val.Int(), true
+	case reflect.Float64:
+		return val.Float(), true
+	}
+	return nil, false
 }
 
-// inferType maps Go values to CEL types
+// inferType maps Go values to CEL types, typeTag must be kept in sync
 func inferType(val any) *expr.Type {
 	switch val.(type) {
 	case map[string]any:
 		// if you want to expose the map itself, use this:
 		return decls.NewMapType(decls.String, decls.Dyn)
+	case []any:
+		return decls.NewListType(decls.Dyn)
+	case []map[string]any:
+		return decls.NewListType(decls.NewMapType(decls.String, decls.Dyn))
+	case []string:
+		return decls.NewListType(decls.String)
+	case []int, []int64:
+		return decls.NewListType(decls.Int)
+	case []float32, []float64:
+		return decls.NewListType(decls.Double)
+	case []bool:
+		return decls.NewListType(decls.Bool)
 	case string:
 		return decls.String
 	case int, int64:
@@ -310,15 +1213,74 @@ func inferType(val any) *expr.Type {
 		return decls.Double
 	case bool:
 		return decls.Bool
+	case Decimal:
+		return decls.NewAbstractType(decimalTypeName)
+	case time.Time:
+		return decls.Timestamp
+	case *string:
+		return decls.NewWrapperType(decls.String)
+	case *bool:
+		return decls.NewWrapperType(decls.Bool)
+	case *int, *int32, *int64:
+		return decls.NewWrapperType(decls.Int)
+	case *uint, *uint32, *uint64:
+		return decls.NewWrapperType(decls.Uint)
+	case *float32, *float64:
+		return decls.NewWrapperType(decls.Double)
 	default:
+		if t, ok := reflectType(reflect.TypeOf(val)); ok {
+			return t
+		}
 		return decls.Dyn
 	}
 }
 
+// reflectType declares the CEL type of other maps and lists from their Go
+// type, e.g. map(int, string) for map[int]string
+func reflectType(typ reflect.Type) (*expr.Type, bool) {
+	if typ == nil {
+		return nil, false
+	}
+	switch typ.Kind() {
+	case reflect.Map:
+		return decls.NewMapType(elemType(typ.Key()), elemType(typ.Elem())), true
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return nil, false
+		}
+		return decls.NewListType(elemType(typ.Elem())), true
+	}
+	return nil, false
+}
+
+// elemType returns the CEL type of map keys and values and of list elements.
+// Named types are dyn as cel-go adapts some to other types, e.g. time.Duration.
+func elemType(typ reflect.Type) *expr.Type {
+	if t, ok := reflectType(typ); ok {
+		return t
+	}
+	if typ.PkgPath() != "" {
+		return decls.Dyn
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return decls.String
+	case reflect.Bool:
+		return decls.Bool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decls.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return decls.Uint
+	case reflect.Float32, reflect.Float64:
+		return decls.Double
+	}
+	return decls.Dyn
+}
+
 // getStructName extracts the type name
 func getStructName(obj any) string {
 	t := reflect.TypeOf(obj)
-	if t.Kind() == reflect.Ptr {
+	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
 	return t.Name()
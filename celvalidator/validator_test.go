@@ -1,8 +1,12 @@
 package celvalidator
 
 import (
+	"context"
 	"os"
 	"testing"
+	"time"
+
+	"github.com/google/cel-go/checker/decls"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -122,6 +126,29 @@ var _ = Describe("Validator", func() {
 		Expect(results[2].Error).To(BeNil())
 	})
 
+	It("includes success messages when enabled", func() {
+		ruleMap := RuleSetMap{
+			"Sample": map[string][]RuleEntry{
+				"Create": {
+					{
+						Rule:           "Age > 18",
+						Enabled:        true,
+						SuccessMessage: "age requirement satisfied",
+					},
+				},
+			},
+		}
+		results, err := v.Validate(obj, GetRulesFor(obj, "Create", ruleMap), NewValidationMetadata(obj, "Create", ruleMap))
+		Expect(err).To(BeNil())
+		Expect(results[0].Message).To(BeEmpty())
+
+		v = NewValidator(WithSuccessMessages())
+		results, err = v.Validate(obj, GetRulesFor(obj, "Create", ruleMap), NewValidationMetadata(obj, "Create", ruleMap))
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[0].Message).To(Equal("age requirement satisfied"))
+	})
+
 	It("continues evaluation if AllowPartialEval is enabled", func() {
 		v := NewValidator(WithPartialEval())
 		ruleMap := RuleSetMap{
@@ -150,6 +177,50 @@ var _ = Describe("Validator", func() {
 		Expect(results[2].Passed).To(BeTrue())
 	})
 
+	It("classifies runtime errors according to the error policy", func() {
+		rules := []RuleEntry{
+			{Rule: "Age > 18", Enabled: true},
+			{Rule: "[1, 2][Age] == 1", Enabled: true},
+			{Rule: "Age > 100", Enabled: true},
+			{Rule: "UnknownField == true", Enabled: true},
+		}
+		metadata := ValidationMetadata{StructName: "Sample"}
+
+		results, err := NewValidator(WithPartialEval()).Validate(obj, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(4))
+		Expect(results[0].Outcome).To(Equal(OutcomePassed))
+		Expect(results[1].Outcome).To(Equal(OutcomeError))
+		Expect(results[2].Outcome).To(Equal(OutcomeFailed))
+		Expect(results[3].Outcome).To(Equal(OutcomeError))
+
+		results, err = NewValidator(WithPartialEval(), WithErrorPolicy(ErrorsAsFailures)).Validate(obj, rules, metadata)
+		Expect(err).To(BeNil())
+		Expect(results[1].Outcome).To(Equal(OutcomeFailed))
+		Expect(results[1].Error).To(HaveOccurred())
+		Expect(results[3].Outcome).To(Equal(OutcomeError))
+	})
+
+	It("reports compile issues and warnings with their location", func() {
+		results, err := NewValidator(WithPartialEval()).Validate(obj, []RuleEntry{
+			{Rule: "Age > 18 &&\n  Missing", Enabled: true},
+			{Rule: "[1, 2].all(Age, Age > 0)", Enabled: true},
+		}, ValidationMetadata{StructName: "Sample"})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+
+		Expect(results[0].Issues).To(ConsistOf(And(
+			HaveField("Severity", IssueError),
+			HaveField("Line", 2),
+			HaveField("Message", ContainSubstring("Missing")),
+		)))
+		Expect(results[1].Passed).To(BeTrue())
+		Expect(results[1].Issues).To(ConsistOf(And(
+			HaveField("Severity", IssueWarning),
+			HaveField("Message", ContainSubstring("shadows the field Age")),
+		)))
+	})
+
 	It("loads CRUD-specific rules", func() {
 		yaml := `User:
   Create:
@@ -242,6 +313,33 @@ var _ = Describe("Validator", func() {
 		))
 	})
 
+	It("deduplicates rules by ID before expression", func() {
+		rulesMap := RuleSetMap{"User": {
+			"Default": {
+				{ID: "adult", Rule: "Age >= 18", Enabled: true},
+				{ID: "adult-strict", Rule: "Age >= 18", Enabled: true},
+			},
+			"Create": {
+				{ID: "adult", Rule: "Age > 17", Enabled: true},
+			},
+		}}
+		rules := GetRulesFor(User{}, "Create", rulesMap)
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[0].ID).To(Equal("adult"))
+		Expect(rules[0].Rule).To(Equal("Age >= 18"))
+		Expect(rules[1].ID).To(Equal("adult-strict"))
+
+		results, err := NewValidator().Validate(User{Age: 30}, []RuleEntry{
+			{ID: "adult", Rule: "Age >= 18", Enabled: true, Then: []RuleEntry{{ID: "young", Rule: "Age < 65", Enabled: true}}},
+			{ID: "adult", Rule: "Age > 17", Enabled: true},
+		}, ValidationMetadata{StructName: "User", RuleIndex: -1})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Metadata.RuleID).To(Equal("adult"))
+		Expect(results[1].Metadata.RuleID).To(Equal("young"))
+		Expect(results[1].Metadata.ParentRuleID).To(Equal("adult"))
+	})
+
 	It("ignores disabled rules in YAML", func() {
 		yaml := `User:
   Default:
@@ -274,6 +372,136 @@ var _ = Describe("Validator", func() {
 		))
 	})
 
+	It("reports rule provenance on results", func() {
+		yaml := `Sample:
+  Create:
+    - rule: "Age > 18"
+      enabled: true
+      then:
+        - rule: "Email == ''"
+          enabled: true`
+		os.WriteFile("provenance_rules.yaml", []byte(yaml), 0644)
+		defer os.Remove("provenance_rules.yaml")
+
+		ruleMap, err := LoadRuleSetMapFromYAML("provenance_rules.yaml", WithProvenance("abc123"))
+		Expect(err).To(BeNil())
+
+		results, err := v.Validate(obj, GetRulesFor(obj, "Create", ruleMap), NewValidationMetadata(obj, "Create", ruleMap))
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Source).To(Equal(RuleSource{File: "provenance_rules.yaml", Path: "Sample.Create[0]", Line: 3, Version: "abc123"}))
+		Expect(results[1].Source).To(Equal(RuleSource{File: "provenance_rules.yaml", Path: "Sample.Create[0].then[0]", Line: 6, Version: "abc123"}))
+	})
+
+	It("reports the position of Then children in the rule tree", func() {
+		rules := []RuleEntry{
+			{Rule: "Age > 0", Enabled: true},
+			{Rule: "Age > 1", Enabled: true, Then: []RuleEntry{
+				{Rule: "Age > 2", Enabled: true},
+				{Rule: "Age > 3", Enabled: true, Then: []RuleEntry{
+					{Rule: "Age > 4", Enabled: true},
+				}},
+			}},
+		}
+		results, err := NewValidator().Validate(Sample{Age: 30}, rules, ValidationMetadata{StructName: "Sample", RuleIndex: -1})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(5))
+		var paths [][]int
+		for _, res := range results {
+			paths = append(paths, res.Metadata.IndexPath())
+		}
+		Expect(paths).To(Equal([][]int{{0}, {1}, {1, 0}, {1, 1}, {1, 1, 0}}))
+		Expect(results[4].Metadata.ParentIndexes).To(Equal([]int{1, 1}))
+		Expect(results[4].Metadata.ParentRule).To(Equal("Age > 3"))
+		Expect(results[0].Metadata.ParentIndexes).To(BeEmpty())
+	})
+
+	It("validates in one call with Check", func() {
+		yaml := `Sample:
+  Create:
+    - rule: "Age > 18"
+      enabled: true
+    - rule: "Email == ''"
+      enabled: true
+      message: "email must be empty"`
+		os.WriteFile("check_rules.yaml", []byte(yaml), 0644)
+		defer os.Remove("check_rules.yaml")
+
+		report, err := Check(obj, "Create", "check_rules.yaml")
+		Expect(err).To(BeNil())
+		Expect(report).To(HaveLen(2))
+		Expect(report[0].Passed).To(BeTrue())
+		Expect(report[1].Message).To(Equal("email must be empty"))
+
+		_, err = Check(obj, "Create", "missing_rules.yaml")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("configures and reuses the validator of the rule file with Check", func() {
+		yaml := `validator:
+  success_messages: true
+  globals:
+    minAge: 18
+Sample:
+  Create:
+    - rule: "Age > minAge"
+      enabled: true
+      success_message: "old enough"`
+		Expect(os.WriteFile("check_config_rules.yaml", []byte(yaml), 0644)).To(Succeed())
+		defer os.Remove("check_config_rules.yaml")
+
+		report, err := Check(obj, "Create", "check_config_rules.yaml")
+		Expect(err).To(BeNil())
+		Expect(report).To(HaveLen(1))
+		Expect(report[0].Passed).To(BeTrue())
+		Expect(report[0].Message).To(Equal("old enough"))
+
+		_, first, err := loadCachedRuleFile("check_config_rules.yaml")
+		Expect(err).To(BeNil())
+		_, second, err := loadCachedRuleFile("check_config_rules.yaml")
+		Expect(err).To(BeNil())
+		Expect(second).To(BeIdenticalTo(first))
+	})
+
+	Context("operation resolution", func() {
+		yaml := `User:
+  default_operation: Update
+  Create:
+    - rule: "Age > 18"
+      enabled: true
+  Update:
+    - rule: "Email != ''"
+      enabled: true`
+
+		It("uses the default_operation from the rule file", func() {
+			file, err := ParseRuleFileYAML([]byte(yaml))
+			Expect(err).To(BeNil())
+			Expect(file.Rules["User"]).To(HaveLen(2))
+
+			metadata, err := ResolveValidationMetadata(User{}, "", file.Rules,
+				WithDefaultOperations(file.DefaultOperations), WithStrictOperation())
+			Expect(err).To(BeNil())
+			Expect(metadata.Operation).To(Equal("Update"))
+		})
+
+		It("errors when ambiguous in strict mode", func() {
+			file, err := ParseRuleFileYAML([]byte(yaml))
+			Expect(err).To(BeNil())
+
+			_, err = ResolveValidationMetadata(User{}, "", file.Rules, WithStrictOperation())
+			Expect(err).To(MatchError(ErrAmbiguousOperation))
+			Expect(NewValidationMetadata(User{}, "", file.Rules, WithStrictOperation()).Operation).To(Equal("Default"))
+		})
+
+		It("keeps guessing a single operation when not strict", func() {
+			rules := RuleSetMap{"User": {"Create": {{Rule: "Age > 18", Enabled: true}}}}
+			Expect(NewValidationMetadata(User{}, "", rules).Operation).To(Equal("Create"))
+
+			_, err := ResolveValidationMetadata(User{}, "", rules, WithStrictOperation())
+			Expect(err).To(MatchError(ErrAmbiguousOperation))
+		})
+	})
+
 	Context("with nested struct fields", func() {
 		var user User
 		var validator *Validator
@@ -293,6 +521,37 @@ var _ = Describe("Validator", func() {
 			validator = NewValidator()
 		})
 
+		It("exposes the object with its native type", func() {
+			v := NewValidator(WithPartialEval(), WithNativeTypes("user"))
+			results, err := v.Validate(&user, []RuleEntry{
+				{Rule: "has(user.Address) && user.Address.City == 'Toronto'", Enabled: true},
+				{Rule: "user.Age == Age", Enabled: true},
+				{Rule: "user.Address.Zip > 10000u", Enabled: true},
+			}, ValidationMetadata{StructName: "User"})
+			Expect(err).To(BeNil())
+			Expect(results[0].Passed).To(BeTrue())
+			Expect(results[1].Passed).To(BeTrue())
+			// Fields are typed, Zip is an int
+			Expect(isCompileError(results[2])).To(BeTrue())
+		})
+
+		It("exposes the whole object as self", func() {
+			var received any
+			v := NewValidator(WithSelf(), WithResolver("checkQuota", 1, func(rc ResolverContext, args []any) (any, error) {
+				received = args[0]
+				return true, nil
+			}))
+
+			results, err := v.Validate(user, []RuleEntry{
+				{Rule: "checkQuota(self)", Enabled: true},
+				{Rule: "has(self.Address) && self.Address.City == Address.City", Enabled: true},
+				{Rule: "!has(self.Phone)", Enabled: true},
+			}, ValidationMetadata{StructName: "User"})
+			Expect(err).To(BeNil())
+			Expect(results).To(HaveEach(HaveField("Passed", true)))
+			Expect(received).To(HaveKeyWithValue("Name", "Alice"))
+		})
+
 		It("validates rules on nested struct fields", func() {
 			ruleMap := RuleSetMap{
 				"User": map[string][]RuleEntry{
@@ -350,6 +609,31 @@ var _ = Describe("Validator", func() {
 			Expect(results[1].Error).To(BeNil()) // Evaluation failed, not a runtime error
 		})
 
+		It("resolves nested fields through member selection", func() {
+			validator = NewValidator(WithNestedFields())
+			ruleMap := RuleSetMap{
+				"User": map[string][]RuleEntry{
+					"Create": {
+						{
+							Rule:    "Address.City == 'Toronto'",
+							Enabled: true,
+						},
+						{
+							Rule:    "has(Address.Zip) && !has(Address.Street)",
+							Enabled: true,
+						},
+					},
+				},
+			}
+
+			results, err := validator.Validate(user, GetRulesFor(user, "Create", ruleMap), NewValidationMetadata(user, "Create", ruleMap))
+			Expect(err).To(BeNil())
+			Expect(results).To(HaveLen(2))
+			for _, res := range results {
+				Expect(res.Passed).To(BeTrue(), "Rule failed: %s", res.Rule)
+			}
+		})
+
 		It("fails on invalid nested field if partial eval is off", func() {
 			ruleMap := RuleSetMap{
 				"User": map[string][]RuleEntry{
@@ -408,6 +692,46 @@ var _ = Describe("Validator", func() {
 		})
 	})
 
+	Context("with pointer fields", func() {
+		type Item struct {
+			Name  string
+			Price float64
+		}
+		type Config struct {
+			Limit int
+		}
+		type Order struct {
+			Items  []*Item
+			Config **Config
+			Notes  *string
+		}
+
+		It("follows pointer chains and exposes pointer slices as lists", func() {
+			cfg := &Config{Limit: 3}
+			order := &Order{
+				Items:  []*Item{{Name: "a", Price: 10}, {Name: "b", Price: 5}},
+				Config: &cfg,
+			}
+			ruleMap := RuleSetMap{
+				"Order": map[string][]RuleEntry{
+					"Create": {
+						{Rule: "Items.all(i, i.Price > 0.0)", Enabled: true},
+						{Rule: "size(Items) <= Config.Limit", Enabled: true},
+						{Rule: "Items.exists(i, i.Name == 'b')", Enabled: true},
+						{Rule: "Notes == null", Enabled: true},
+					},
+				},
+			}
+
+			results, err := v.Validate(order, GetRulesFor(order, "Create", ruleMap), NewValidationMetadata(order, "Create", ruleMap))
+			Expect(err).To(BeNil())
+			Expect(results).To(HaveLen(4))
+			for _, res := range results {
+				Expect(res.Passed).To(BeTrue(), "Rule failed: %s", res.Rule)
+			}
+		})
+	})
+
 	Context("Nested then rules", func() {
 		type Sample struct {
 			Name  string
@@ -469,11 +793,242 @@ var _ = Describe("Validator", func() {
 			Expect(result).To(ContainElement(HaveField("Rule", "Count < 100")))
 		})
 
+		It("should apply operation overrides to Then children by ID", func() {
+			rules["Sample"]["Default"][0].Then[0].ID = "name-set"
+			rules["Sample"]["Default"][0].Then[1].ID = "name-not-test"
+			rules["Sample"]["Update"] = []RuleEntry{
+				{Override: "name-set", Enabled: false},
+				{Override: "name-not-test", Enabled: true, FailureMessage: "name cannot be test"},
+			}
+
+			result := GetRulesFor(Sample{}, "Update", rules)
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Then).To(HaveLen(1))
+			Expect(result[0].Then[0].Rule).To(Equal("Name != 'test'"))
+			Expect(result[0].Then[0].FailureMessage).To(Equal("name cannot be test"))
+
+			result = GetRulesFor(Sample{}, "Create", rules)
+			Expect(result[0].Then).To(HaveLen(1))
+			Expect(result[0].Then[0].Rule).To(Equal("Name != ''"))
+		})
+
+		It("should keep the child enabled when a YAML override only sets its message", func() {
+			file, err := ParseRuleFileYAML([]byte(`Sample:
+  Default:
+    - rule: "Count > 0"
+      enabled: true
+      then:
+        - id: name-set
+          rule: "Name != ''"
+          enabled: true
+  Update:
+    - override: name-set
+      message: name is required
+`))
+			Expect(err).To(BeNil())
+
+			result := GetRulesFor(Sample{}, "Update", file.Rules)
+			Expect(result[0].Then).To(HaveLen(1))
+			Expect(result[0].Then[0].FailureMessage).To(Equal("name is required"))
+
+			results, err := NewValidator().Validate(Sample{Count: 1}, result, ValidationMetadata{StructName: "Sample"})
+			Expect(err).To(BeNil())
+			Expect(results).To(HaveLen(2))
+			Expect(results[1].Message).To(Equal("name is required"))
+		})
+
 		It("should return empty if all rules are disabled", func() {
 			rules["Sample"]["Default"][0].Enabled = false
 			rules["Sample"]["Default"][1].Enabled = false
 			result := GetRulesFor(Sample{}, "Default", rules)
 			Expect(result).To(BeEmpty())
 		})
+
+		It("should distinguish missing types and operations in strict mode", func() {
+			_, err := GetRulesForStrict(struct{ Other int }{}, "Default", rules)
+			Expect(err).To(MatchError(ErrNoRulesForType))
+
+			rules["Sample"]["Default"][0].Enabled = false
+			_, err = GetRulesForStrict(Sample{}, "Default", rules)
+			Expect(err).To(MatchError(ErrNoRulesForOperation))
+
+			result, err := GetRulesForStrict(Sample{}, "Create", rules)
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+		})
+	})
+})
+
+var _ = Describe("Context cancellation", func() {
+	md := ValidationMetadata{StructName: "Sample"}
+
+	It("returns partial results and the context error", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := NewValidator().ValidateContext(ctx, Sample{}, []RuleEntry{{Rule: "Age == 0", Enabled: true}}, md)
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(results).To(BeEmpty())
+	})
+
+	It("interrupts long running comprehensions at the deadline", func() {
+		// 10^8 iterations, cel-go checks for interrupts in every comprehension
+		// and an outer one ends once the inner ones it starts end
+		lists := map[string][]any{"outer": make([]any, 100), "inner": make([]any, 1000000)}
+		for _, list := range lists {
+			for i := range list {
+				list[i] = i
+			}
+		}
+		slowRule := "ext.outer.all(a, ext.inner.all(b, a + b >= 0))"
+		rules := []RuleEntry{
+			{Rule: "Age == 0", Enabled: true},
+			{Rule: slowRule, Enabled: true},
+			{Rule: "Email == ''", Enabled: true},
+		}
+		v := NewValidator(WithVariableResolver("ext", VariableResolverFunc(func(rc ResolverContext, name string) (any, error) {
+			return lists[name], nil
+		})))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		results, err := v.ValidateContext(ctx, Sample{}, rules, md)
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[1].Error).To(MatchError(context.DeadlineExceeded))
+	})
+})
+
+type LineItem struct {
+	SKU   string
+	Price float64
+}
+
+type Tags []string
+
+type Order struct {
+	Tags   Tags
+	Counts []int
+	Prices []float64
+	Flags  []bool
+	Items  []LineItem
+}
+
+var _ = Describe("Slice fields", func() {
+	md := ValidationMetadata{StructName: "Order"}
+	order := Order{
+		Tags:   Tags{"vip", "eu"},
+		Counts: []int{1, 2},
+		Prices: []float64{9.5},
+		Flags:  []bool{true},
+		Items:  []LineItem{{SKU: "a", Price: 1}, {SKU: "b", Price: 2}},
+	}
+
+	It("declares slices as typed lists", func() {
+		fields := NewValidator().objectFields(order)
+		Expect(inferType(fields["Tags"])).To(Equal(decls.NewListType(decls.String)))
+		Expect(inferType(fields["Counts"])).To(Equal(decls.NewListType(decls.Int)))
+		Expect(inferType(fields["Prices"])).To(Equal(decls.NewListType(decls.Double)))
+		Expect(inferType(fields["Flags"])).To(Equal(decls.NewListType(decls.Bool)))
+		Expect(inferType(fields["Items"])).To(Equal(decls.NewListType(decls.NewMapType(decls.String, decls.Dyn))))
+	})
+
+	It("evaluates list macros on slices", func() {
+		rules := []RuleEntry{
+			{Rule: "Tags.exists(t, t == 'vip')", Enabled: true},
+			{Rule: "Counts.all(c, c > 0) && Prices.exists(p, p > 9.0)", Enabled: true},
+			{Rule: "Items.map(i, i.Price).exists(p, p > 1.5) && Items.all(i, i.SKU != '')", Enabled: true},
+			{Rule: "Flags.all(f, f)", Enabled: true},
+		}
+		results, err := NewValidator().Validate(order, rules, md)
+		Expect(err).To(BeNil())
+		for _, result := range results {
+			Expect(result.Error).To(BeNil())
+			Expect(result.Passed).To(BeTrue(), result.Rule)
+		}
+	})
+
+	It("type checks list elements", func() {
+		_, err := NewValidator().Validate(order, []RuleEntry{{Rule: "Tags.exists(t, t > 1)", Enabled: true}}, md)
+		Expect(err).To(MatchError(ContainSubstring("no matching overload")))
+	})
+})
+
+type Quota struct {
+	Limit int
+	Zones []string
+}
+
+type Account struct {
+	Scores   map[string]int
+	Features map[string]bool
+	Quotas   map[string]Quota
+	Levels   map[int]string
+	Pending  map[string]*Quota
+}
+
+var _ = Describe("Map fields", func() {
+	md := ValidationMetadata{StructName: "Account"}
+	account := Account{
+		Scores:   map[string]int{"a": 3},
+		Features: map[string]bool{"beta": true},
+		Quotas:   map[string]Quota{"cpu": {Limit: 4, Zones: []string{"eu"}}},
+		Levels:   map[int]string{1: "gold"},
+		Pending:  map[string]*Quota{"gpu": nil, "mem": {Limit: 2}},
+	}
+
+	It("declares maps with their key and value types", func() {
+		fields := NewValidator().objectFields(account)
+		Expect(inferType(fields["Scores"])).To(Equal(decls.NewMapType(decls.String, decls.Int)))
+		Expect(inferType(fields["Features"])).To(Equal(decls.NewMapType(decls.String, decls.Bool)))
+		Expect(inferType(fields["Quotas"])).To(Equal(decls.NewMapType(decls.String, decls.NewMapType(decls.String, decls.Dyn))))
+		Expect(inferType(fields["Levels"])).To(Equal(decls.NewMapType(decls.Int, decls.String)))
+		Expect(inferType(fields["Pending"])).To(Equal(decls.NewMapType(decls.String, decls.Dyn)))
+	})
+
+	It("selects the fields of struct values", func() {
+		rules := []RuleEntry{
+			{Rule: "Scores['a'] > 2 && Scores.all(k, Scores[k] > 0)", Enabled: true},
+			{Rule: "Features['beta']", Enabled: true},
+			{Rule: "Quotas['cpu'].Limit == 4 && 'eu' in Quotas['cpu'].Zones", Enabled: true},
+			{Rule: "Levels[1] == 'gold'", Enabled: true},
+			{Rule: "Pending['gpu'] == null && Pending['mem'].Limit == 2", Enabled: true},
+		}
+		results, err := NewValidator().Validate(account, rules, md)
+		Expect(err).To(BeNil())
+		for _, result := range results {
+			Expect(result.Error).To(BeNil())
+			Expect(result.Passed).To(BeTrue(), result.Rule)
+		}
+	})
+
+	It("type checks map values", func() {
+		_, err := NewValidator().Validate(account, []RuleEntry{{Rule: "Scores['a'] == 'x'", Enabled: true}}, md)
+		Expect(err).To(MatchError(ContainSubstring("no matching overload")))
+	})
+
+	It("converts nested maps and structs in map values recursively", func() {
+		type Site struct {
+			Office Address
+			Quotas map[string]Quota
+		}
+		type Fleet struct {
+			Sites map[string]map[string]Site
+		}
+		fleet := Fleet{Sites: map[string]map[string]Site{
+			"eu": {"hq": {Office: Address{City: "Lisbon"}, Quotas: map[string]Quota{"cpu": {Limit: 8}}}},
+		}}
+		for _, v := range []*Validator{NewValidator(), NewValidator(WithNestedFields())} {
+			results, err := v.Validate(fleet, []RuleEntry{
+				{Rule: "Sites['eu']['hq'].Office.City == 'Lisbon'", Enabled: true},
+				{Rule: "Sites['eu'].all(k, Sites['eu'][k].Quotas['cpu'].Limit <= 8)", Enabled: true},
+			}, ValidationMetadata{StructName: "Fleet"})
+			Expect(err).To(BeNil())
+			for _, result := range results {
+				Expect(result.Error).To(BeNil())
+				Expect(result.Passed).To(BeTrue(), result.Rule)
+			}
+		}
 	})
 })
@@ -0,0 +1,187 @@
+package celvalidator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+)
+
+// versionsKey is the reserved per-struct key mapping versions of the struct
+// onto it, so its rules apply to every version during a migration
+const versionsKey = "versions"
+
+// VersionMapping validates a version of a struct against the rules of its
+// logical struct, e.g. UserV2 against the rules of User
+type VersionMapping struct {
+	// Logical is the struct name whose rules apply to the version
+	Logical string
+	// Fields maps logical field names, as used in rules, to the fields of the
+	// version, e.g. Name: FullName. Dotted paths map nested fields, and fields
+	// without a mapping keep their name.
+	Fields map[string]string
+}
+
+// WithVersionMappings validates the struct versions named by the keys of
+// versions with their fields renamed to the logical fields, e.g. with the
+// Versions of a RuleFile. Dotted paths are only mapped with flattened fields.
+func WithVersionMappings(versions map[string]VersionMapping) ValidatorOption {
+	return func(v *Validator) {
+		v.versions = versions
+	}
+}
+
+// WithVersions resolves the operation of struct versions from the rules and
+// default operation of their logical struct
+func WithVersions(versions map[string]VersionMapping) MetadataOption {
+	return func(c *metadataConfig) {
+		c.versions = versions
+	}
+}
+
+// logicalName returns the struct name whose rules apply to structName
+func logicalName(structName string, versions map[string]VersionMapping) string {
+	if m, ok := versions[structName]; ok && m.Logical != "" {
+		return m.Logical
+	}
+	return structName
+}
+
+// mapVersionFields renames the fields of a struct version to their logical names
+func (v *Validator) mapVersionFields(obj any, fields map[string]any) {
+	m, ok := v.versions[getStructName(obj)]
+	if !ok {
+		return
+	}
+
+	// Every mapping matching a field renames it, so overlapping mappings such
+	// as Contact and Contact.Mail both apply regardless of map order
+	renamed := make(map[string]any, len(fields))
+	for name, val := range fields {
+		mapped := false
+		for logical, field := range m.Fields {
+			if name == field {
+				renamed[logical] = val
+				mapped = true
+			} else if rest, ok := strings.CutPrefix(name, field+"."); ok {
+				renamed[logical+"."+rest] = val
+				mapped = true
+			}
+		}
+		if mapped {
+			delete(fields, name)
+		}
+	}
+	for name, val := range renamed {
+		fields[name] = val
+	}
+}
+
+// UnmappedFields returns the fields referenced by rules, including Then
+// children, that obj does not provide once its version is mapped, e.g. a
+// logical field removed from the version without a mapping
+func (v *Validator) UnmappedFields(obj any, rules []RuleEntry) []string {
+	fields := v.objectFields(obj)
+	v.addNativeVariable(fields, obj)
+	for _, r := range v.variableResolvers {
+		fields[r.prefix] = nil
+	}
+	for name := range v.globals {
+		fields[name] = nil
+	}
+	if v.clock != nil {
+		fields[nowVariable] = nil
+	}
+
+	seen := map[string]bool{}
+	var unmapped []string
+	for _, entry := range flattenRuleTree(rules) {
+		for _, ref := range referencedFields(entry.Rule) {
+			if seen[ref] || providesField(fields, ref) {
+				continue
+			}
+			seen[ref] = true
+			unmapped = append(unmapped, ref)
+		}
+	}
+	sort.Strings(unmapped)
+	return unmapped
+}
+
+// providesField reports whether a variable declares ref, or a parent or child of it
+func providesField(fields map[string]any, ref string) bool {
+	for name := range fields {
+		if name == ref || strings.HasPrefix(ref, name+".") || strings.HasPrefix(name, ref+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// referencedFields returns the variables referenced by a rule as dotted paths,
+// nil when it does not parse
+func referencedFields(rule string) []string {
+	env, err := cel.NewEnv(parsePresenceMacro())
+	if err != nil {
+		return nil
+	}
+	parsed, iss := env.Parse(rule)
+	if iss != nil && iss.Err() != nil {
+		return nil
+	}
+	var refs []string
+	collectFields(parsed.NativeRep().Expr(), map[string]bool{}, &refs)
+	return refs
+}
+
+// collectFields appends the field paths of e, skipping comprehension variables in scope
+func collectFields(e ast.Expr, scope map[string]bool, refs *[]string) {
+	if path, ok := fieldPath(e); ok {
+		if root, _, _ := strings.Cut(path, "."); !scope[root] {
+			*refs = append(*refs, path)
+		}
+		return
+	}
+
+	switch e.Kind() {
+	case ast.SelectKind:
+		collectFields(e.AsSelect().Operand(), scope, refs)
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.IsMemberFunction() {
+			collectFields(call.Target(), scope, refs)
+		}
+		for _, arg := range call.Args() {
+			collectFields(arg, scope, refs)
+		}
+	case ast.ListKind:
+		for _, elem := range e.AsList().Elements() {
+			collectFields(elem, scope, refs)
+		}
+	case ast.MapKind:
+		for _, entry := range e.AsMap().Entries() {
+			collectFields(entry.AsMapEntry().Key(), scope, refs)
+			collectFields(entry.AsMapEntry().Value(), scope, refs)
+		}
+	case ast.StructKind:
+		for _, field := range e.AsStruct().Fields() {
+			collectFields(field.AsStructField().Value(), scope, refs)
+		}
+	case ast.ComprehensionKind:
+		comp := e.AsComprehension()
+		collectFields(comp.IterRange(), scope, refs)
+		inner := make(map[string]bool, len(scope)+3)
+		for name := range scope {
+			inner[name] = true
+		}
+		inner[comp.IterVar()] = true
+		inner[comp.AccuVar()] = true
+		if comp.HasIterVar2() {
+			inner[comp.IterVar2()] = true
+		}
+		for _, child := range []ast.Expr{comp.AccuInit(), comp.LoopCondition(), comp.LoopStep(), comp.Result()} {
+			collectFields(child, inner, refs)
+		}
+	}
+}
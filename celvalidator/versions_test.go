@@ -0,0 +1,83 @@
+package celvalidator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type ContactV2 struct {
+	Mail string
+	Town string
+}
+
+type UserV2 struct {
+	FullName string
+	Age      int
+	Contact  ContactV2
+}
+
+var _ = Describe("Struct versions", func() {
+	ruleFile := []byte(`
+User:
+  default_operation: Create
+  versions:
+    UserV2:
+      Name: FullName
+      Email: Contact.Mail
+      Address: Contact
+  Create:
+    - rule: Name != '' && Age >= 18
+      enabled: true
+    - rule: Email.endsWith('@example.com')
+      enabled: true
+    - rule: Address.Town == 'Oslo'
+      enabled: true
+`)
+	user := UserV2{FullName: "Ann", Age: 30, Contact: ContactV2{Mail: "ann@example.com", Town: "Oslo"}}
+
+	It("parses version mappings", func() {
+		file, err := ParseRuleFileYAML(ruleFile)
+		Expect(err).To(BeNil())
+		Expect(file.Versions).To(Equal(map[string]VersionMapping{
+			"UserV2": {Logical: "User", Fields: map[string]string{"Name": "FullName", "Email": "Contact.Mail", "Address": "Contact"}},
+		}))
+		Expect(file.Rules).NotTo(HaveKey("UserV2"))
+	})
+
+	It("validates versions with the rules of the logical struct", func() {
+		file, err := ParseRuleFileYAML(ruleFile)
+		Expect(err).To(BeNil())
+		snapshot := NewRuleProvider(nil).Swap(file)
+
+		metadata := snapshot.Metadata(user, "")
+		Expect(metadata.StructName).To(Equal("UserV2"))
+		Expect(metadata.Operation).To(Equal("Create"))
+
+		v := NewValidator(WithPartialEval(), WithVersionMappings(snapshot.Versions()))
+		results, err := v.ValidateSnapshot(user, "Create", snapshot)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(3))
+		for _, result := range results {
+			Expect(result.Error).To(BeNil())
+			Expect(result.Passed).To(BeTrue())
+		}
+
+		results, err = v.ValidateSnapshot(User{Name: "Bob", Age: 20, Email: "bob@example.com"}, "Create", snapshot)
+		Expect(err).To(BeNil())
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[2].Passed).To(BeFalse())
+	})
+
+	It("reports logical fields the version does not provide", func() {
+		v := NewValidator(WithVersionMappings(map[string]VersionMapping{
+			"UserV2": {Logical: "User", Fields: map[string]string{"Name": "FullName"}},
+		}))
+		rules := []RuleEntry{
+			{Rule: "Name != '' && IsActive", Enabled: true, Then: []RuleEntry{
+				{Rule: "Address.City == 'Oslo' && Contact.Mail != ''", Enabled: true},
+			}},
+			{Rule: "[1, 2].all(x, x < Age) && Email.size() > 0", Enabled: true},
+		}
+		Expect(v.UnmappedFields(user, rules)).To(Equal([]string{"Address.City", "Email", "IsActive"}))
+	})
+})
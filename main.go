@@ -28,18 +28,7 @@ func main() {
 		Address:  Address{City: "LA"},
 	}
 
-	rulesMap, err := celvalidator.LoadRuleSetMapFromYAML("./assets/rules.yaml")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	createRules := celvalidator.GetRulesFor(user, "Create", rulesMap)
-	if len(createRules) == 0 {
-		log.Println("No rules found for User.Create")
-	}
-
-	validator := celvalidator.NewValidator(celvalidator.WithPartialEval())
-	results, err := validator.Validate(user, createRules, celvalidator.NewValidationMetadata(user, "Create", rulesMap))
+	results, err := celvalidator.Check(user, "Create", "./assets/rules.yaml")
 	if err != nil {
 		log.Fatal("Validation error:", err)
 	}